@@ -0,0 +1,139 @@
+package main
+
+import (
+	"log"
+	"os"
+
+	"recommender/config"
+	repository "recommender/internal/adapters/repositories"
+	"recommender/internal/core/ports"
+	"recommender/internal/core/services"
+
+	"gorm.io/gorm"
+)
+
+// app agrupa la configuración y las dependencias compartidas por los subcomandos.
+// Cada subcomando agrega encima lo que solo él necesita (handlers, hubs, workers...).
+type app struct {
+	db              *gorm.DB
+	stockRepo       ports.StockRepository
+	companyService  *services.CompanyService
+	snapshotService *services.SnapshotService
+	stockService    *services.StockService
+}
+
+// newApp conecta la base (migrando salvo AUTO_MIGRATE=false) y arma el StockService con
+// la configuración de entorno. apiClient puede ser nil si el subcomando no importa de la API.
+func newApp(apiClient ports.StockAPIClient) *app {
+	db := config.InitDB()
+
+	stockRepo := repository.NewCockroachStockRepository(db)
+	companyService := services.NewCompanyService(repository.NewCockroachCompanyRepository(db))
+	defaults := config.LoadRecommendationDefaults(services.DefaultRecommendationParams())
+	snapshotService := services.NewSnapshotService(repository.NewCockroachSnapshotRepository(db)).WithDefaults(defaults)
+	stockService := services.NewStockService(stockRepo, apiClient).
+		WithCompanyService(companyService).
+		WithSnapshots(snapshotService).
+		WithRecommendationDefaults(defaults).
+		WithDecay(config.LoadDecay()).
+		WithPageFailurePolicy(config.LoadPageFailurePolicy())
+	if path := os.Getenv("RATING_MAPPING_FILE"); path != "" {
+		stockService.WithRatingNormalizer(loadRatingNormalizer(path))
+	}
+
+	// Cargar el archivo de referencia de compañías si está configurado
+	if path := os.Getenv("COMPANY_REFERENCE_FILE"); path != "" {
+		loadCompanyReference(companyService, path)
+	}
+
+	return &app{
+		db:              db,
+		stockRepo:       stockRepo,
+		companyService:  companyService,
+		snapshotService: snapshotService,
+		stockService:    stockService,
+	}
+}
+
+// brokerageAccuracy carga los precios configurados y, con USE_MEASURED_BROKERAGE_WEIGHTS,
+// reemplaza los pesos estáticos de la estrategia por defecto. Los pesos medidos se
+// devuelven para que un proceso largo los recalcule; son nil si no están activados.
+func (a *app) brokerageAccuracy() (*services.BrokerageAccuracyService, *services.RefreshingBrokerageWeights) {
+	priceRepo := repository.NewCockroachPriceRepository(a.db)
+	if path := os.Getenv("PRICE_FILE"); path != "" {
+		loadPrices(services.NewPriceService(priceRepo), path)
+	}
+	accuracyService := services.NewBrokerageAccuracyService(a.stockRepo, priceRepo,
+		config.EnvInt("BROKERAGE_ACCURACY_HORIZON_DAYS", 30),
+		config.EnvInt("BROKERAGE_ACCURACY_MIN_EVENTS", 5))
+	if os.Getenv("USE_MEASURED_BROKERAGE_WEIGHTS") != "true" {
+		return accuracyService, nil
+	}
+	return accuracyService, useMeasuredBrokerageWeights(a.stockService, accuracyService)
+}
+
+func loadCompanyReference(companyService *services.CompanyService, path string) {
+	file, err := os.Open(path)
+	if err != nil {
+		log.Println("⚠ No se pudo abrir el archivo de referencia de compañías:", err)
+		return
+	}
+	defer file.Close()
+
+	count, err := companyService.ImportReferenceCSV(file)
+	if err != nil {
+		log.Println("⚠ Error importando referencia de compañías:", err)
+	}
+	log.Printf("✅ %d compañías cargadas desde %s", count, path)
+}
+
+// loadRatingNormalizer combina el mapeo por defecto con el archivo configurado.
+func loadRatingNormalizer(path string) *services.RatingNormalizer {
+	mapping := services.DefaultRatingMapping()
+
+	file, err := os.Open(path)
+	if err != nil {
+		log.Println("⚠ No se pudo abrir el mapeo de ratings, usando el mapeo por defecto:", err)
+		return services.NewRatingNormalizer(mapping)
+	}
+	defer file.Close()
+
+	custom, err := services.ParseRatingMapping(file)
+	if err != nil {
+		log.Println("⚠ Mapeo de ratings inválido, usando el mapeo por defecto:", err)
+		return services.NewRatingNormalizer(mapping)
+	}
+
+	log.Printf("✅ %d etiquetas de rating cargadas desde %s", len(custom), path)
+	return services.NewRatingNormalizer(services.MergeRatingMappings(mapping, custom))
+}
+
+func loadPrices(priceService *services.PriceService, path string) {
+	file, err := os.Open(path)
+	if err != nil {
+		log.Println("⚠ No se pudo abrir el archivo de precios:", err)
+		return
+	}
+	defer file.Close()
+
+	count, err := priceService.ImportCSV(file)
+	if err != nil {
+		log.Println("⚠ Error importando precios:", err)
+		return
+	}
+	log.Printf("✅ %d cierres cargados desde %s", count, path)
+}
+
+// useMeasuredBrokerageWeights reemplaza los pesos estáticos por los medidos en el último
+// año. Si la primera medición falla se usan los pesos estáticos hasta el próximo Refresh.
+func useMeasuredBrokerageWeights(stockService *services.StockService, accuracyService *services.BrokerageAccuracyService) *services.RefreshingBrokerageWeights {
+	weights := services.NewRefreshingBrokerageWeights(accuracyService)
+	stockService.WithBrokerageWeights(weights)
+	count, err := weights.Refresh()
+	if err != nil {
+		log.Println("⚠ No se pudo medir la precisión de las corredoras, usando pesos estáticos:", err)
+		return weights
+	}
+	log.Printf("✅ Pesos medidos para %d corredoras", count)
+	return weights
+}
@@ -0,0 +1,137 @@
+package main
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"recommender/internal/adapters/prices"
+	repository "recommender/internal/adapters/repositories"
+	"recommender/internal/core/domain"
+	"recommender/internal/core/ports"
+	"recommender/internal/core/services"
+)
+
+// runBacktest implementa `recommender backtest [flags]`: reproduce los eventos guardados
+// y compara las estrategias indicadas, escribiendo el resultado en JSON o CSV.
+func runBacktest(args []string) error {
+	fs := flag.NewFlagSet("backtest", flag.ContinueOnError)
+	strategies := fs.String("strategies", services.DefaultStrategy, "estrategias a comparar, separadas por coma")
+	startFlag := fs.String("start", "", "fecha inicial (YYYY-MM-DD)")
+	endFlag := fs.String("end", "", "fecha final (YYYY-MM-DD), por defecto hoy")
+	step := fs.Int("step-days", 1, "días entre rebalanceos")
+	horizon := fs.Int("horizon-days", 30, "horizonte del retorno futuro de cada pick")
+	top := fs.Int("top", 5, "cantidad de picks por fecha")
+	lookback := fs.Int("lookback-days", 30, "ventana de eventos considerada en cada fecha")
+	groupBy := fs.String("group-by", domain.GroupByTicker, "ticker o event")
+	priceFile := fs.String("prices", "", "CSV de cierres (ticker,date,close); sin él se usan los precios guardados")
+	format := fs.String("format", "json", "json o csv")
+	output := fs.String("output", "", "archivo de salida, por defecto stdout")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	start, err := time.Parse("2006-01-02", *startFlag)
+	if err != nil {
+		return fmt.Errorf("backtest: --start inválido: %w", err)
+	}
+	end := time.Now().UTC().Truncate(24 * time.Hour)
+	if *endFlag != "" {
+		if end, err = time.Parse("2006-01-02", *endFlag); err != nil {
+			return fmt.Errorf("backtest: --end inválido: %w", err)
+		}
+	}
+	if *format != "json" && *format != "csv" {
+		return fmt.Errorf("backtest: formato desconocido '%s'", *format)
+	}
+
+	a := newApp(nil)
+	stockRepo := a.stockRepo
+	scorers := a.stockService.ScorerRegistry()
+
+	var feed ports.PriceFeed = repository.NewCockroachPriceRepository(a.db)
+	if *priceFile != "" {
+		file, err := os.Open(*priceFile)
+		if err != nil {
+			return fmt.Errorf("backtest: no se pudo abrir el archivo de precios: %w", err)
+		}
+		bars, err := services.ParsePriceCSV(file)
+		file.Close()
+		if err != nil {
+			return err
+		}
+		feed = prices.NewMemoryPriceFeed(bars)
+	}
+
+	var names []string
+	for _, name := range strings.Split(*strategies, ",") {
+		if name = strings.TrimSpace(name); name != "" {
+			names = append(names, name)
+		}
+	}
+
+	report, err := services.NewBacktestService(stockRepo, feed, scorers).Run(domain.BacktestConfig{
+		Start:        start,
+		End:          end,
+		StepDays:     *step,
+		HorizonDays:  *horizon,
+		TopN:         *top,
+		LookbackDays: *lookback,
+		GroupBy:      *groupBy,
+		Strategies:   names,
+	})
+	if err != nil {
+		return err
+	}
+
+	var w io.Writer = os.Stdout
+	if *output != "" {
+		file, err := os.Create(*output)
+		if err != nil {
+			return err
+		}
+		defer file.Close()
+		w = file
+	}
+
+	if *format == "csv" {
+		err = writeBacktestCSV(w, report)
+	} else {
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		err = encoder.Encode(report)
+	}
+	if err == nil {
+		log.Printf("✅ Backtest de %d estrategias completado", len(report.Results))
+	}
+	return err
+}
+
+// writeBacktestCSV escribe una fila por estrategia para compararlas lado a lado.
+func writeBacktestCSV(w io.Writer, report *domain.BacktestReport) error {
+	writer := csv.NewWriter(w)
+	writer.Write([]string{"strategy", "scorer_version", "periods", "picks", "skipped_picks",
+		"hit_rate", "avg_return", "max_drawdown", "turnover"})
+	for _, r := range report.Results {
+		writer.Write([]string{
+			r.Strategy,
+			r.ScorerVersion,
+			strconv.Itoa(r.Periods),
+			strconv.Itoa(r.Picks),
+			strconv.Itoa(r.SkippedPicks),
+			strconv.FormatFloat(r.HitRate, 'f', -1, 64),
+			strconv.FormatFloat(r.AvgReturn, 'f', -1, 64),
+			strconv.FormatFloat(r.MaxDrawdown, 'f', -1, 64),
+			strconv.FormatFloat(r.Turnover, 'f', -1, 64),
+		})
+	}
+	writer.Flush()
+	return writer.Error()
+}
@@ -0,0 +1,115 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"recommender/internal/adapters/export"
+	"recommender/internal/core/domain"
+)
+
+// runExport implementa `recommender export <stocks|history|recommendations> [flags]` con
+// los mismos formatos que la API. Los stocks se escriben a medida que se leen.
+func runExport(args []string) error {
+	if len(args) == 0 || strings.HasPrefix(args[0], "-") {
+		return fmt.Errorf("export: indique qué exportar: stocks, history o recommendations")
+	}
+	kind := args[0]
+
+	fs := flag.NewFlagSet("export "+kind, flag.ContinueOnError)
+	format := fs.String("format", "", "csv o xlsx; por defecto se deduce de --output o csv")
+	output := fs.String("output", "", "archivo de salida, por defecto stdout")
+	var (
+		params         *domain.RecommendationParams
+		limit, offset  *int
+		ticker, action *string
+	)
+	switch kind {
+	case "stocks", "history":
+		limit = fs.Int("limit", 0, "máximo de eventos; 0 exporta todos")
+		offset = fs.Int("offset", 0, "eventos a saltar")
+		if kind == "history" {
+			ticker = fs.String("ticker", "", "ticker a exportar (obligatorio)")
+		} else {
+			action = fs.String("action", "", "tipos de acción separados por coma")
+		}
+	case "recommendations":
+		params = recommendationFlags(fs)
+	default:
+		return fmt.Errorf("export: no se puede exportar '%s'", kind)
+	}
+	if err := fs.Parse(args[1:]); err != nil {
+		return err
+	}
+
+	if *format == "" {
+		*format = strings.TrimPrefix(strings.ToLower(filepath.Ext(*output)), ".")
+		if *format != export.FormatXLSX {
+			*format = export.FormatCSV
+		}
+	}
+	if *format != export.FormatCSV && *format != export.FormatXLSX {
+		return fmt.Errorf("export: formato desconocido '%s'", *format)
+	}
+	var filter domain.StockFilter
+	if action != nil && *action != "" {
+		for _, raw := range strings.Split(*action, ",") {
+			actionType := domain.ActionType(strings.TrimSpace(raw))
+			if !actionType.Valid() {
+				return fmt.Errorf("export: acción desconocida '%s'", raw)
+			}
+			filter.ActionTypes = append(filter.ActionTypes, actionType)
+		}
+	}
+	if ticker != nil && *ticker == "" {
+		return fmt.Errorf("export: falta --ticker")
+	}
+
+	var w io.Writer = os.Stdout
+	if *output != "" {
+		file, err := os.Create(*output)
+		if err != nil {
+			return err
+		}
+		defer file.Close()
+		w = file
+	}
+
+	a := newApp(nil)
+	writer := export.NewWriter(*format, w, kind)
+	var err error
+	switch kind {
+	case "stocks":
+		if err = writer.WriteRow(export.StockHeader); err == nil {
+			err = a.stockService.StreamStocks(filter, *limit, *offset, func(stock domain.Stock) error {
+				return writer.WriteRow(export.StockRow(stock))
+			})
+		}
+	case "history":
+		if err = writer.WriteRow(export.StockHeader); err == nil {
+			err = a.stockService.StreamStockHistory(*ticker, *limit, *offset, func(stock domain.Stock) error {
+				return writer.WriteRow(export.StockRow(stock))
+			})
+		}
+	case "recommendations":
+		a.brokerageAccuracy()
+		var list *domain.RecommendationList
+		if list, err = a.stockService.GetTopRecommendedStocks(*params); err == nil {
+			err = writer.WriteRow(export.RecommendationHeader)
+			for _, rec := range list.Items {
+				if err != nil {
+					break
+				}
+				err = writer.WriteRow(export.RecommendationRow(rec))
+			}
+		}
+	}
+	if err != nil {
+		return err
+	}
+	return writer.Close()
+}
@@ -0,0 +1,43 @@
+package main
+
+import (
+	"flag"
+	"log"
+	"net/http"
+	"os"
+
+	"recommender/internal/adapters/fakeupstream"
+)
+
+// runFakeUpstream implementa `recommender fake-upstream`: una API externa falsa con
+// los eventos grabados, para desarrollar y probar la importación sin credenciales.
+func runFakeUpstream(args []string) error {
+	fs := flag.NewFlagSet("fake-upstream", flag.ContinueOnError)
+	port := fs.String("port", "8090", "puerto HTTP")
+	apiKey := fs.String("api-key", "dev-key", "token Bearer que se exige a los clientes")
+	pageSize := fs.Int("page-size", 10, "eventos por página")
+	fixtures := fs.String("fixtures", "", "carpeta con *.json propios; vacío usa los embebidos")
+	seed := fs.Uint64("seed", 1, "semilla para que las fallas aleatorias sean reproducibles")
+	var faults fakeupstream.Faults
+	fs.IntVar(&faults.LatencyMS, "latency-ms", 0, "demora de cada respuesta")
+	fs.Float64Var(&faults.ErrorRate, "error-rate", 0, "fracción de peticiones que responden 5xx")
+	fs.IntVar(&faults.ErrorStatus, "error-status", http.StatusInternalServerError, "código de las respuestas con error")
+	fs.Float64Var(&faults.MalformedPriceRate, "malformed-price-rate", 0, "fracción de eventos con precio ilegible")
+	fs.Float64Var(&faults.BadTimeRate, "bad-time-rate", 0, "fracción de eventos con fecha inválida")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	opts := fakeupstream.Options{APIKey: *apiKey, PageSize: *pageSize, Faults: faults, Seed: *seed}
+	if *fixtures != "" {
+		opts.Fixtures = os.DirFS(*fixtures)
+	}
+	server, err := fakeupstream.NewServer(opts)
+	if err != nil {
+		return err
+	}
+
+	log.Printf("🧪 API externa falsa con %d eventos en el puerto %s", server.Len(), *port)
+	log.Printf("ℹ Usar API_URL=http://localhost:%s API_KEY=%s; fallas en caliente con PUT %s", *port, *apiKey, fakeupstream.FaultsPath)
+	return http.ListenAndServe(":"+*port, server)
+}
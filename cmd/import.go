@@ -0,0 +1,71 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"recommender/internal/core/domain"
+	"recommender/internal/core/services"
+)
+
+// runImport implementa `recommender import --file ratings.csv [flags]`: carga eventos
+// históricos desde un archivo CSV o NDJSON con la misma validación que la API y reporta
+// las filas insertadas, repetidas e inválidas con su número de línea.
+func runImport(args []string) error {
+	fs := flag.NewFlagSet("import", flag.ContinueOnError)
+	path := fs.String("file", "", "archivo CSV o NDJSON a importar")
+	format := fs.String("format", "", "csv o ndjson; por defecto se deduce de la extensión")
+	dryRun := fs.Bool("dry-run", false, "valida y cuenta sin escribir en la base")
+	asJSON := fs.Bool("json", false, "escribe el reporte en JSON")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	if *path == "" {
+		return fmt.Errorf("import: falta --file")
+	}
+	if *format == "" {
+		*format = services.ImportFormatFromPath(*path)
+	}
+	if *format != services.ImportFormatCSV && *format != services.ImportFormatNDJSON {
+		return fmt.Errorf("import: formato desconocido '%s', use --format csv o ndjson", *format)
+	}
+
+	file, err := os.Open(*path)
+	if err != nil {
+		return fmt.Errorf("import: no se pudo abrir el archivo: %w", err)
+	}
+	defer file.Close()
+
+	stockService := newApp(nil).stockService
+	report, err := stockService.ImportStocks(file, *format, *dryRun)
+	if report != nil {
+		if *asJSON {
+			encoder := json.NewEncoder(os.Stdout)
+			encoder.SetIndent("", "  ")
+			encoder.Encode(report)
+		} else {
+			writeImportReport(os.Stdout, report)
+		}
+	}
+	return err
+}
+
+// writeImportReport lista las filas ignoradas y cierra con los totales.
+func writeImportReport(w io.Writer, report *domain.ImportReport) {
+	for _, invalid := range report.Invalid {
+		fmt.Fprintf(w, "❌ línea %d: %s\n", invalid.Line, invalid.Error)
+	}
+	for _, duplicate := range report.Duplicates {
+		fmt.Fprintf(w, "ℹ línea %d: %s\n", duplicate.Line, duplicate.Error)
+	}
+
+	verb := "insertados"
+	if report.DryRun {
+		verb = "a insertar (dry-run)"
+	}
+	fmt.Fprintf(w, "%d %s, %d duplicados, %d inválidos\n", report.Inserted, verb, len(report.Duplicates), len(report.Invalid))
+}
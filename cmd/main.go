@@ -1,50 +1,97 @@
 package main
 
 import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
 	"log"
 	"os"
 
-	"recommender/config"
-	"recommender/internal/adapters/clients"
-	"recommender/internal/adapters/handlers"
-
-	repository "recommender/internal/adapters/repositories"
-	"recommender/internal/core/services"
-	"recommender/routes"
-
 	"github.com/joho/godotenv"
 )
 
+// command es un subcomando de `recommender <comando> [flags]`.
+type command struct {
+	name    string
+	summary string
+	run     func(args []string) error
+}
+
+var commands = []command{
+	{"serve", "levanta la API (sin importar, salvo --sync-on-start)", runServe},
+	{"sync", "importa una vez de la API externa y termina", runSync},
+	{"import", "importa eventos desde un archivo CSV o NDJSON", runImport},
+	{"export", "exporta stocks, historial o recomendaciones a CSV o XLSX", runExport},
+	{"recommend", "imprime el top de recomendaciones con sus puntuaciones", runRecommend},
+	{"backtest", "compara estrategias sobre los eventos guardados", runBacktest},
+	{"migrate", "crea o actualiza las tablas y termina", runMigrate},
+	{"fake-upstream", "sirve una API externa falsa con eventos grabados", runFakeUpstream},
+}
+
 func main() {
 	// Cargar variables de entorno desde .env
 	if err := godotenv.Load(); err != nil {
 		log.Println("⚠ No se pudo cargar el archivo .env, usando variables del sistema")
 	}
 
-	db := config.InitDB()
+	args := os.Args[1:]
+	if len(args) == 0 {
+		// Compatibilidad con el arranque anterior: importar una vez y servir
+		log.Println("ℹ Sin subcomando: se ejecuta `serve --sync-on-start`")
+		args = []string{"serve", "--sync-on-start"}
+	}
+	if args[0] == "help" || args[0] == "-h" || args[0] == "--help" {
+		usage(os.Stdout)
+		return
+	}
+
+	for _, cmd := range commands {
+		if cmd.name != args[0] {
+			continue
+		}
+		err := cmd.run(args[1:])
+		if errors.Is(err, flag.ErrHelp) {
+			return
+		}
+		var exit *exitError
+		if errors.As(err, &exit) {
+			log.Printf("⚠ %s: %v", cmd.name, exit.err)
+			os.Exit(exit.code)
+		}
+		if err != nil {
+			log.Printf("❌ Error en %s: %v", cmd.name, err)
+			os.Exit(1)
+		}
+		return
+	}
 
-	// Crear instancia del adaptador para la API externa
-	apiClient := clients.NewExternalStockAPI()
+	fmt.Fprintf(os.Stderr, "subcomando desconocido '%s'\n\n", args[0])
+	usage(os.Stderr)
+	os.Exit(2)
+}
 
-	// Inyección de dependencias
-	stockRepo := repository.NewCockroachStockRepository(db)
-	stockService := services.NewStockService(stockRepo, apiClient)
-	stockHandler := handlers.NewStockHandler(stockService)
+// exitError termina el proceso con un código distinto de 1, p. ej. una importación parcial.
+type exitError struct {
+	code int
+	err  error
+}
 
-	// Ejecutar la importación de datos solo una vez al inicio
-	err := stockService.FetchAndStoreStocks()
-	if err != nil {
-		log.Println("Error importing stocks:", err)
-	}
+func (e *exitError) Error() string {
+	return e.err.Error()
+}
 
-	r := routes.SetupRouter(stockHandler)
+func (e *exitError) Unwrap() error {
+	return e.err
+}
 
-	// Obtener el puerto desde las variables de entorno
-	port := os.Getenv("APP_PORT")
-	if port == "" {
-		port = "8081" // Puerto por defecto si no se encuentra en .env
+func usage(w io.Writer) {
+	fmt.Fprintln(w, "uso: recommender <comando> [flags]")
+	fmt.Fprintln(w)
+	for _, cmd := range commands {
+		fmt.Fprintf(w, "  %-14s %s\n", cmd.name, cmd.summary)
 	}
-
-	log.Println("🚀 Servidor corriendo en el puerto", port)
-	r.Run(":" + port)
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "Use `recommender <comando> -h` para ver los flags de cada comando.")
+	fmt.Fprintln(w, "Códigos de salida: 0 ok, 1 error, 2 uso incorrecto, 3 importación parcial.")
 }
@@ -0,0 +1,16 @@
+package main
+
+import (
+	"flag"
+
+	"recommender/config"
+)
+
+// runMigrate implementa `recommender migrate`: crea o actualiza las tablas y termina.
+func runMigrate(args []string) error {
+	fs := flag.NewFlagSet("migrate", flag.ContinueOnError)
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	return config.Migrate(config.ConnectDB())
+}
@@ -0,0 +1,76 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"recommender/config"
+	"recommender/internal/core/domain"
+	"recommender/internal/core/services"
+)
+
+// recommendationFlags registra los parámetros de scoring con los valores configurados
+// (RECOMMENDATION_*) como valores por defecto.
+func recommendationFlags(fs *flag.FlagSet) *domain.RecommendationParams {
+	params := config.LoadRecommendationDefaults(services.DefaultRecommendationParams())
+	fs.IntVar(&params.Limit, "limit", params.Limit, "cantidad de recomendaciones")
+	fs.IntVar(&params.LookbackDays, "lookback-days", params.LookbackDays, "ventana de eventos en días")
+	fs.IntVar(&params.CandidatePool, "candidate-pool", params.CandidatePool, "eventos candidatos; 0 usa toda la ventana")
+	fs.StringVar(&params.Strategy, "strategy", params.Strategy, "estrategia de puntuación")
+	fs.StringVar(&params.GroupBy, "group-by", params.GroupBy, "ticker o event")
+	fs.IntVar(&params.MaxPerSector, "max-per-sector", params.MaxPerSector, "máximo de picks por sector")
+	fs.IntVar(&params.MaxPerBrokerage, "max-per-brokerage", params.MaxPerBrokerage, "máximo de picks por corredora")
+	fs.IntVar(&params.MinBrokerages, "min-brokerages", params.MinBrokerages, "corredoras distintas que deben respaldar cada pick")
+	fs.Func("tickers", "solo estos tickers, separados por coma", func(raw string) error {
+		for _, ticker := range strings.Split(raw, ",") {
+			if ticker = strings.ToUpper(strings.TrimSpace(ticker)); ticker != "" {
+				params.Tickers = append(params.Tickers, ticker)
+			}
+		}
+		return nil
+	})
+	return &params
+}
+
+// runRecommend implementa `recommender recommend [flags]`: imprime el top con sus
+// puntuaciones en una tabla, o la lista completa en JSON con --json.
+func runRecommend(args []string) error {
+	fs := flag.NewFlagSet("recommend", flag.ContinueOnError)
+	params := recommendationFlags(fs)
+	asJSON := fs.Bool("json", false, "escribe la lista completa en JSON")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	a := newApp(nil)
+	a.brokerageAccuracy()
+	list, err := a.stockService.GetTopRecommendedStocks(*params)
+	if err != nil {
+		return err
+	}
+
+	if *asJSON {
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(list)
+	}
+	return writeRecommendationTable(os.Stdout, list)
+}
+
+func writeRecommendationTable(w io.Writer, list *domain.RecommendationList) error {
+	fmt.Fprintf(w, "Estrategia %s (%s), %s\n\n", list.Strategy, list.ScorerVersion, list.GeneratedAt.Format("2006-01-02 15:04"))
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "#\tTICKER\tCOMPAÑÍA\tSCORE\tDETALLE")
+	for _, rec := range list.Items {
+		fmt.Fprintf(tw, "%d\t%s\t%s\t%.2f\t%s\n", rec.Rank, rec.Ticker, rec.Company, rec.Score, rec.Breakdown.Rationale)
+	}
+	if len(list.Items) == 0 {
+		fmt.Fprintln(tw, "-\t-\tsin eventos en la ventana\t-\t-")
+	}
+	return tw.Flush()
+}
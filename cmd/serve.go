@@ -0,0 +1,164 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"log"
+	"os"
+	"strings"
+	"time"
+
+	"recommender/config"
+	"recommender/internal/adapters/clients"
+	"recommender/internal/adapters/handlers"
+	"recommender/internal/adapters/notifiers"
+	repository "recommender/internal/adapters/repositories"
+	"recommender/internal/core/domain"
+	"recommender/internal/core/ports"
+	"recommender/internal/core/services"
+	"recommender/routes"
+)
+
+// runServe implementa `recommender serve`: levanta la API y los workers en segundo plano.
+// No importa de la API externa salvo con --sync-on-start o SYNC_INTERVAL_MINUTES.
+func runServe(args []string) error {
+	fs := flag.NewFlagSet("serve", flag.ContinueOnError)
+	port := fs.String("port", "", "puerto HTTP, por defecto APP_PORT u 8081")
+	syncOnStart := fs.Bool("sync-on-start", false, "importa de la API externa antes de atender peticiones")
+	syncInterval := fs.Int("sync-interval-minutes", config.EnvInt("SYNC_INTERVAL_MINUTES", 0), "importa periódicamente; 0 lo desactiva")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if *port == "" {
+		*port = os.Getenv("APP_PORT")
+	}
+	if *port == "" {
+		*port = "8081" // Puerto por defecto si no se encuentra en .env
+	}
+
+	// El cliente de la API solo es obligatorio si el servidor va a importar
+	var apiClient ports.StockAPIClient
+	var upstreamHandler *handlers.UpstreamHandler
+	if *syncOnStart || *syncInterval > 0 {
+		providers, err := clients.NewProvidersFromEnv(config.LoadUpstreamPolicy())
+		if err != nil {
+			return err
+		}
+		log.Println("🔌 Proveedores configurados:", strings.Join(providers.Sources(), ", "))
+		apiClient = providers
+		upstreamHandler = handlers.NewUpstreamHandler(providers)
+	}
+	a := newApp(apiClient)
+	stockService := a.stockService
+
+	// Alertas sobre los eventos de rating nuevos: se evalúan desde el outbox, de modo que
+	// cubren también lo importado por `sync` o `import` en otros procesos
+	watchlistRepo := repository.NewCockroachWatchlistRepository(a.db)
+	alertService := newAlertService(repository.NewCockroachAlertRepository(a.db), watchlistRepo).
+		WithOutbox(repository.NewCockroachOutboxRepository(a.db), repository.NewCockroachOutboxCursorRepository(a.db)).
+		WithSafetyLag(time.Duration(config.EnvInt("ALERT_SAFETY_LAG_SECONDS", 2)) * time.Second)
+	go alertService.Run(context.Background(), time.Duration(config.EnvInt("ALERT_POLL_SECONDS", 5))*time.Second)
+
+	// Webhooks salientes: se alimentan del outbox que escribe el repositorio de stocks
+	webhookService := services.NewWebhookService(
+		repository.NewCockroachWebhookRepository(a.db),
+		notifiers.NewHTTPWebhookSender(time.Duration(config.EnvInt("WEBHOOK_TIMEOUT_SECONDS", 10))*time.Second),
+		config.LoadWebhookRetryPolicy())
+	go webhookService.Run(context.Background(), time.Duration(config.EnvInt("WEBHOOK_POLL_SECONDS", 5))*time.Second)
+
+	// Stream SSE de stocks nuevos, también alimentado por el outbox
+	streamHub := services.NewStreamHub(repository.NewCockroachOutboxRepository(a.db),
+		time.Duration(config.EnvInt("STREAM_POLL_SECONDS", 2))*time.Second).
+		WithSafetyLag(time.Duration(config.EnvInt("STREAM_SAFETY_LAG_SECONDS", 2)) * time.Second)
+	if err := streamHub.Start(context.Background()); err != nil {
+		log.Println("⚠ No se pudo iniciar el stream de stocks:", err)
+	}
+
+	// Feed en vivo de recomendaciones: se recalcula cuando el outbox registra eventos
+	// nuevos, los haya importado este proceso o cualquier otro
+	recommendationFeed := services.NewRecommendationFeed(stockService, repository.NewCockroachOutboxRepository(a.db),
+		time.Duration(config.EnvInt("FEED_POLL_SECONDS", 5))*time.Second)
+	if err := recommendationFeed.Start(context.Background()); err != nil {
+		log.Println("⚠ No se pudo iniciar el feed de recomendaciones:", err)
+	}
+	stockService.WithSyncObserver(recommendationFeed)
+
+	// Precios históricos para medir la precisión de las corredoras; los pesos medidos se
+	// recalculan periódicamente porque los horizontes se cumplen y llegan eventos nuevos
+	accuracyService, brokerageWeights := a.brokerageAccuracy()
+	if refresh := config.EnvInt("BROKERAGE_WEIGHTS_REFRESH_MINUTES", 60); brokerageWeights != nil && refresh > 0 {
+		go brokerageWeights.Run(context.Background(), time.Duration(refresh)*time.Minute)
+	}
+
+	if *syncOnStart {
+		if _, err := stockService.FetchAndStoreStocks(); err != nil {
+			log.Println("Error importing stocks:", err)
+		}
+	}
+	if *syncInterval > 0 {
+		go runPeriodicSync(context.Background(), stockService, time.Duration(*syncInterval)*time.Minute)
+	}
+
+	r := routes.SetupRouter(routes.Handlers{
+		Stock:     handlers.NewStockHandler(stockService),
+		Company:   handlers.NewCompanyHandler(a.companyService),
+		Brokerage: handlers.NewBrokerageHandler(accuracyService),
+		Snapshot:  handlers.NewSnapshotHandler(a.snapshotService),
+		Watchlist: handlers.NewWatchlistHandler(services.NewWatchlistService(watchlistRepo, stockService), stockService),
+		Alert:     handlers.NewAlertHandler(alertService),
+		Webhook:   handlers.NewWebhookHandler(webhookService),
+		Stream:    handlers.NewStreamHandler(streamHub, time.Duration(config.EnvInt("STREAM_HEARTBEAT_SECONDS", 15))*time.Second),
+		Feed: handlers.NewRecommendationFeedHandler(recommendationFeed, stockService,
+			time.Duration(config.EnvInt("FEED_PING_SECONDS", 30))*time.Second),
+		Upstream: upstreamHandler,
+	})
+
+	log.Println("🚀 Servidor corriendo en el puerto", *port)
+	return r.Run(":" + *port)
+}
+
+// runPeriodicSync repite la importación; cada corrida notifica a los observadores de sincronización.
+func runPeriodicSync(ctx context.Context, stockService *services.StockService, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if _, err := stockService.FetchAndStoreStocks(); err != nil {
+				log.Println("Error importing stocks:", err)
+			}
+		}
+	}
+}
+
+// newAlertService registra los notificadores. Con ALERT_DELIVERY=local los canales
+// externos se reemplazan por notificadores en memoria que solo escriben en el log.
+func newAlertService(alerts ports.AlertRepository, watchlists ports.WatchlistRepository) *services.AlertService {
+	alertService := services.NewAlertService(alerts, watchlists).RegisterNotifier(notifiers.NewLogNotifier())
+
+	if os.Getenv("ALERT_DELIVERY") == "local" {
+		log.Println("🧪 Alertas en modo local: webhook y smtp no envían nada")
+		return alertService.
+			RegisterNotifier(notifiers.NewMemoryNotifier(domain.NotifierWebhook)).
+			RegisterNotifier(notifiers.NewMemoryNotifier(domain.NotifierSMTP))
+	}
+
+	timeout := time.Duration(config.EnvInt("ALERT_WEBHOOK_TIMEOUT_SECONDS", 5)) * time.Second
+	alertService.RegisterNotifier(notifiers.NewWebhookNotifier(timeout))
+	if host := os.Getenv("SMTP_HOST"); host != "" {
+		port := os.Getenv("SMTP_PORT")
+		if port == "" {
+			port = "587"
+		}
+		alertService.RegisterNotifier(notifiers.NewSMTPNotifier(notifiers.SMTPConfig{
+			Host:     host,
+			Port:     port,
+			Username: os.Getenv("SMTP_USERNAME"),
+			Password: os.Getenv("SMTP_PASSWORD"),
+			From:     os.Getenv("SMTP_FROM"),
+		}))
+	}
+	return alertService
+}
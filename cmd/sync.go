@@ -0,0 +1,71 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"recommender/config"
+	"recommender/internal/adapters/clients"
+	"recommender/internal/core/domain"
+)
+
+// exitPartialSync es el estado de salida de una importación que terminó como parcial.
+const exitPartialSync = 3
+
+// runSync implementa `recommender sync`: importa una vez de la API externa y termina.
+// Sale con 1 si la importación falla y con 3 si quedó parcial, para que un cron o un
+// pipeline puedan distinguirlo.
+func runSync(args []string) error {
+	fs := flag.NewFlagSet("sync", flag.ContinueOnError)
+	onFailure := fs.String("on-page-failure", config.LoadPageFailurePolicy(), "abort, skip o partial si una página falla")
+	asJSON := fs.Bool("json", false, "escribe el resultado en JSON")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if !domain.ValidPageFailurePolicy(*onFailure) {
+		return fmt.Errorf("sync: --on-page-failure desconocida '%s', use abort, skip o partial", *onFailure)
+	}
+
+	providers, err := clients.NewProvidersFromEnv(config.LoadUpstreamPolicy())
+	if err != nil {
+		return fmt.Errorf("sync: %w", err)
+	}
+	a := newApp(providers)
+	a.stockService.WithPageFailurePolicy(*onFailure)
+
+	result, err := a.stockService.FetchAndStoreStocks()
+	if *asJSON {
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		encoder.Encode(result)
+	} else {
+		writeSyncResult(os.Stdout, result, providers.UpstreamStats())
+	}
+
+	if err != nil {
+		return fmt.Errorf("sync: %w", err)
+	}
+	if result.Status == domain.SyncPartial {
+		return &exitError{code: exitPartialSync, err: fmt.Errorf("importación parcial, %d páginas fallidas", len(result.FailedPages))}
+	}
+	return nil
+}
+
+// writeSyncResult lista las páginas fallidas, los totales y el estado de cada proveedor.
+func writeSyncResult(w io.Writer, result *domain.SyncResult, upstreams []domain.UpstreamStats) {
+	for _, failure := range result.FailedPages {
+		fmt.Fprintf(w, "❌ página '%s': %s\n", failure.Page, failure.Error)
+	}
+	fmt.Fprintf(w, "%s: %d páginas, %d eventos nuevos, %d duplicados, %d con error\n",
+		result.Status, result.Pages, result.Inserted, result.Duplicates, result.ItemErrors)
+	if result.ResumeFrom != "" {
+		fmt.Fprintf(w, "se detuvo en la página '%s'\n", result.ResumeFrom)
+	}
+	for _, stats := range upstreams {
+		fmt.Fprintf(w, "%s: %d peticiones, %d reintentos, %d respuestas 429, circuito %s\n",
+			stats.Source, stats.Requests, stats.Retries, stats.RateLimited, stats.Circuit)
+	}
+}
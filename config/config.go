@@ -4,8 +4,14 @@ import (
 	"fmt"
 	"log"
 	"os"
+	"strconv"
+	"strings"
+	"time"
 
+	"recommender/internal/adapters/clients"
+	repository "recommender/internal/adapters/repositories"
 	"recommender/internal/core/domain"
+	"recommender/internal/core/services"
 
 	"github.com/joho/godotenv"
 	"gorm.io/driver/postgres"
@@ -14,7 +20,21 @@ import (
 
 var DB *gorm.DB
 
+// InitDB conecta y aplica las migraciones, salvo que AUTO_MIGRATE sea "false"
+// (en ese caso se espera que se haya corrido `recommender migrate`).
 func InitDB() *gorm.DB {
+	db := ConnectDB()
+	if os.Getenv("AUTO_MIGRATE") == "false" {
+		return db
+	}
+	if err := Migrate(db); err != nil {
+		log.Fatal("❌ Error al migrar la base de datos:", err)
+	}
+	return db
+}
+
+// ConnectDB abre la conexión sin migrar.
+func ConnectDB() *gorm.DB {
 	// Cargar variables de entorno
 	if err := godotenv.Load(); err != nil {
 		log.Println("⚠ No se pudo cargar el archivo .env, usando variables del sistema")
@@ -37,13 +57,157 @@ func InitDB() *gorm.DB {
 	}
 
 	log.Println("✅ Conectado a la base de datos")
+	return DB
+}
 
-	// Migraciones automáticas
-	err = DB.AutoMigrate(&domain.Stock{})
+// Migrate crea o actualiza las tablas de todos los modelos.
+func Migrate(db *gorm.DB) error {
+	err := db.AutoMigrate(&domain.Stock{}, &domain.Company{}, &domain.PriceBar{}, &domain.RecommendationSnapshot{}, &domain.Watchlist{},
+		&domain.AlertRule{}, &domain.AlertEvent{},
+		&domain.OutboxEvent{}, &domain.OutboxCursor{}, &domain.WebhookSubscription{}, &domain.WebhookDelivery{})
 	if err != nil {
-		log.Fatal("❌ Error al migrar la base de datos:", err)
+		return err
+	}
+	// Los eventos anteriores a action_type quedaron con la columna vacía
+	backfilled, err := repository.BackfillActionTypes(db)
+	if err != nil {
+		return err
+	}
+	if backfilled > 0 {
+		log.Printf("✅ %d eventos clasificados por tipo de acción", backfilled)
 	}
 	log.Println("✅ Migraciones completadas")
+	return nil
+}
 
-	return DB
+// LoadRecommendationDefaults lee los parámetros por defecto de las recomendaciones
+// (RECOMMENDATION_LIMIT, RECOMMENDATION_LOOKBACK_DAYS, RECOMMENDATION_CANDIDATE_POOL y
+// RECOMMENDATION_STRATEGY y RECOMMENDATION_GROUP_BY) partiendo de los valores recibidos.
+func LoadRecommendationDefaults(defaults domain.RecommendationParams) domain.RecommendationParams {
+	params := defaults
+	params.Limit = EnvInt("RECOMMENDATION_LIMIT", params.Limit)
+	params.LookbackDays = EnvInt("RECOMMENDATION_LOOKBACK_DAYS", params.LookbackDays)
+	params.CandidatePool = EnvInt("RECOMMENDATION_CANDIDATE_POOL", params.CandidatePool)
+	params.MaxPerSector = EnvInt("RECOMMENDATION_MAX_PER_SECTOR", params.MaxPerSector)
+	params.MaxPerBrokerage = EnvInt("RECOMMENDATION_MAX_PER_BROKERAGE", params.MaxPerBrokerage)
+	params.MinBrokerages = EnvInt("RECOMMENDATION_MIN_BROKERAGES", params.MinBrokerages)
+	if strategy := os.Getenv("RECOMMENDATION_STRATEGY"); strategy != "" {
+		params.Strategy = strategy
+	}
+	if groupBy := os.Getenv("RECOMMENDATION_GROUP_BY"); groupBy != "" {
+		params.GroupBy = groupBy
+	}
+
+	if err := params.Validate(); err != nil {
+		log.Printf("⚠ Parámetros de recomendación inválidos (%v), usando valores por defecto", err)
+		return defaults
+	}
+	return params
+}
+
+// LoadWebhookRetryPolicy lee WEBHOOK_MAX_ATTEMPTS, WEBHOOK_BACKOFF_SECONDS y
+// WEBHOOK_MAX_BACKOFF_SECONDS sobre la política por defecto.
+func LoadWebhookRetryPolicy() services.WebhookRetryPolicy {
+	policy := services.DefaultWebhookRetryPolicy()
+	policy.MaxAttempts = EnvInt("WEBHOOK_MAX_ATTEMPTS", policy.MaxAttempts)
+	policy.Backoff = time.Duration(EnvInt("WEBHOOK_BACKOFF_SECONDS", int(policy.Backoff/time.Second))) * time.Second
+	policy.MaxBackoff = time.Duration(EnvInt("WEBHOOK_MAX_BACKOFF_SECONDS", int(policy.MaxBackoff/time.Second))) * time.Second
+	if policy.MaxAttempts < 1 || policy.Backoff <= 0 || policy.MaxBackoff < policy.Backoff {
+		log.Println("⚠ Política de reintentos de webhooks inválida, usando valores por defecto")
+		return services.DefaultWebhookRetryPolicy()
+	}
+	return policy
+}
+
+// LoadUpstreamPolicy lee la política con que se llama a los proveedores externos:
+// UPSTREAM_RATE_PER_SECOND, UPSTREAM_BURST, UPSTREAM_MAX_ATTEMPTS,
+// UPSTREAM_ATTEMPT_TIMEOUT_SECONDS, UPSTREAM_BACKOFF_MS, UPSTREAM_MAX_BACKOFF_SECONDS,
+// UPSTREAM_MAX_RETRY_AFTER_SECONDS, UPSTREAM_BREAKER_THRESHOLD y
+// UPSTREAM_BREAKER_COOLDOWN_SECONDS. Un ritmo o umbral en 0 desactiva esa protección.
+func LoadUpstreamPolicy() clients.ResiliencePolicy {
+	defaults := clients.DefaultResiliencePolicy()
+	policy := defaults
+	policy.RatePerSecond = EnvFloat("UPSTREAM_RATE_PER_SECOND", policy.RatePerSecond)
+	policy.Burst = EnvInt("UPSTREAM_BURST", policy.Burst)
+	policy.MaxAttempts = EnvInt("UPSTREAM_MAX_ATTEMPTS", policy.MaxAttempts)
+	policy.AttemptTimeout = time.Duration(EnvInt("UPSTREAM_ATTEMPT_TIMEOUT_SECONDS", int(policy.AttemptTimeout/time.Second))) * time.Second
+	policy.BackoffBase = time.Duration(EnvInt("UPSTREAM_BACKOFF_MS", int(policy.BackoffBase/time.Millisecond))) * time.Millisecond
+	policy.BackoffMax = time.Duration(EnvInt("UPSTREAM_MAX_BACKOFF_SECONDS", int(policy.BackoffMax/time.Second))) * time.Second
+	policy.MaxRetryAfter = time.Duration(EnvInt("UPSTREAM_MAX_RETRY_AFTER_SECONDS", int(policy.MaxRetryAfter/time.Second))) * time.Second
+	policy.BreakerThreshold = EnvInt("UPSTREAM_BREAKER_THRESHOLD", policy.BreakerThreshold)
+	policy.BreakerCooldown = time.Duration(EnvInt("UPSTREAM_BREAKER_COOLDOWN_SECONDS", int(policy.BreakerCooldown/time.Second))) * time.Second
+	if policy.RatePerSecond < 0 || policy.Burst < 1 || policy.MaxAttempts < 1 || policy.AttemptTimeout < 0 ||
+		policy.BackoffBase < 0 || policy.BackoffMax < policy.BackoffBase || policy.MaxRetryAfter < 0 ||
+		policy.BreakerThreshold < 0 || policy.BreakerCooldown < 0 {
+		log.Println("⚠ Política de llamadas a proveedores inválida, usando valores por defecto")
+		return defaults
+	}
+	return policy
+}
+
+// LoadPageFailurePolicy lee SYNC_PAGE_FAILURE_POLICY (abort, skip o partial): qué hace
+// la importación si una página de la API falla tras los reintentos.
+func LoadPageFailurePolicy() string {
+	policy := os.Getenv("SYNC_PAGE_FAILURE_POLICY")
+	if policy == "" {
+		return domain.PageFailurePartial
+	}
+	if !domain.ValidPageFailurePolicy(policy) {
+		log.Printf("⚠ SYNC_PAGE_FAILURE_POLICY desconocida '%s', usando %s", policy, domain.PageFailurePartial)
+		return domain.PageFailurePartial
+	}
+	return policy
+}
+
+// EnvFloat lee una variable de entorno decimal, usando fallback si falta o es inválida.
+func EnvFloat(name string, fallback float64) float64 {
+	raw := os.Getenv(name)
+	if raw == "" {
+		return fallback
+	}
+	value, err := strconv.ParseFloat(raw, 64)
+	if err != nil {
+		log.Printf("⚠ %s no es un número válido: '%s'", name, raw)
+		return fallback
+	}
+	return value
+}
+
+// EnvInt lee una variable de entorno entera, usando fallback si falta o es inválida.
+func EnvInt(name string, fallback int) int {
+	raw := os.Getenv(name)
+	if raw == "" {
+		return fallback
+	}
+	value, err := strconv.Atoi(raw)
+	if err != nil {
+		log.Printf("⚠ %s no es un entero válido: '%s'", name, raw)
+		return fallback
+	}
+	return value
+}
+
+// LoadDecay arma el decaimiento temporal de la puntuación a partir de DECAY_MODE
+// (none, exponential, linear o step) y sus parámetros DECAY_HALF_LIFE_DAYS,
+// DECAY_WINDOW_DAYS y DECAY_STEPS ("7:1,14:0.5,30:0.25").
+func LoadDecay() services.Decay {
+	day := 24 * time.Hour
+	switch mode := strings.ToLower(os.Getenv("DECAY_MODE")); mode {
+	case "", "none":
+		return services.NoDecay{}
+	case "exponential":
+		return services.ExponentialDecay{HalfLife: time.Duration(EnvInt("DECAY_HALF_LIFE_DAYS", 7)) * day}
+	case "linear":
+		return services.LinearDecay{Window: time.Duration(EnvInt("DECAY_WINDOW_DAYS", 30)) * day}
+	case "step":
+		steps, err := services.ParseDecaySteps(os.Getenv("DECAY_STEPS"))
+		if err != nil {
+			log.Printf("⚠ DECAY_STEPS inválido (%v), sin decaimiento", err)
+			return services.NoDecay{}
+		}
+		return services.StepDecay{Steps: steps}
+	default:
+		log.Printf("⚠ DECAY_MODE desconocido '%s', sin decaimiento", mode)
+		return services.NoDecay{}
+	}
 }
@@ -0,0 +1,191 @@
+package clients
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// Modos de grabación configurables con API_CASSETTE_MODE.
+const (
+	CassetteModeRecord = "record" // Llama a la API y guarda cada respuesta
+	CassetteModeReplay = "replay" // No usa la red: responde con lo grabado
+)
+
+const redacted = "[REDACTED]"
+
+// Interaction es una petición a la API externa y su respuesta, tal como se guarda en
+// un archivo del directorio de cassettes. La API key nunca se escribe.
+type Interaction struct {
+	RecordedAt time.Time        `json:"recorded_at"`
+	Request    CassetteRequest  `json:"request"`
+	Response   CassetteResponse `json:"response"`
+}
+
+type CassetteRequest struct {
+	Method string      `json:"method"`
+	URL    string      `json:"url"`
+	Header http.Header `json:"header"`
+}
+
+type CassetteResponse struct {
+	Status int         `json:"status"`
+	Header http.Header `json:"header"`
+	Body   string      `json:"body"`
+}
+
+// cassettePath nombra el archivo por método y URL (sin la key), así la misma página
+// se encuentra al reproducir aunque se use otra API key.
+func cassettePath(dir, method, url string) string {
+	sum := sha256.Sum256([]byte(method + " " + url))
+	return filepath.Join(dir, hex.EncodeToString(sum[:8])+".json")
+}
+
+// RecordingTransport pasa cada petición a next y guarda la interacción en dir. Si no
+// puede escribir el archivo lo informa en el log pero no hace fallar la importación.
+type RecordingTransport struct {
+	next   http.RoundTripper
+	dir    string
+	secret string
+}
+
+func NewRecordingTransport(next http.RoundTripper, dir, secret string) *RecordingTransport {
+	if next == nil {
+		next = http.DefaultTransport
+	}
+	return &RecordingTransport{next: next, dir: dir, secret: secret}
+}
+
+func (t *RecordingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	resp, err := t.next.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+	body, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+
+	interaction := Interaction{
+		RecordedAt: time.Now().UTC(),
+		Request: CassetteRequest{
+			Method: req.Method,
+			URL:    t.redact(req.URL.String()),
+			Header: t.redactHeader(req.Header),
+		},
+		Response: CassetteResponse{
+			Status: resp.StatusCode,
+			Header: t.redactHeader(resp.Header),
+			Body:   t.redact(string(body)),
+		},
+	}
+	if err := t.save(interaction); err != nil {
+		log.Println("⚠ No se pudo grabar la respuesta de la API:", err)
+	}
+	return resp, nil
+}
+
+func (t *RecordingTransport) save(interaction Interaction) error {
+	if err := os.MkdirAll(t.dir, 0o755); err != nil {
+		return err
+	}
+	raw, err := json.MarshalIndent(interaction, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(cassettePath(t.dir, interaction.Request.Method, interaction.Request.URL), raw, 0o644)
+}
+
+func (t *RecordingTransport) redact(value string) string {
+	if t.secret == "" {
+		return value
+	}
+	return strings.ReplaceAll(value, t.secret, redacted)
+}
+
+func (t *RecordingTransport) redactHeader(header http.Header) http.Header {
+	clean := make(http.Header, len(header))
+	for key, values := range header {
+		for _, value := range values {
+			clean.Add(key, t.redact(value))
+		}
+	}
+	return clean
+}
+
+// ReplayTransport responde con las interacciones grabadas en dir sin tocar la red.
+// Una petición sin grabación es un error, para que la reproducción sea determinista.
+type ReplayTransport struct {
+	dir    string
+	secret string
+}
+
+func NewReplayTransport(dir, secret string) *ReplayTransport {
+	return &ReplayTransport{dir: dir, secret: secret}
+}
+
+func (t *ReplayTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	url := req.URL.String()
+	if t.secret != "" {
+		url = strings.ReplaceAll(url, t.secret, redacted)
+	}
+	raw, err := os.ReadFile(cassettePath(t.dir, req.Method, url))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, fmt.Errorf("cassette: no hay grabación para %s %s en %s", req.Method, url, t.dir)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var interaction Interaction
+	if err := json.Unmarshal(raw, &interaction); err != nil {
+		return nil, fmt.Errorf("cassette: %w", err)
+	}
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", interaction.Response.Status, http.StatusText(interaction.Response.Status)),
+		StatusCode:    interaction.Response.Status,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        interaction.Response.Header,
+		Body:          io.NopCloser(strings.NewReader(interaction.Response.Body)),
+		ContentLength: int64(len(interaction.Response.Body)),
+		Request:       req,
+	}, nil
+}
+
+// CassetteTransportFromEnv arma el transporte según API_CASSETTE_MODE y
+// API_CASSETTE_DIR (por defecto "cassettes"); cada proveedor usa su subdirectorio.
+// Sin modo devuelve nil, es decir, la red sin grabar.
+func CassetteTransportFromEnv(source, secret string) (http.RoundTripper, error) {
+	dir := strings.TrimSpace(os.Getenv("API_CASSETTE_DIR"))
+	if dir == "" {
+		dir = "cassettes"
+	}
+	dir = filepath.Join(dir, source)
+
+	switch mode := strings.TrimSpace(os.Getenv("API_CASSETTE_MODE")); mode {
+	case "":
+		return nil, nil
+	case CassetteModeRecord:
+		log.Printf("📼 Grabando las respuestas de '%s' en %s", source, dir)
+		return NewRecordingTransport(nil, dir, secret), nil
+	case CassetteModeReplay:
+		log.Printf("📼 Reproduciendo las respuestas de '%s' desde %s", source, dir)
+		return NewReplayTransport(dir, secret), nil
+	default:
+		return nil, fmt.Errorf("API_CASSETTE_MODE desconocido '%s'", mode)
+	}
+}
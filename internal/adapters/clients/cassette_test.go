@@ -0,0 +1,112 @@
+package clients
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCassettes_RecordThenReplay(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "Bearer live-key", r.Header.Get("Authorization"))
+		if r.URL.Query().Get("next_page") == "" {
+			w.Write([]byte(`{"items":[{"ticker":"AAPL","target_from":"$150.00","target_to":"$180.00","time":"2024-06-01T10:00:00Z"}],"next_page":"AAPL"}`))
+			return
+		}
+		w.Write([]byte(`{"items":[{"ticker":"MSFT","target_from":"abc","target_to":"$400.00","time":"2024-06-01T10:00:00Z"}],"next_page":""}`))
+	}))
+	dir := t.TempDir()
+
+	recorder := NewExternalStockAPIProvider("legacy", server.URL, "live-key").
+		WithTransport(NewRecordingTransport(nil, dir, "live-key"))
+	recorded, err := recorder.FetchStocks("")
+	require.NoError(t, err)
+	_, recordedErr := recorder.FetchStocks("AAPL")
+	require.Error(t, recordedErr)
+
+	files, _ := filepath.Glob(filepath.Join(dir, "*.json"))
+	require.Len(t, files, 2)
+	for _, file := range files {
+		raw, err := os.ReadFile(file)
+		require.NoError(t, err)
+		assert.NotContains(t, string(raw), "live-key")
+		assert.Contains(t, string(raw), "Bearer [REDACTED]")
+	}
+
+	// Con el servidor apagado y otra key, la reproducción devuelve lo mismo
+	server.Close()
+	replayer := NewExternalStockAPIProvider("legacy", server.URL, "other-key").
+		WithTransport(NewReplayTransport(dir, "other-key"))
+	replayed, err := replayer.FetchStocks("")
+	require.NoError(t, err)
+	assert.Equal(t, recorded, replayed)
+	_, replayedErr := replayer.FetchStocks("AAPL")
+	assert.Equal(t, recordedErr.Error(), replayedErr.Error())
+
+	_, err = replayer.FetchStocks("MSFT")
+	assert.ErrorContains(t, err, "no hay grabación")
+}
+
+func TestCassettes_RecordsErrorResponses(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+	dir := t.TempDir()
+
+	_, err := NewPagedStockAPI("acme", server.URL, "k", "", 10).
+		WithTransport(NewRecordingTransport(nil, dir, "k")).
+		FetchStocks("")
+	assert.ErrorContains(t, err, "503")
+
+	_, err = NewPagedStockAPI("acme", "http://unreachable.invalid", "k", "", 10).
+		WithTransport(NewReplayTransport(dir, "k")).
+		FetchStocks("")
+	assert.ErrorContains(t, err, "no hay grabación") // Otra URL, otro cassette
+}
+
+func TestCassetteTransportFromEnv(t *testing.T) {
+	t.Setenv("API_CASSETTE_DIR", "/tmp/incident-42")
+
+	t.Setenv("API_CASSETTE_MODE", "")
+	transport, err := CassetteTransportFromEnv("legacy", "k")
+	require.NoError(t, err)
+	assert.Nil(t, transport)
+
+	t.Setenv("API_CASSETTE_MODE", CassetteModeReplay)
+	transport, err = CassetteTransportFromEnv("legacy", "k")
+	require.NoError(t, err)
+	assert.Equal(t, "/tmp/incident-42/legacy", transport.(*ReplayTransport).dir)
+
+	t.Setenv("API_CASSETTE_MODE", CassetteModeRecord)
+	transport, err = CassetteTransportFromEnv("acme", "k")
+	require.NoError(t, err)
+	assert.Equal(t, "/tmp/incident-42/acme", transport.(*RecordingTransport).dir)
+
+	t.Setenv("API_CASSETTE_MODE", "rewind")
+	_, err = CassetteTransportFromEnv("legacy", "k")
+	assert.Error(t, err)
+}
+
+// testdata/cassettes/default es una importación grabada en la que la segunda página
+// trae una fecha inválida; se reproduce igual sin red ni credenciales.
+func TestCassettes_ReplayRecordedIncident(t *testing.T) {
+	t.Setenv("API_CASSETTE_MODE", CassetteModeReplay)
+	t.Setenv("API_CASSETTE_DIR", filepath.Join("testdata", "cassettes"))
+	transport, err := CassetteTransportFromEnv(DefaultSource, "any-key")
+	require.NoError(t, err)
+	api := NewExternalStockAPIProvider(DefaultSource, "http://127.0.0.1:8090", "any-key").WithTransport(transport)
+
+	first, err := api.FetchStocks("")
+	require.NoError(t, err)
+	assert.Len(t, first.Items, 10)
+	assert.Equal(t, "10", first.NextPage)
+
+	_, err = api.FetchStocks(first.NextPage)
+	assert.ErrorContains(t, err, `parsing time "not-a-timestamp"`)
+}
@@ -7,15 +7,31 @@ import (
 	"os"
 	"recommender/internal/core/domain"
 	"recommender/internal/core/ports"
-	"strconv"
 	"strings"
 	"time"
 )
 
+// DefaultSource es el nombre del proveedor configurado con API_URL y API_KEY.
+const DefaultSource = "default"
+
+// ExternalStockAPI es el proveedor original: respuesta {items, next_page} con precios
+// como texto, autenticación Bearer y paginación por `?next_page=`. Este formato no trae
+// ID propio, así que el ExternalID se arma con el ticker y la fecha del evento.
 type ExternalStockAPI struct {
 	client  *http.Client
 	baseURL string
 	apiKey  string
+	source  string
+}
+
+// NewExternalStockAPIProvider crea el proveedor con el formato original bajo otro nombre.
+func NewExternalStockAPIProvider(source, baseURL, apiKey string) *ExternalStockAPI {
+	return &ExternalStockAPI{
+		client:  &http.Client{Timeout: 10 * time.Second},
+		baseURL: baseURL,
+		apiKey:  apiKey,
+		source:  source,
+	}
 }
 
 func NewExternalStockAPI() ports.StockAPIClient {
@@ -27,33 +43,30 @@ func NewExternalStockAPI() ports.StockAPIClient {
 	if apiKey == "" {
 		panic("API_KEY environment variable not set")
 	}
-	return &ExternalStockAPI{
-		client:  &http.Client{Timeout: 10 * time.Second},
-		baseURL: apiURL,
-		apiKey:  apiKey,
-	}
+	return NewExternalStockAPIProvider(DefaultSource, apiURL, apiKey)
 }
 
-func parsePrice(priceStr string) (float64, error) {
-	//elimina caracteres no necesarios 
-	priceStr = strings.TrimSpace(strings.Replace(priceStr, "$", "", -1))
-	priceStr = strings.Replace(priceStr, ",", "", -1)
-
-	if priceStr == "" {
-		return 0, fmt.Errorf("parsePrice: recibido string vacío")
+// WithTransport reemplaza el transporte HTTP (cassettes, ResilientTransport); nil no
+// cambia nada. El transporte pasa a controlar los tiempos de espera de cada intento.
+func (a *ExternalStockAPI) WithTransport(transport http.RoundTripper) *ExternalStockAPI {
+	if transport != nil {
+		a.client.Transport = transport
+		a.client.Timeout = 0
 	}
+	return a
+}
 
-	price, err := strconv.ParseFloat(priceStr, 64)
-	if err != nil {
-		fmt.Printf("⚠ Error convirtiendo TargetFrom: '%s'\n", priceStr)
-		return 0, fmt.Errorf("parsePrice: error convirtiendo '%s' a float64", priceStr)
-	}
+func (a *ExternalStockAPI) Source() string {
+	return a.source
+}
 
-	return price, nil
+// parsePrice y parseTime delegan en el dominio, que comparte la lógica con la importación de archivos.
+func parsePrice(priceStr string) (float64, error) {
+	return domain.ParsePrice(priceStr)
 }
 
 func parseTime(timeStr string) (time.Time, error) {
-	return time.Parse(time.RFC3339, timeStr) // Convertir a `time.Time`
+	return domain.ParseTime(timeStr)
 }
 
 func (a *ExternalStockAPI) FetchStocks(nextPage string) (*domain.APIResponse, error) {
@@ -90,32 +103,13 @@ func (a *ExternalStockAPI) FetchStocks(nextPage string) (*domain.APIResponse, er
 	// Convertir `StockDTO` a `Stock`
 	var stocks []domain.Stock
 	for _, stockDTO := range apiResponseDTO.Items {
-		targetFrom, err := parsePrice(stockDTO.TargetFrom)
-		if err != nil {
-			return nil, fmt.Errorf("error parsing TargetFrom")
-		}
-
-		targetTo, err := parsePrice(stockDTO.TargetTo)
+		stock, err := stockDTO.ToStock()
 		if err != nil {
-			return nil, fmt.Errorf("error parsing TargetTo")
+			return nil, err
 		}
-
-		parsedTime, err := parseTime(stockDTO.Time)
-		if err != nil {
-			return nil, fmt.Errorf("error parsing Time")
-		}
-
-		stocks = append(stocks, domain.Stock{
-			Ticker:     stockDTO.Ticker,
-			TargetFrom: targetFrom,
-			TargetTo:   targetTo,
-			Company:    stockDTO.Company,
-			Brokerage:  stockDTO.Brokerage,
-			Action:     stockDTO.Action,
-			RatingFrom: stockDTO.RatingFrom,
-			RatingTo:   stockDTO.RatingTo,
-			Time:       parsedTime,
-		})
+		stock.Source = a.source
+		stock.ExternalID = syntheticExternalID(stock)
+		stocks = append(stocks, stock)
 	}
 
 	return &domain.APIResponse{
@@ -123,3 +117,10 @@ func (a *ExternalStockAPI) FetchStocks(nextPage string) (*domain.APIResponse, er
 		NextPage: apiResponseDTO.NextPage,
 	}, nil
 }
+
+// syntheticExternalID arma un ID para la API que no publica uno propio. Dos corredoras
+// pueden publicar el mismo ticker en el mismo segundo, así que el ID lleva la fecha con
+// toda su precisión, la corredora y la acción.
+func syntheticExternalID(stock domain.Stock) string {
+	return strings.Join([]string{stock.Ticker, stock.Time.UTC().Format(time.RFC3339Nano), stock.Brokerage, stock.Action}, "|")
+}
@@ -440,4 +440,23 @@ func TestFetchStocks_InvalidTimeFormat(t *testing.T) {
 	assert.Error(t, err)
 	assert.Nil(t, response)
 	assert.Contains(t, err.Error(), "error parsing Time")
-}
\ No newline at end of file
+}
+
+func TestSyntheticExternalID_DistinguishesSameSecondEvents(t *testing.T) {
+	at := time.Date(2024, 6, 1, 10, 0, 0, 0, time.UTC)
+	base := domain.Stock{Ticker: "AAPL", Brokerage: "Goldman Sachs", Action: "upgraded by", Time: at}
+
+	other := base
+	other.Brokerage = "Morgan Stanley"
+	later := base
+	later.Time = at.Add(300 * time.Millisecond)
+	downgrade := base
+	downgrade.Action = "downgraded by"
+
+	ids := map[string]bool{}
+	for _, stock := range []domain.Stock{base, other, later, downgrade} {
+		ids[syntheticExternalID(stock)] = true
+	}
+	assert.Len(t, ids, 4)
+	assert.Equal(t, syntheticExternalID(base), syntheticExternalID(base))
+}
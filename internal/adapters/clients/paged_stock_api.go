@@ -0,0 +1,183 @@
+package clients
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"recommender/internal/core/domain"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// pagedRatingDTO es el formato de los proveedores paginados por número de página:
+// precios numéricos, ratings y objetivos anidados y un ID propio por evento.
+type pagedRatingDTO struct {
+	ID          string `json:"id"`
+	Symbol      string `json:"symbol"`
+	CompanyName string `json:"company_name"`
+	Firm        string `json:"firm"`
+	Action      string `json:"action"`
+	Rating      struct {
+		From string `json:"from"`
+		To   string `json:"to"`
+	} `json:"rating"`
+	PriceTarget struct {
+		From float64 `json:"from"`
+		To   float64 `json:"to"`
+	} `json:"price_target"`
+	PublishedAt string `json:"published_at"`
+}
+
+type pagedResponseDTO struct {
+	Data []pagedRatingDTO `json:"data"`
+	Meta struct {
+		Page       int `json:"page"`
+		TotalPages int `json:"total_pages"`
+	} `json:"meta"`
+}
+
+// PagedStockAPI consume proveedores con respuesta {data, meta:{page, total_pages}},
+// paginación por `?page=N&per_page=M` y la API key en un encabezado configurable.
+type PagedStockAPI struct {
+	client     *http.Client
+	source     string
+	baseURL    string
+	apiKey     string
+	authHeader string
+	pageSize   int
+
+	mu         sync.Mutex
+	totalPages int // Último total_pages recibido; 0 si nunca respondió bien
+}
+
+func NewPagedStockAPI(source, baseURL, apiKey, authHeader string, pageSize int) *PagedStockAPI {
+	if authHeader == "" {
+		authHeader = "X-API-Key"
+	}
+	if pageSize <= 0 {
+		pageSize = 100
+	}
+	return &PagedStockAPI{
+		client:     &http.Client{Timeout: 10 * time.Second},
+		source:     source,
+		baseURL:    baseURL,
+		apiKey:     apiKey,
+		authHeader: authHeader,
+		pageSize:   pageSize,
+	}
+}
+
+// WithTransport reemplaza el transporte HTTP (cassettes, ResilientTransport); nil no
+// cambia nada. El transporte pasa a controlar los tiempos de espera de cada intento.
+func (a *PagedStockAPI) WithTransport(transport http.RoundTripper) *PagedStockAPI {
+	if transport != nil {
+		a.client.Transport = transport
+		a.client.Timeout = 0
+	}
+	return a
+}
+
+func (a *PagedStockAPI) Source() string {
+	return a.source
+}
+
+// FetchStocks pide la página indicada (la primera si nextPage está vacío) y devuelve
+// como NextPage el número de la siguiente, o vacío en la última.
+func (a *PagedStockAPI) FetchStocks(nextPage string) (*domain.APIResponse, error) {
+	page := 1
+	if nextPage != "" {
+		var err error
+		if page, err = strconv.Atoi(nextPage); err != nil || page < 1 {
+			return nil, fmt.Errorf("%s: página inválida '%s'", a.source, nextPage)
+		}
+	}
+
+	query := url.Values{}
+	query.Set("page", strconv.Itoa(page))
+	query.Set("per_page", strconv.Itoa(a.pageSize))
+	req, err := http.NewRequest("GET", a.baseURL+"?"+query.Encode(), nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set(a.authHeader, a.apiKey)
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := a.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("%s: API returned status: %d", a.source, resp.StatusCode)
+	}
+
+	var body pagedResponseDTO
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return nil, err
+	}
+	a.mu.Lock()
+	a.totalPages = body.Meta.TotalPages
+	a.mu.Unlock()
+
+	stocks := make([]domain.Stock, 0, len(body.Data))
+	for _, dto := range body.Data {
+		stock, err := a.toStock(dto)
+		if err != nil {
+			return nil, err
+		}
+		stocks = append(stocks, stock)
+	}
+
+	response := &domain.APIResponse{Items: stocks}
+	if body.Meta.Page < body.Meta.TotalPages {
+		response.NextPage = strconv.Itoa(body.Meta.Page + 1)
+	}
+	return response, nil
+}
+
+// NextPageAfter permite saltear una página que falló: la siguiente es el número que sigue,
+// siempre que no pase del último total_pages conocido. Si el proveedor nunca respondió
+// bien no se sabe cuántas páginas hay, así que no se saltea.
+func (a *PagedStockAPI) NextPageAfter(failed string) (string, bool) {
+	page := 1
+	if failed != "" {
+		var err error
+		if page, err = strconv.Atoi(failed); err != nil || page < 1 {
+			return "", false
+		}
+	}
+	a.mu.Lock()
+	totalPages := a.totalPages
+	a.mu.Unlock()
+	if totalPages == 0 || page+1 > totalPages {
+		return "", false
+	}
+	return strconv.Itoa(page + 1), true
+}
+
+func (a *PagedStockAPI) toStock(dto pagedRatingDTO) (domain.Stock, error) {
+	if strings.TrimSpace(dto.Symbol) == "" || dto.ID == "" {
+		return domain.Stock{}, fmt.Errorf("%s: evento sin symbol o id", a.source)
+	}
+	published, err := domain.ParseTime(dto.PublishedAt)
+	if err != nil {
+		return domain.Stock{}, fmt.Errorf("error parsing Time: %w", err)
+	}
+	return domain.Stock{
+		Ticker:     strings.TrimSpace(dto.Symbol),
+		Company:    dto.CompanyName,
+		Brokerage:  dto.Firm,
+		Action:     dto.Action,
+		RatingFrom: dto.Rating.From,
+		RatingTo:   dto.Rating.To,
+		TargetFrom: dto.PriceTarget.From,
+		TargetTo:   dto.PriceTarget.To,
+		Time:       published,
+		Source:     a.source,
+		ExternalID: dto.ID,
+	}, nil
+}
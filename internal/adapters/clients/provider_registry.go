@@ -0,0 +1,210 @@
+package clients
+
+import (
+	"fmt"
+	"net/http"
+	"os"
+	"recommender/internal/core/domain"
+	"recommender/internal/core/ports"
+	"strconv"
+	"strings"
+)
+
+// Tipos de proveedor configurables con PROVIDER_<NOMBRE>_TYPE.
+const (
+	ProviderTypeNextPage = "next_page" // Formato original, ver ExternalStockAPI
+	ProviderTypePaged    = "paged"     // Ver PagedStockAPI
+)
+
+// ProviderRegistry expone varios proveedores como un único StockAPIClient: recorre
+// todas las páginas del primero, luego las del segundo, etc. El token de página es
+// "<source>|<token del proveedor>", de modo que cada proveedor conserva su propio
+// estilo de paginación.
+type ProviderRegistry struct {
+	providers  []ports.StockProvider
+	transports []*ResilientTransport
+}
+
+func NewProviderRegistry() *ProviderRegistry {
+	return &ProviderRegistry{}
+}
+
+// Register agrega un proveedor; los nombres deben ser únicos porque identifican el Source.
+func (r *ProviderRegistry) Register(provider ports.StockProvider) error {
+	for _, existing := range r.providers {
+		if existing.Source() == provider.Source() {
+			return fmt.Errorf("proveedor duplicado '%s'", provider.Source())
+		}
+	}
+	r.providers = append(r.providers, provider)
+	return nil
+}
+
+// Sources lista los proveedores en el orden en que se recorren.
+func (r *ProviderRegistry) Sources() []string {
+	sources := make([]string, 0, len(r.providers))
+	for _, provider := range r.providers {
+		sources = append(sources, provider.Source())
+	}
+	return sources
+}
+
+func (r *ProviderRegistry) FetchStocks(nextPage string) (*domain.APIResponse, error) {
+	if len(r.providers) == 0 {
+		return &domain.APIResponse{}, nil
+	}
+
+	index, token := 0, ""
+	if nextPage != "" {
+		source, rest, _ := strings.Cut(nextPage, "|")
+		index = r.indexOf(source)
+		if index < 0 {
+			return nil, fmt.Errorf("token de página con proveedor desconocido '%s'", source)
+		}
+		token = rest
+	}
+
+	provider := r.providers[index]
+	response, err := provider.FetchStocks(token)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", provider.Source(), err)
+	}
+	for i := range response.Items {
+		if response.Items[i].Source == "" {
+			response.Items[i].Source = provider.Source()
+		}
+	}
+
+	switch {
+	case response.NextPage != "":
+		response.NextPage = provider.Source() + "|" + response.NextPage
+	case index+1 < len(r.providers):
+		response.NextPage = r.providers[index+1].Source() + "|"
+	}
+	return response, nil
+}
+
+// NextPageAfter saltea una página que falló. Si el proveedor no sabe cuál le sigue, se
+// pasa a la primera página del proveedor siguiente; en el último, no hay cursor conocido.
+func (r *ProviderRegistry) NextPageAfter(failed string) (string, bool) {
+	if len(r.providers) == 0 {
+		return "", false
+	}
+	index, token := 0, ""
+	if failed != "" {
+		source, rest, _ := strings.Cut(failed, "|")
+		if index = r.indexOf(source); index < 0 {
+			return "", false
+		}
+		token = rest
+	}
+
+	provider := r.providers[index]
+	if skipper, ok := provider.(ports.PageSkipper); ok {
+		if next, ok := skipper.NextPageAfter(token); ok && next != "" {
+			return provider.Source() + "|" + next, true
+		}
+	}
+	if index+1 < len(r.providers) {
+		return r.providers[index+1].Source() + "|", true
+	}
+	return "", false
+}
+
+// UpstreamStats reporta reintentos, 429 y estado del circuito de cada proveedor
+// creado con NewProvidersFromEnv.
+func (r *ProviderRegistry) UpstreamStats() []domain.UpstreamStats {
+	stats := make([]domain.UpstreamStats, 0, len(r.transports))
+	for _, transport := range r.transports {
+		stats = append(stats, transport.Stats())
+	}
+	return stats
+}
+
+func (r *ProviderRegistry) indexOf(source string) int {
+	for i, provider := range r.providers {
+		if provider.Source() == source {
+			return i
+		}
+	}
+	return -1
+}
+
+// NewProvidersFromEnv arma el registro con STOCK_PROVIDERS (nombres separados por coma)
+// y, por cada nombre, PROVIDER_<NOMBRE>_TYPE, _URL, _API_KEY y, para "paged",
+// _AUTH_HEADER y _PAGE_SIZE. Sin STOCK_PROVIDERS se usa solo el proveedor original
+// configurado con API_URL y API_KEY. Cada proveedor llama a la red con su propio
+// ResilientTransport según policy; API_CASSETTE_MODE aplica a todos, ver
+// CassetteTransportFromEnv.
+func NewProvidersFromEnv(policy ResiliencePolicy) (*ProviderRegistry, error) {
+	registry := NewProviderRegistry()
+	names := strings.TrimSpace(os.Getenv("STOCK_PROVIDERS"))
+	if names == "" {
+		api := NewExternalStockAPI().(*ExternalStockAPI)
+		transport, err := registry.transportFor(api.source, api.apiKey, policy)
+		if err != nil {
+			return nil, err
+		}
+		return registry, registry.Register(api.WithTransport(transport))
+	}
+
+	for _, name := range strings.Split(names, ",") {
+		name = strings.TrimSpace(name)
+		if name == "" {
+			continue
+		}
+		provider, err := registry.providerFromEnv(name, policy)
+		if err != nil {
+			return nil, err
+		}
+		if err := registry.Register(provider); err != nil {
+			return nil, err
+		}
+	}
+	return registry, nil
+}
+
+// transportFor arma la cadena de transportes de un proveedor. Al reproducir cassettes
+// no hay red, así que no se limita el ritmo ni se reintenta.
+func (r *ProviderRegistry) transportFor(source, secret string, policy ResiliencePolicy) (http.RoundTripper, error) {
+	cassettes, err := CassetteTransportFromEnv(source, secret)
+	if err != nil {
+		return nil, err
+	}
+	if _, replay := cassettes.(*ReplayTransport); replay {
+		return cassettes, nil
+	}
+	transport := NewResilientTransport(cassettes, source, policy)
+	r.transports = append(r.transports, transport)
+	return transport, nil
+}
+
+func (r *ProviderRegistry) providerFromEnv(name string, policy ResiliencePolicy) (ports.StockProvider, error) {
+	prefix := "PROVIDER_" + strings.ToUpper(strings.ReplaceAll(name, "-", "_")) + "_"
+	env := func(key string) string { return strings.TrimSpace(os.Getenv(prefix + key)) }
+
+	baseURL, apiKey := env("URL"), env("API_KEY")
+	if baseURL == "" || apiKey == "" {
+		return nil, fmt.Errorf("proveedor '%s': faltan %sURL o %sAPI_KEY", name, prefix, prefix)
+	}
+
+	transport, err := r.transportFor(name, apiKey, policy)
+	if err != nil {
+		return nil, err
+	}
+
+	switch providerType := env("TYPE"); providerType {
+	case "", ProviderTypeNextPage:
+		return NewExternalStockAPIProvider(name, baseURL, apiKey).WithTransport(transport), nil
+	case ProviderTypePaged:
+		pageSize := 0
+		if raw := env("PAGE_SIZE"); raw != "" {
+			if pageSize, err = strconv.Atoi(raw); err != nil {
+				return nil, fmt.Errorf("proveedor '%s': %sPAGE_SIZE inválido", name, prefix)
+			}
+		}
+		return NewPagedStockAPI(name, baseURL, apiKey, env("AUTH_HEADER"), pageSize).WithTransport(transport), nil
+	default:
+		return nil, fmt.Errorf("proveedor '%s': tipo desconocido '%s'", name, providerType)
+	}
+}
@@ -0,0 +1,201 @@
+package clients
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"recommender/internal/core/domain"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func pagedServer(t *testing.T, pages int) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "secret", r.Header.Get("X-Token"))
+		assert.Equal(t, "2", r.URL.Query().Get("per_page"))
+		page := r.URL.Query().Get("page")
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{
+			"data": [{
+				"id": "evt-` + page + `",
+				"symbol": "NVDA",
+				"company_name": "NVIDIA",
+				"firm": "Bank of America",
+				"action": "upgraded by",
+				"rating": {"from": "Neutral", "to": "Buy"},
+				"price_target": {"from": 450, "to": 500.5},
+				"published_at": "2024-06-0` + page + `T10:00:00Z"
+			}],
+			"meta": {"page": ` + page + `, "total_pages": ` + string(rune('0'+pages)) + `}
+		}`))
+	}))
+}
+
+func TestPagedStockAPI_FetchStocks(t *testing.T) {
+	server := pagedServer(t, 2)
+	defer server.Close()
+	api := NewPagedStockAPI("acme", server.URL, "secret", "X-Token", 2)
+
+	first, err := api.FetchStocks("")
+	require.NoError(t, err)
+	require.Len(t, first.Items, 1)
+	assert.Equal(t, "2", first.NextPage)
+
+	stock := first.Items[0]
+	assert.Equal(t, "NVDA", stock.Ticker)
+	assert.Equal(t, "Bank of America", stock.Brokerage)
+	assert.Equal(t, "Buy", stock.RatingTo)
+	assert.Equal(t, 500.5, stock.TargetTo)
+	assert.Equal(t, "acme", stock.Source)
+	assert.Equal(t, "evt-1", stock.ExternalID)
+	assert.True(t, time.Date(2024, 6, 1, 10, 0, 0, 0, time.UTC).Equal(stock.Time))
+
+	last, err := api.FetchStocks("2")
+	require.NoError(t, err)
+	assert.Equal(t, "", last.NextPage)
+
+	_, err = api.FetchStocks("abc")
+	assert.Error(t, err)
+}
+
+func TestProviderRegistry_WalksEveryProvider(t *testing.T) {
+	legacy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "Bearer legacy-key", r.Header.Get("Authorization"))
+		response := domain.APIResponseDTO{Items: []domain.StockDTO{{
+			Ticker: "AAPL", Brokerage: "Goldman Sachs", Action: "upgraded by", TargetFrom: "$150.00", TargetTo: "$180.00", Time: "2024-06-01T10:00:00.25Z",
+		}}}
+		if r.URL.Query().Get("next_page") == "" {
+			response.NextPage = "cursor-2"
+		}
+		json.NewEncoder(w).Encode(response)
+	}))
+	defer legacy.Close()
+	paged := pagedServer(t, 2)
+	defer paged.Close()
+
+	registry := NewProviderRegistry()
+	require.NoError(t, registry.Register(NewExternalStockAPIProvider("legacy", legacy.URL, "legacy-key")))
+	require.NoError(t, registry.Register(NewPagedStockAPI("acme", paged.URL, "secret", "X-Token", 2)))
+	assert.Error(t, registry.Register(NewPagedStockAPI("acme", paged.URL, "secret", "X-Token", 2)))
+	assert.Equal(t, []string{"legacy", "acme"}, registry.Sources())
+
+	var stocks []domain.Stock
+	var pages []string
+	next := ""
+	for {
+		response, err := registry.FetchStocks(next)
+		require.NoError(t, err)
+		stocks = append(stocks, response.Items...)
+		if response.NextPage == "" {
+			break
+		}
+		next = response.NextPage
+		pages = append(pages, next)
+	}
+
+	assert.Equal(t, []string{"legacy|cursor-2", "acme|", "acme|2"}, pages)
+	require.Len(t, stocks, 4)
+	assert.Equal(t, "legacy", stocks[0].Source)
+	assert.Equal(t, "AAPL|2024-06-01T10:00:00.25Z|Goldman Sachs|upgraded by", stocks[0].ExternalID)
+	assert.Equal(t, "acme", stocks[3].Source)
+	assert.Equal(t, "evt-2", stocks[3].ExternalID)
+
+	_, err := registry.FetchStocks("unknown|1")
+	assert.Error(t, err)
+}
+
+func TestNewProvidersFromEnv(t *testing.T) {
+	vars := map[string]string{
+		"STOCK_PROVIDERS":                "legacy, acme-data",
+		"PROVIDER_LEGACY_URL":            "https://legacy.example.com",
+		"PROVIDER_LEGACY_API_KEY":        "k1",
+		"PROVIDER_ACME_DATA_TYPE":        "paged",
+		"PROVIDER_ACME_DATA_URL":         "https://acme.example.com/ratings",
+		"PROVIDER_ACME_DATA_API_KEY":     "k2",
+		"PROVIDER_ACME_DATA_PAGE_SIZE":   "50",
+		"PROVIDER_ACME_DATA_AUTH_HEADER": "X-Token",
+	}
+	for key, value := range vars {
+		t.Setenv(key, value)
+	}
+
+	registry, err := NewProvidersFromEnv(DefaultResiliencePolicy())
+	require.NoError(t, err)
+	assert.Equal(t, []string{"legacy", "acme-data"}, registry.Sources())
+	paged := registry.providers[1].(*PagedStockAPI)
+	assert.Equal(t, 50, paged.pageSize)
+	assert.Equal(t, "X-Token", paged.authHeader)
+	stats := registry.UpstreamStats()
+	require.Len(t, stats, 2)
+	assert.Equal(t, "acme-data", stats[1].Source)
+	assert.Equal(t, domain.CircuitClosed, stats[1].Circuit)
+
+	t.Setenv("PROVIDER_ACME_DATA_TYPE", "soap")
+	_, err = NewProvidersFromEnv(DefaultResiliencePolicy())
+	assert.ErrorContains(t, err, "tipo desconocido")
+
+	// Sin STOCK_PROVIDERS se usa el proveedor original
+	t.Setenv("STOCK_PROVIDERS", "")
+	t.Setenv("API_URL", "https://api.example.com")
+	t.Setenv("API_KEY", "key")
+	registry, err = NewProvidersFromEnv(DefaultResiliencePolicy())
+	require.NoError(t, err)
+	assert.Equal(t, []string{DefaultSource}, registry.Sources())
+}
+
+func TestProviderRegistry_NextPageAfter(t *testing.T) {
+	registry := NewProviderRegistry()
+	paged := NewPagedStockAPI("acme", "http://acme", "k", "", 10)
+	require.NoError(t, registry.Register(NewExternalStockAPIProvider("legacy", "http://legacy", "k")))
+	require.NoError(t, registry.Register(paged))
+
+	// Sin ninguna respuesta buena no se sabe cuántas páginas tiene el proveedor paginado
+	_, ok := registry.NextPageAfter("acme|")
+	assert.False(t, ok)
+	paged.totalPages = 3
+
+	// El formato next_page no permite adivinar el cursor: se pasa al siguiente proveedor
+	next, ok := registry.NextPageAfter("legacy|cursor-2")
+	assert.True(t, ok)
+	assert.Equal(t, "acme|", next)
+
+	next, ok = registry.NextPageAfter("acme|")
+	assert.True(t, ok)
+	assert.Equal(t, "acme|2", next)
+	next, ok = registry.NextPageAfter("acme|2")
+	assert.True(t, ok)
+	assert.Equal(t, "acme|3", next)
+	_, ok = registry.NextPageAfter("acme|3") // Pasado el total_pages conocido
+	assert.False(t, ok)
+
+	_, ok = registry.NextPageAfter("unknown|1")
+	assert.False(t, ok)
+
+	single := NewProviderRegistry()
+	require.NoError(t, single.Register(NewExternalStockAPIProvider("legacy", "http://legacy", "k")))
+	_, ok = single.NextPageAfter("")
+	assert.False(t, ok)
+}
+
+func TestPagedStockAPI_NextPageAfterAlwaysFailingProvider(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+	api := NewPagedStockAPI("acme", server.URL, "k", "", 10)
+
+	page := ""
+	for range 3 {
+		_, err := api.FetchStocks(page)
+		require.Error(t, err)
+		next, ok := api.NextPageAfter(page)
+		if !ok {
+			return
+		}
+		page = next
+	}
+	t.Fatalf("NextPageAfter siguió devolviendo páginas de un proveedor que nunca respondió")
+}
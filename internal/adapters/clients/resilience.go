@@ -0,0 +1,328 @@
+package clients
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"math/rand/v2"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"recommender/internal/core/domain"
+)
+
+// ErrCircuitOpen se devuelve sin llamar al proveedor mientras su circuito está abierto.
+var ErrCircuitOpen = errors.New("circuit breaker abierto")
+
+// ResiliencePolicy configura cómo se llama a un proveedor externo. Un valor en cero
+// desactiva la parte correspondiente (sin límite de ritmo, sin reintentos, sin breaker).
+type ResiliencePolicy struct {
+	RatePerSecond    float64       // Ritmo sostenido del token bucket
+	Burst            int           // Peticiones que se pueden hacer de golpe
+	MaxAttempts      int           // Intentos por petición, incluido el primero
+	AttemptTimeout   time.Duration // Límite de cada intento, incluida la lectura del cuerpo
+	BackoffBase      time.Duration // Espera máxima del primer reintento; se duplica en cada uno
+	BackoffMax       time.Duration
+	MaxRetryAfter    time.Duration // Un Retry-After mayor no se espera: la petición falla
+	BreakerThreshold int           // Fallos seguidos que abren el circuito
+	BreakerCooldown  time.Duration // Tiempo abierto antes de dejar pasar una llamada de prueba
+}
+
+func DefaultResiliencePolicy() ResiliencePolicy {
+	return ResiliencePolicy{
+		RatePerSecond:    5,
+		Burst:            5,
+		MaxAttempts:      4,
+		AttemptTimeout:   10 * time.Second,
+		BackoffBase:      500 * time.Millisecond,
+		BackoffMax:       30 * time.Second,
+		MaxRetryAfter:    2 * time.Minute,
+		BreakerThreshold: 5,
+		BreakerCooldown:  time.Minute,
+	}
+}
+
+// ResilientTransport envuelve el transporte de un proveedor con un token bucket,
+// reintentos con backoff exponencial y jitter (respetando Retry-After) y un circuit
+// breaker que deja de llamar tras varios fallos seguidos.
+type ResilientTransport struct {
+	next   http.RoundTripper
+	source string
+	policy ResiliencePolicy
+
+	now    func() time.Time
+	sleep  func(ctx context.Context, d time.Duration) error
+	jitter func(max time.Duration) time.Duration
+
+	mu        sync.Mutex
+	tokens    float64
+	refilled  time.Time
+	blocked   time.Time // Un Retry-After frena también a las peticiones siguientes
+	state     string
+	openUntil time.Time
+	trial     bool // Hay una llamada de prueba en curso con el circuito semiabierto
+	stats     domain.UpstreamStats
+}
+
+func NewResilientTransport(next http.RoundTripper, source string, policy ResiliencePolicy) *ResilientTransport {
+	if next == nil {
+		next = http.DefaultTransport
+	}
+	return &ResilientTransport{
+		next:   next,
+		source: source,
+		policy: policy,
+		now:    time.Now,
+		sleep:  sleepContext,
+		jitter: func(max time.Duration) time.Duration {
+			if max <= 0 {
+				return 0
+			}
+			return rand.N(max + 1)
+		},
+		tokens: float64(policy.Burst),
+		state:  domain.CircuitClosed,
+		stats:  domain.UpstreamStats{Source: source},
+	}
+}
+
+func sleepContext(ctx context.Context, d time.Duration) error {
+	if d <= 0 {
+		return nil
+	}
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (t *ResilientTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if err := t.allow(); err != nil {
+		return nil, err
+	}
+	// Solo se reintentan peticiones sin cuerpo, como las de FetchStocks
+	retriable := req.Body == nil || req.Body == http.NoBody
+
+	for attempt := 1; ; attempt++ {
+		if err := t.sleep(req.Context(), t.reserve()); err != nil {
+			t.release()
+			return nil, err
+		}
+
+		resp, cancel, err := t.attempt(req)
+		if err != nil && req.Context().Err() != nil {
+			// Una cancelación del llamador no dice nada de la salud del proveedor
+			t.release()
+			return nil, err
+		}
+		delay, transient, reason := t.classify(resp, err, attempt)
+		if !transient {
+			t.finish(true, "")
+			return resp, err
+		}
+		if !retriable || attempt >= t.policy.MaxAttempts || delay < 0 {
+			t.finish(false, reason)
+			return resp, err
+		}
+
+		if resp != nil {
+			io.Copy(io.Discard, resp.Body)
+			resp.Body.Close()
+		}
+		cancel()
+		t.record(func(s *domain.UpstreamStats) { s.Retries++ })
+		log.Printf("⏳ %s: %s, reintento %d/%d en %s", t.source, reason, attempt, t.policy.MaxAttempts-1, delay)
+		if err := t.sleep(req.Context(), delay); err != nil {
+			t.release()
+			return nil, err
+		}
+	}
+}
+
+// attempt hace una llamada con su propio límite de tiempo. El límite sigue corriendo
+// mientras se lee el cuerpo y se libera al cerrarlo.
+func (t *ResilientTransport) attempt(req *http.Request) (*http.Response, context.CancelFunc, error) {
+	if t.policy.AttemptTimeout <= 0 {
+		resp, err := t.next.RoundTrip(req)
+		return resp, func() {}, err
+	}
+	ctx, cancel := context.WithTimeout(req.Context(), t.policy.AttemptTimeout)
+	resp, err := t.next.RoundTrip(req.WithContext(ctx))
+	if err != nil {
+		cancel()
+		return nil, cancel, err
+	}
+	resp.Body = cancelOnClose{ReadCloser: resp.Body, cancel: cancel}
+	return resp, cancel, nil
+}
+
+type cancelOnClose struct {
+	io.ReadCloser
+	cancel context.CancelFunc
+}
+
+func (b cancelOnClose) Close() error {
+	err := b.ReadCloser.Close()
+	b.cancel()
+	return err
+}
+
+// classify decide si la respuesta es un fallo transitorio y cuánto esperar antes de
+// reintentar. Una espera negativa significa que no vale la pena reintentar.
+func (t *ResilientTransport) classify(resp *http.Response, err error, attempt int) (time.Duration, bool, string) {
+	t.record(func(s *domain.UpstreamStats) { s.Requests++ })
+	if err != nil {
+		return t.backoff(attempt), true, err.Error()
+	}
+
+	switch resp.StatusCode {
+	case http.StatusTooManyRequests, http.StatusInternalServerError, http.StatusBadGateway,
+		http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+	default:
+		return 0, false, ""
+	}
+	reason := fmt.Sprintf("status %d", resp.StatusCode)
+	if resp.StatusCode == http.StatusTooManyRequests {
+		t.record(func(s *domain.UpstreamStats) { s.RateLimited++ })
+	}
+
+	retryAfter, ok := parseRetryAfter(resp.Header.Get("Retry-After"), t.now())
+	if !ok {
+		return t.backoff(attempt), true, reason
+	}
+	if retryAfter > t.policy.MaxRetryAfter {
+		return -1, true, fmt.Sprintf("%s con Retry-After de %s", reason, retryAfter)
+	}
+	t.mu.Lock()
+	if until := t.now().Add(retryAfter); until.After(t.blocked) {
+		t.blocked = until
+	}
+	t.mu.Unlock()
+	return retryAfter, true, reason
+}
+
+// backoff es "full jitter": un valor al azar entre 0 y base·2^(intento-1), con tope.
+func (t *ResilientTransport) backoff(attempt int) time.Duration {
+	ceiling := t.policy.BackoffBase << (attempt - 1)
+	if ceiling <= 0 || (t.policy.BackoffMax > 0 && ceiling > t.policy.BackoffMax) {
+		ceiling = t.policy.BackoffMax
+	}
+	return t.jitter(ceiling)
+}
+
+// parseRetryAfter acepta segundos o una fecha HTTP.
+func parseRetryAfter(value string, now time.Time) (time.Duration, bool) {
+	if value == "" {
+		return 0, false
+	}
+	if seconds, err := strconv.Atoi(value); err == nil && seconds >= 0 {
+		return time.Duration(seconds) * time.Second, true
+	}
+	if at, err := http.ParseTime(value); err == nil {
+		return max(at.Sub(now), 0), true
+	}
+	return 0, false
+}
+
+// reserve toma un token del bucket y devuelve cuánto hay que esperar para usarlo.
+func (t *ResilientTransport) reserve() time.Duration {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	now := t.now()
+
+	var wait time.Duration
+	if t.policy.RatePerSecond > 0 {
+		if !t.refilled.IsZero() {
+			t.tokens += now.Sub(t.refilled).Seconds() * t.policy.RatePerSecond
+		}
+		t.tokens = min(t.tokens, float64(max(t.policy.Burst, 1)))
+		t.refilled = now
+		t.tokens--
+		if t.tokens < 0 {
+			wait = time.Duration(-t.tokens / t.policy.RatePerSecond * float64(time.Second))
+		}
+	}
+	if blocked := t.blocked.Sub(now); blocked > wait {
+		wait = blocked
+	}
+	return wait
+}
+
+// allow aplica el circuit breaker antes de cada petición.
+func (t *ResilientTransport) allow() error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.state == domain.CircuitClosed {
+		return nil
+	}
+	if t.state == domain.CircuitOpen && !t.now().Before(t.openUntil) {
+		t.state = domain.CircuitHalfOpen
+		log.Printf("🔌 %s: circuito semiabierto, se prueba una llamada", t.source)
+	}
+	if t.state == domain.CircuitHalfOpen && !t.trial {
+		t.trial = true
+		return nil
+	}
+	t.stats.Rejected++
+	return fmt.Errorf("%s: %w hasta %s", t.source, ErrCircuitOpen, t.openUntil.Format(time.RFC3339))
+}
+
+// finish registra el resultado de una petición completa (con sus reintentos) en el breaker.
+func (t *ResilientTransport) finish(ok bool, reason string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.trial = false
+	if ok {
+		if t.state != domain.CircuitClosed {
+			log.Printf("🔌 %s: circuito cerrado", t.source)
+		}
+		t.state = domain.CircuitClosed
+		t.stats.ConsecutiveFailures = 0
+		return
+	}
+
+	t.stats.Failures++
+	t.stats.ConsecutiveFailures++
+	t.stats.LastError = reason
+	threshold := t.policy.BreakerThreshold
+	if threshold > 0 && (t.state == domain.CircuitHalfOpen || t.stats.ConsecutiveFailures >= threshold) {
+		t.state = domain.CircuitOpen
+		t.openUntil = t.now().Add(t.policy.BreakerCooldown)
+		log.Printf("🔌 %s: circuito abierto tras %d fallos seguidos, hasta %s",
+			t.source, t.stats.ConsecutiveFailures, t.openUntil.Format(time.RFC3339))
+	}
+}
+
+// release libera la llamada de prueba sin contar éxito ni fallo.
+func (t *ResilientTransport) release() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.trial = false
+}
+
+func (t *ResilientTransport) record(update func(*domain.UpstreamStats)) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	update(&t.stats)
+}
+
+// Stats devuelve una copia de las métricas del proveedor.
+func (t *ResilientTransport) Stats() domain.UpstreamStats {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	stats := t.stats
+	stats.Circuit = t.state
+	if t.state == domain.CircuitOpen {
+		openUntil := t.openUntil
+		stats.OpenUntil = &openUntil
+	}
+	return stats
+}
@@ -0,0 +1,170 @@
+package clients
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"recommender/internal/core/domain"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeClock hace que las esperas del transporte avancen el reloj sin dormir.
+type fakeClock struct {
+	now    time.Time
+	sleeps []time.Duration
+}
+
+func (c *fakeClock) install(t *ResilientTransport) *ResilientTransport {
+	t.now = func() time.Time { return c.now }
+	t.sleep = func(_ context.Context, d time.Duration) error {
+		if d > 0 {
+			c.sleeps = append(c.sleeps, d)
+			c.now = c.now.Add(d)
+		}
+		return nil
+	}
+	t.jitter = func(max time.Duration) time.Duration { return max } // El peor caso, para que sea determinista
+	return t
+}
+
+// statusSequence responde con los códigos indicados, uno por petición, y luego 200.
+func statusSequence(t *testing.T, headers map[int]string, statuses ...int) (*httptest.Server, *int32) {
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := int(atomic.AddInt32(&calls, 1)) - 1
+		if n < len(statuses) {
+			if retryAfter, ok := headers[n]; ok {
+				w.Header().Set("Retry-After", retryAfter)
+			}
+			w.WriteHeader(statuses[n])
+			return
+		}
+		w.Write([]byte(`{"items":[],"next_page":""}`))
+	}))
+	t.Cleanup(server.Close)
+	return server, &calls
+}
+
+func testPolicy() ResiliencePolicy {
+	return ResiliencePolicy{
+		MaxAttempts:      4,
+		BackoffBase:      100 * time.Millisecond,
+		BackoffMax:       time.Second,
+		MaxRetryAfter:    time.Minute,
+		BreakerThreshold: 2,
+		BreakerCooldown:  30 * time.Second,
+	}
+}
+
+func TestResilientTransport_RetriesWithBackoffAndRetryAfter(t *testing.T) {
+	server, calls := statusSequence(t, map[int]string{1: "7"}, 503, 429, 502)
+	clock := &fakeClock{now: time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)}
+	transport := clock.install(NewResilientTransport(nil, "legacy", testPolicy()))
+
+	_, err := NewExternalStockAPIProvider("legacy", server.URL, "k").WithTransport(transport).FetchStocks("")
+	require.NoError(t, err)
+	assert.Equal(t, int32(4), *calls)
+	// Backoff exponencial 100ms, 200ms (el 429 impone 7s) y luego 400ms
+	assert.Equal(t, []time.Duration{100 * time.Millisecond, 7 * time.Second, 400 * time.Millisecond}, clock.sleeps)
+
+	stats := transport.Stats()
+	assert.Equal(t, 4, stats.Requests)
+	assert.Equal(t, 3, stats.Retries)
+	assert.Equal(t, 1, stats.RateLimited)
+	assert.Equal(t, 0, stats.Failures)
+	assert.Equal(t, domain.CircuitClosed, stats.Circuit)
+}
+
+func TestResilientTransport_GivesUpOnLongRetryAfterAndClientErrors(t *testing.T) {
+	server, calls := statusSequence(t, map[int]string{0: "3600"}, 429, 404)
+	clock := &fakeClock{now: time.Now()}
+	transport := clock.install(NewResilientTransport(nil, "legacy", testPolicy()))
+	api := NewExternalStockAPIProvider("legacy", server.URL, "k").WithTransport(transport)
+
+	_, err := api.FetchStocks("")
+	assert.ErrorContains(t, err, "429")
+	assert.Equal(t, int32(1), *calls)
+	assert.Empty(t, clock.sleeps)
+
+	_, err = api.FetchStocks("")
+	assert.ErrorContains(t, err, "404") // Un 4xx no se reintenta
+	assert.Equal(t, int32(2), *calls)
+}
+
+func TestResilientTransport_CircuitBreaker(t *testing.T) {
+	server, calls := statusSequence(t, nil, 500, 500, 500, 500)
+	clock := &fakeClock{now: time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)}
+	policy := testPolicy()
+	policy.MaxAttempts = 1
+	transport := clock.install(NewResilientTransport(nil, "legacy", policy))
+	api := NewExternalStockAPIProvider("legacy", server.URL, "k").WithTransport(transport)
+
+	for range 2 {
+		_, err := api.FetchStocks("")
+		assert.ErrorContains(t, err, "500")
+	}
+	_, err := api.FetchStocks("")
+	assert.True(t, errors.Is(err, ErrCircuitOpen))
+	assert.Equal(t, int32(2), *calls) // Con el circuito abierto no se llama
+
+	stats := transport.Stats()
+	assert.Equal(t, domain.CircuitOpen, stats.Circuit)
+	assert.Equal(t, 1, stats.Rejected)
+	assert.Equal(t, "status 500", stats.LastError)
+	require.NotNil(t, stats.OpenUntil)
+
+	// Tras el enfriamiento pasa una llamada de prueba; si falla vuelve a abrirse
+	clock.now = clock.now.Add(31 * time.Second)
+	_, err = api.FetchStocks("")
+	assert.ErrorContains(t, err, "500")
+	assert.Equal(t, domain.CircuitOpen, transport.Stats().Circuit)
+
+	// Si la prueba sale bien, se cierra
+	clock.now = clock.now.Add(31 * time.Second)
+	_, err = api.FetchStocks("")
+	assert.ErrorContains(t, err, "500")
+	clock.now = clock.now.Add(31 * time.Second)
+	_, err = api.FetchStocks("")
+	require.NoError(t, err)
+	assert.Equal(t, domain.CircuitClosed, transport.Stats().Circuit)
+	assert.Equal(t, 0, transport.Stats().ConsecutiveFailures)
+}
+
+func TestResilientTransport_TokenBucket(t *testing.T) {
+	server, _ := statusSequence(t, nil)
+	clock := &fakeClock{now: time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)}
+	policy := testPolicy()
+	policy.RatePerSecond = 2
+	policy.Burst = 2
+	transport := clock.install(NewResilientTransport(nil, "legacy", policy))
+	api := NewExternalStockAPIProvider("legacy", server.URL, "k").WithTransport(transport)
+
+	for range 4 {
+		_, err := api.FetchStocks("")
+		require.NoError(t, err)
+	}
+	// Dos de golpe y luego una cada medio segundo
+	assert.Equal(t, []time.Duration{500 * time.Millisecond, 500 * time.Millisecond}, clock.sleeps)
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	now := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+
+	d, ok := parseRetryAfter("120", now)
+	assert.True(t, ok)
+	assert.Equal(t, 2*time.Minute, d)
+
+	d, ok = parseRetryAfter(now.Add(30*time.Second).Format(http.TimeFormat), now)
+	assert.True(t, ok)
+	assert.Equal(t, 30*time.Second, d)
+
+	_, ok = parseRetryAfter("soon", now)
+	assert.False(t, ok)
+}
@@ -0,0 +1,28 @@
+package export
+
+import (
+	"encoding/csv"
+	"io"
+)
+
+type CSVWriter struct {
+	w      *csv.Writer
+	record []string
+}
+
+func NewCSVWriter(w io.Writer) *CSVWriter {
+	return &CSVWriter{w: csv.NewWriter(w)}
+}
+
+func (c *CSVWriter) WriteRow(cells []any) error {
+	c.record = c.record[:0]
+	for _, cell := range cells {
+		c.record = append(c.record, formatCell(cell))
+	}
+	return c.w.Write(c.record)
+}
+
+func (c *CSVWriter) Close() error {
+	c.w.Flush()
+	return c.w.Error()
+}
@@ -0,0 +1,92 @@
+// Package export escribe tablas de stocks y recomendaciones en CSV y XLSX, fila por
+// fila, para que las exportaciones grandes no se construyan en memoria.
+package export
+
+import (
+	"io"
+	"strconv"
+	"time"
+
+	"recommender/internal/core/domain"
+)
+
+const (
+	FormatCSV  = "csv"
+	FormatXLSX = "xlsx"
+
+	ContentTypeCSV  = "text/csv"
+	ContentTypeXLSX = "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+)
+
+// Writer recibe las filas en orden; Close termina el archivo.
+// Las celdas pueden ser string, int, float64 o time.Time.
+type Writer interface {
+	WriteRow(cells []any) error
+	Close() error
+}
+
+// NewWriter crea el writer del formato indicado, o nil si el formato no existe.
+func NewWriter(format string, w io.Writer, sheet string) Writer {
+	switch format {
+	case FormatCSV:
+		return NewCSVWriter(w)
+	case FormatXLSX:
+		return NewXLSXWriter(w, sheet)
+	default:
+		return nil
+	}
+}
+
+// ContentType devuelve el tipo MIME del formato.
+func ContentType(format string) string {
+	if format == FormatXLSX {
+		return ContentTypeXLSX
+	}
+	return ContentTypeCSV
+}
+
+// StockHeader son las columnas de un evento de rating.
+var StockHeader = []any{
+	"id", "ticker", "company", "brokerage", "action", "action_type", "rating_from", "rating_to",
+	"rating_from_score", "rating_to_score", "target_from", "target_to", "time", "source", "external_id",
+}
+
+func StockRow(stock domain.Stock) []any {
+	return []any{
+		int(stock.ID), stock.Ticker, stock.Company, stock.Brokerage, stock.Action, string(stock.ActionType),
+		stock.RatingFrom, stock.RatingTo, int(stock.RatingFromScore), int(stock.RatingToScore),
+		stock.TargetFrom, stock.TargetTo, stock.Time, stock.Source, stock.ExternalID,
+	}
+}
+
+// RecommendationHeader son las columnas de un elemento del ranking con su desglose.
+var RecommendationHeader = []any{
+	"rank", "ticker", "company", "sector", "score", "price_impact_pct", "rating_impact", "action_impact",
+	"brokerage_weight", "decay_factor", "age_days", "brokerage", "rating_to", "target_from", "target_to",
+	"time", "rationale",
+}
+
+func RecommendationRow(rec domain.Recommendation) []any {
+	return []any{
+		rec.Rank, rec.Ticker, rec.Company, rec.Sector, rec.Score, rec.Breakdown.PriceImpact,
+		rec.Breakdown.RatingImpact, rec.Breakdown.ActionImpact, rec.Breakdown.BrokerageWeight,
+		rec.Breakdown.DecayFactor, rec.Breakdown.AgeDays, rec.Stock.Brokerage, rec.Stock.RatingTo,
+		rec.Stock.TargetFrom, rec.Stock.TargetTo, rec.Stock.Time, rec.Breakdown.Rationale,
+	}
+}
+
+// formatCell convierte una celda a texto; las fechas se escriben en RFC3339.
+func formatCell(cell any) string {
+	switch v := cell.(type) {
+	case string:
+		return v
+	case int:
+		return strconv.Itoa(v)
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case time.Time:
+		return v.UTC().Format(time.RFC3339)
+	default:
+		return ""
+	}
+}
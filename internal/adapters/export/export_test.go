@@ -0,0 +1,81 @@
+package export
+
+import (
+	"archive/zip"
+	"bytes"
+	"io"
+	"testing"
+	"time"
+
+	"recommender/internal/core/domain"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func sampleStock() domain.Stock {
+	return domain.Stock{
+		ID: 7, Ticker: "AAPL", Company: "Apple, Inc.", Brokerage: "Goldman <Sachs>", ActionType: domain.ActionUpgrade,
+		RatingFrom: "Hold", RatingTo: "Buy", RatingToScore: domain.RatingBuy, TargetFrom: 150, TargetTo: 180.5, Source: "primary", ExternalID: "r-1",
+		Time: time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC),
+	}
+}
+
+func TestCSVWriter(t *testing.T) {
+	var buf bytes.Buffer
+	w := NewWriter(FormatCSV, &buf, "stocks")
+	assert.Nil(t, w.WriteRow(StockHeader))
+	assert.Nil(t, w.WriteRow(StockRow(sampleStock())))
+	assert.Nil(t, w.Close())
+
+	lines := bytes.Split(bytes.TrimSpace(buf.Bytes()), []byte("\n"))
+	assert.Len(t, lines, 2)
+	assert.Equal(t, "id,ticker,company,brokerage,action,action_type,rating_from,rating_to,rating_from_score,rating_to_score,target_from,target_to,time,source,external_id", string(lines[0]))
+	assert.Equal(t, `7,AAPL,"Apple, Inc.",Goldman <Sachs>,,upgrade,Hold,Buy,0,4,150,180.5,2025-03-01T12:00:00Z,primary,r-1`, string(lines[1]))
+}
+
+func TestXLSXWriter(t *testing.T) {
+	var buf bytes.Buffer
+	w := NewWriter(FormatXLSX, &buf, "stocks")
+	assert.Nil(t, w.WriteRow(StockHeader))
+	assert.Nil(t, w.WriteRow(StockRow(sampleStock())))
+	assert.Nil(t, w.Close())
+
+	archive, err := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	assert.Nil(t, err)
+	files := map[string]string{}
+	for _, f := range archive.File {
+		r, err := f.Open()
+		assert.Nil(t, err)
+		content, _ := io.ReadAll(r)
+		files[f.Name] = string(content)
+	}
+
+	assert.Contains(t, files, "[Content_Types].xml")
+	assert.Contains(t, files, "xl/_rels/workbook.xml.rels")
+	assert.Contains(t, files["xl/workbook.xml"], `<sheet name="stocks"`)
+	sheet := files["xl/worksheets/sheet1.xml"]
+	assert.Contains(t, sheet, `<c t="inlineStr"><is><t xml:space="preserve">ticker</t></is></c>`)
+	assert.Contains(t, sheet, `<c t="inlineStr"><is><t xml:space="preserve">Goldman &lt;Sachs&gt;</t></is></c>`)
+	assert.Contains(t, sheet, `<c><v>180.5</v></c>`)
+	assert.Equal(t, 2, bytes.Count([]byte(sheet), []byte("<row>")))
+}
+
+func TestSanitizeSheetName(t *testing.T) {
+	cases := map[string]string{
+		"AAPL-history":                       "AAPL-history",
+		"BRK/B-history":                      "BRK_B-history",
+		`a[b]c:d*e?f\g`:                      "a_b_c_d_e_f_g",
+		"'quoted'":                           "quoted",
+		"ABCDEFGHIJKLMNOPQRSTUVWXYZ-history": "ABCDEFGHIJKLMNOPQRSTUVWXYZ-hist",
+		"":                                   "Sheet1",
+		"'":                                  "Sheet1",
+		"ÁÉÍÓÚÁÉÍÓÚÁÉÍÓÚÁÉÍÓÚÁÉÍÓÚÁÉÍÓÚÁÉ": "ÁÉÍÓÚÁÉÍÓÚÁÉÍÓÚÁÉÍÓÚÁÉÍÓÚÁÉÍÓÚÁ",
+	}
+	for input, want := range cases {
+		assert.Equal(t, want, sanitizeSheetName(input), input)
+	}
+}
+
+func TestNewWriter_UnknownFormat(t *testing.T) {
+	assert.Nil(t, NewWriter("pdf", io.Discard, "stocks"))
+}
@@ -0,0 +1,128 @@
+package export
+
+import (
+	"archive/zip"
+	"bufio"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// XLSXWriter escribe un libro de una sola hoja. El zip se escribe de forma secuencial,
+// así que la hoja se emite fila por fila a medida que llegan los datos. Los números se
+// guardan como celdas numéricas y el resto como texto en línea.
+type XLSXWriter struct {
+	zip   *zip.Writer
+	sheet *bufio.Writer
+	name  string
+}
+
+func NewXLSXWriter(w io.Writer, sheetName string) *XLSXWriter {
+	return &XLSXWriter{zip: zip.NewWriter(w), name: sanitizeSheetName(sheetName)}
+}
+
+// maxSheetName es el largo máximo que Excel acepta para el nombre de una hoja.
+const maxSheetName = 31
+
+// sanitizeSheetName adapta el nombre a las reglas de Excel: sin []:*?/\, sin apóstrofo
+// al principio ni al final y con a lo sumo 31 caracteres. Excel rechaza el libro
+// entero si el nombre no las cumple.
+func sanitizeSheetName(name string) string {
+	name = strings.Map(func(r rune) rune {
+		if strings.ContainsRune(`[]:*?/\`, r) {
+			return '_'
+		}
+		return r
+	}, name)
+	if runes := []rune(name); len(runes) > maxSheetName {
+		name = string(runes[:maxSheetName])
+	}
+	name = strings.Trim(name, "' ")
+	if name == "" {
+		return "Sheet1"
+	}
+	return name
+}
+
+func (x *XLSXWriter) WriteRow(cells []any) error {
+	if x.sheet == nil {
+		if err := x.begin(); err != nil {
+			return err
+		}
+	}
+	x.sheet.WriteString("<row>")
+	for _, cell := range cells {
+		switch cell.(type) {
+		case int, float64:
+			fmt.Fprintf(x.sheet, "<c><v>%s</v></c>", formatCell(cell))
+		default:
+			x.sheet.WriteString(`<c t="inlineStr"><is><t xml:space="preserve">`)
+			xml.EscapeText(x.sheet, []byte(formatCell(cell)))
+			x.sheet.WriteString("</t></is></c>")
+		}
+	}
+	_, err := x.sheet.WriteString("</row>")
+	return err
+}
+
+func (x *XLSXWriter) Close() error {
+	if x.sheet == nil {
+		if err := x.begin(); err != nil {
+			return err
+		}
+	}
+	x.sheet.WriteString(`</sheetData></worksheet>`)
+	if err := x.sheet.Flush(); err != nil {
+		return err
+	}
+	return x.zip.Close()
+}
+
+// begin escribe las partes fijas del libro y abre la hoja.
+func (x *XLSXWriter) begin() error {
+	var name strings.Builder
+	xml.EscapeText(&name, []byte(x.name))
+	parts := []struct{ path, content string }{
+		{"[Content_Types].xml", xlsxContentTypes},
+		{"_rels/.rels", xlsxRootRels},
+		{"xl/workbook.xml", fmt.Sprintf(xlsxWorkbook, name.String())},
+		{"xl/_rels/workbook.xml.rels", xlsxWorkbookRels},
+	}
+	for _, part := range parts {
+		f, err := x.zip.Create(part.path)
+		if err != nil {
+			return err
+		}
+		if _, err := io.WriteString(f, part.content); err != nil {
+			return err
+		}
+	}
+
+	f, err := x.zip.Create("xl/worksheets/sheet1.xml")
+	if err != nil {
+		return err
+	}
+	x.sheet = bufio.NewWriter(f)
+	_, err = x.sheet.WriteString(xml.Header + `<worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main"><sheetData>`)
+	return err
+}
+
+const xlsxContentTypes = xml.Header + `<Types xmlns="http://schemas.openxmlformats.org/package/2006/content-types">` +
+	`<Default Extension="rels" ContentType="application/vnd.openxmlformats-package.relationships+xml"/>` +
+	`<Default Extension="xml" ContentType="application/xml"/>` +
+	`<Override PartName="/xl/workbook.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.sheet.main+xml"/>` +
+	`<Override PartName="/xl/worksheets/sheet1.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.worksheet+xml"/>` +
+	`</Types>`
+
+const xlsxRootRels = xml.Header + `<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">` +
+	`<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/officeDocument" Target="xl/workbook.xml"/>` +
+	`</Relationships>`
+
+const xlsxWorkbook = xml.Header + `<workbook xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main" ` +
+	`xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships">` +
+	`<sheets><sheet name="%s" sheetId="1" r:id="rId1"/></sheets></workbook>`
+
+const xlsxWorkbookRels = xml.Header + `<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">` +
+	`<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/worksheet" Target="worksheets/sheet1.xml"/>` +
+	`</Relationships>`
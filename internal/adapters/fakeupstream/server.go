@@ -0,0 +1,249 @@
+// Package fakeupstream imita la API externa de ratings para desarrollar sin
+// credenciales: sirve los eventos grabados en fixtures/ con el mismo formato
+// {items, next_page}, exige autenticación Bearer y puede inyectar fallas.
+package fakeupstream
+
+import (
+	"embed"
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"math/rand/v2"
+	"net/http"
+	"path"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"recommender/internal/core/domain"
+)
+
+//go:embed fixtures/*.json
+var embedded embed.FS
+
+// FaultsPath permite consultar (GET) y cambiar (PUT) las fallas con el servidor corriendo.
+const FaultsPath = "/_faults"
+
+// Faults son las fallas que se inyectan en cada petición. Las tasas van de 0 a 1 y
+// se aplican por petición (ErrorRate) o por evento (MalformedPriceRate, BadTimeRate).
+type Faults struct {
+	LatencyMS          int     `json:"latency_ms"`
+	ErrorRate          float64 `json:"error_rate"`
+	ErrorStatus        int     `json:"error_status"`
+	MalformedPriceRate float64 `json:"malformed_price_rate"`
+	BadTimeRate        float64 `json:"bad_time_rate"`
+}
+
+func (f Faults) validate() error {
+	for name, rate := range map[string]float64{
+		"error_rate":           f.ErrorRate,
+		"malformed_price_rate": f.MalformedPriceRate,
+		"bad_time_rate":        f.BadTimeRate,
+	} {
+		if rate < 0 || rate > 1 {
+			return fmt.Errorf("%s debe estar entre 0 y 1", name)
+		}
+	}
+	if f.LatencyMS < 0 {
+		return fmt.Errorf("latency_ms no puede ser negativa")
+	}
+	if f.ErrorStatus != 0 && (f.ErrorStatus < 500 || f.ErrorStatus > 599) {
+		return fmt.Errorf("error_status debe ser 5xx")
+	}
+	return nil
+}
+
+// Options configura el servidor. Fixtures vacío usa los archivos embebidos.
+type Options struct {
+	APIKey   string
+	PageSize int
+	Fixtures fs.FS
+	Faults   Faults
+	Seed     uint64
+}
+
+// Server responde como la API externa. next_page es el índice del primer evento de
+// la página siguiente; el cliente lo trata como un token opaco.
+type Server struct {
+	apiKey   string
+	pageSize int
+	items    []domain.StockDTO
+
+	mu     sync.Mutex
+	faults Faults
+	rng    *rand.Rand
+}
+
+func NewServer(opts Options) (*Server, error) {
+	if opts.APIKey == "" {
+		return nil, fmt.Errorf("fake-upstream: falta la API key")
+	}
+	if opts.PageSize <= 0 {
+		opts.PageSize = 10
+	}
+	if err := opts.Faults.validate(); err != nil {
+		return nil, fmt.Errorf("fake-upstream: %w", err)
+	}
+	fixtures := opts.Fixtures
+	if fixtures == nil {
+		sub, err := fs.Sub(embedded, "fixtures")
+		if err != nil {
+			return nil, err
+		}
+		fixtures = sub
+	}
+	items, err := LoadFixtures(fixtures)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Server{
+		apiKey:   opts.APIKey,
+		pageSize: opts.PageSize,
+		items:    items,
+		faults:   opts.Faults,
+		rng:      rand.New(rand.NewPCG(opts.Seed, opts.Seed)),
+	}, nil
+}
+
+// LoadFixtures lee todos los *.json de la raíz de fixtures en orden alfabético. Cada
+// archivo es un arreglo de eventos con el formato de la API (precios como texto).
+func LoadFixtures(fixtures fs.FS) ([]domain.StockDTO, error) {
+	names, err := fs.Glob(fixtures, "*.json")
+	if err != nil {
+		return nil, err
+	}
+	if len(names) == 0 {
+		return nil, fmt.Errorf("fake-upstream: no hay fixtures *.json")
+	}
+	sort.Strings(names)
+
+	var items []domain.StockDTO
+	for _, name := range names {
+		raw, err := fs.ReadFile(fixtures, name)
+		if err != nil {
+			return nil, err
+		}
+		var page []domain.StockDTO
+		if err := json.Unmarshal(raw, &page); err != nil {
+			return nil, fmt.Errorf("fake-upstream: %s: %w", path.Base(name), err)
+		}
+		items = append(items, page...)
+	}
+	return items, nil
+}
+
+// Len es la cantidad de eventos cargados.
+func (s *Server) Len() int {
+	return len(s.items)
+}
+
+func (s *Server) Faults() Faults {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.faults
+}
+
+func (s *Server) SetFaults(faults Faults) error {
+	if err := faults.validate(); err != nil {
+		return err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.faults = faults
+	return nil
+}
+
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Header.Get("Authorization") != "Bearer "+s.apiKey {
+		writeJSON(w, http.StatusUnauthorized, map[string]string{"error": "Unauthorized"})
+		return
+	}
+	if r.URL.Path == FaultsPath {
+		s.serveFaults(w, r)
+		return
+	}
+	if r.Method != http.MethodGet {
+		writeJSON(w, http.StatusMethodNotAllowed, map[string]string{"error": "Method not allowed"})
+		return
+	}
+
+	faults := s.Faults()
+	if faults.LatencyMS > 0 {
+		select {
+		case <-time.After(time.Duration(faults.LatencyMS) * time.Millisecond):
+		case <-r.Context().Done():
+			return
+		}
+	}
+	if s.roll(faults.ErrorRate) {
+		status := faults.ErrorStatus
+		if status == 0 {
+			status = http.StatusInternalServerError
+		}
+		writeJSON(w, status, map[string]string{"error": "Injected failure"})
+		return
+	}
+
+	offset := 0
+	if token := r.URL.Query().Get("next_page"); token != "" {
+		var err error
+		if offset, err = strconv.Atoi(token); err != nil || offset < 0 || offset > len(s.items) {
+			writeJSON(w, http.StatusBadRequest, map[string]string{"error": "Invalid next_page"})
+			return
+		}
+	}
+	end := min(offset+s.pageSize, len(s.items))
+
+	response := domain.APIResponseDTO{Items: make([]domain.StockDTO, 0, end-offset)}
+	for _, item := range s.items[offset:end] {
+		if s.roll(faults.MalformedPriceRate) {
+			item.TargetTo = "N/A"
+		}
+		if s.roll(faults.BadTimeRate) {
+			item.Time = "not-a-timestamp"
+		}
+		response.Items = append(response.Items, item)
+	}
+	if end < len(s.items) {
+		response.NextPage = strconv.Itoa(end)
+	}
+	writeJSON(w, http.StatusOK, response)
+}
+
+func (s *Server) serveFaults(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		writeJSON(w, http.StatusOK, s.Faults())
+	case http.MethodPut:
+		var faults Faults
+		if err := json.NewDecoder(r.Body).Decode(&faults); err != nil {
+			writeJSON(w, http.StatusBadRequest, map[string]string{"error": "Invalid request body"})
+			return
+		}
+		if err := s.SetFaults(faults); err != nil {
+			writeJSON(w, http.StatusBadRequest, map[string]string{"error": err.Error()})
+			return
+		}
+		writeJSON(w, http.StatusOK, faults)
+	default:
+		writeJSON(w, http.StatusMethodNotAllowed, map[string]string{"error": "Method not allowed"})
+	}
+}
+
+// roll decide una falla con probabilidad rate; el generador es determinista con la misma semilla.
+func (s *Server) roll(rate float64) bool {
+	if rate <= 0 {
+		return false
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.rng.Float64() < rate
+}
+
+func writeJSON(w http.ResponseWriter, status int, body any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(body)
+}
@@ -0,0 +1,107 @@
+package fakeupstream
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	"recommender/internal/adapters/clients"
+	"recommender/internal/core/domain"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestServer(t *testing.T, opts Options) (*Server, *httptest.Server) {
+	opts.APIKey = "dev-key"
+	fake, err := NewServer(opts)
+	require.NoError(t, err)
+	server := httptest.NewServer(fake)
+	t.Cleanup(server.Close)
+	return fake, server
+}
+
+// fetchAll recorre todas las páginas con el cliente real, como lo hace la importación.
+func fetchAll(t *testing.T, api *clients.ExternalStockAPI) []domain.Stock {
+	var stocks []domain.Stock
+	next := ""
+	for {
+		response, err := api.FetchStocks(next)
+		require.NoError(t, err)
+		stocks = append(stocks, response.Items...)
+		if response.NextPage == "" {
+			return stocks
+		}
+		next = response.NextPage
+	}
+}
+
+func TestServer_ServesEmbeddedFixturesToTheRealClient(t *testing.T) {
+	fake, server := newTestServer(t, Options{PageSize: 5})
+	api := clients.NewExternalStockAPIProvider("fake", server.URL, "dev-key")
+
+	stocks := fetchAll(t, api)
+	assert.Len(t, stocks, fake.Len())
+	assert.Equal(t, "AAPL", stocks[0].Ticker)
+	assert.Equal(t, 185.0, stocks[0].TargetTo)
+}
+
+func TestServer_RequiresBearerKey(t *testing.T) {
+	_, server := newTestServer(t, Options{})
+
+	_, err := clients.NewExternalStockAPIProvider("fake", server.URL, "wrong").FetchStocks("")
+	assert.ErrorContains(t, err, "401")
+
+	resp, err := http.Get(server.URL + FaultsPath)
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+}
+
+func TestServer_InjectsFaults(t *testing.T) {
+	fake, server := newTestServer(t, Options{Faults: Faults{ErrorRate: 1, ErrorStatus: 503}})
+	api := clients.NewExternalStockAPIProvider("fake", server.URL, "dev-key")
+
+	_, err := api.FetchStocks("")
+	assert.ErrorContains(t, err, "503")
+
+	// Las fallas se cambian en caliente por HTTP
+	req, _ := http.NewRequest(http.MethodPut, server.URL+FaultsPath, strings.NewReader(`{"malformed_price_rate": 1}`))
+	req.Header.Set("Authorization", "Bearer dev-key")
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, Faults{MalformedPriceRate: 1}, fake.Faults())
+
+	_, err = api.FetchStocks("")
+	assert.ErrorContains(t, err, "TargetTo")
+
+	require.NoError(t, fake.SetFaults(Faults{BadTimeRate: 1}))
+	_, err = api.FetchStocks("")
+	assert.ErrorContains(t, err, "Time")
+
+	assert.Error(t, fake.SetFaults(Faults{ErrorRate: 2}))
+	assert.Error(t, fake.SetFaults(Faults{ErrorStatus: 404}))
+}
+
+func TestServer_FixturesFromDirectory(t *testing.T) {
+	fixtures := fstest.MapFS{
+		"b.json": {Data: []byte(`[{"ticker":"MSFT","target_from":"$1","target_to":"$2","time":"2024-06-02T00:00:00Z"}]`)},
+		"a.json": {Data: []byte(`[{"ticker":"AAPL","target_from":"$1","target_to":"$2","time":"2024-06-01T00:00:00Z"}]`)},
+		"notes":  {Data: []byte("ignorado")},
+	}
+	_, server := newTestServer(t, Options{Fixtures: fixtures, PageSize: 1})
+
+	stocks := fetchAll(t, clients.NewExternalStockAPIProvider("fake", server.URL, "dev-key"))
+	require.Len(t, stocks, 2)
+	assert.Equal(t, "AAPL", stocks[0].Ticker)
+	assert.Equal(t, "MSFT", stocks[1].Ticker)
+
+	_, err := NewServer(Options{APIKey: "k", Fixtures: fstest.MapFS{}})
+	assert.Error(t, err)
+	_, err = NewServer(Options{APIKey: "k", Fixtures: fstest.MapFS{"x.json": {Data: []byte("{")}}})
+	assert.ErrorContains(t, err, "x.json")
+}
@@ -0,0 +1,136 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"recommender/internal/core/domain"
+	"recommender/internal/core/services"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+type AlertHandler struct {
+	service *services.AlertService
+}
+
+func NewAlertHandler(service *services.AlertService) *AlertHandler {
+	return &AlertHandler{service: service}
+}
+
+type alertRuleRequest struct {
+	Name               string              `json:"name"`
+	WatchlistID        *uint               `json:"watchlist_id"`
+	Tickers            []string            `json:"tickers"`
+	ActionTypes        []domain.ActionType `json:"action_types"`
+	Brokerage          string              `json:"brokerage"`
+	MinTargetChangePct float64             `json:"min_target_change_pct"`
+	Notifier           string              `json:"notifier"`
+	Target             string              `json:"target"`
+	Active             *bool               `json:"active"`
+}
+
+func (h *AlertHandler) GetRules(c *gin.Context) {
+	rules, err := h.service.ListRules(currentUser(c))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve alert rules"})
+		return
+	}
+	c.JSON(http.StatusOK, rules)
+}
+
+func (h *AlertHandler) GetRule(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid alert rule id"})
+		return
+	}
+	rule, err := h.service.GetRule(currentUser(c), uint(id))
+	if err != nil {
+		writeAlertError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, rule)
+}
+
+// PostRule crea una regla; si no se indica `active` la regla nace activa.
+func (h *AlertHandler) PostRule(c *gin.Context) {
+	var req alertRuleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request"})
+		return
+	}
+	if req.Notifier == "" {
+		req.Notifier = domain.NotifierLog
+	}
+
+	rule := &domain.AlertRule{
+		UserID:             currentUser(c),
+		Name:               req.Name,
+		WatchlistID:        req.WatchlistID,
+		Tickers:            req.Tickers,
+		ActionTypes:        req.ActionTypes,
+		Brokerage:          req.Brokerage,
+		MinTargetChangePct: req.MinTargetChangePct,
+		Notifier:           req.Notifier,
+		Target:             req.Target,
+		Active:             req.Active == nil || *req.Active,
+	}
+	if err := h.service.CreateRule(rule); err != nil {
+		if errors.Is(err, services.ErrInvalidAlertRule) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error(), "notifiers": h.service.Notifiers()})
+			return
+		}
+		writeAlertError(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, rule)
+}
+
+func (h *AlertHandler) DeleteRule(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid alert rule id"})
+		return
+	}
+	if err := h.service.DeleteRule(currentUser(c), uint(id)); err != nil {
+		writeAlertError(c, err)
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// GetEvents lista las alertas disparadas para el usuario, de la más reciente a la más antigua.
+func (h *AlertHandler) GetEvents(c *gin.Context) {
+	limit := 50 // Valor por defecto
+	offset := 0 // Valor por defecto
+
+	if l, exists := c.GetQuery("limit"); exists {
+		parsedLimit, err := strconv.Atoi(l)
+		if err == nil && parsedLimit > 0 {
+			limit = parsedLimit
+		}
+	}
+
+	if o, exists := c.GetQuery("offset"); exists {
+		parsedOffset, err := strconv.Atoi(o)
+		if err == nil && parsedOffset >= 0 {
+			offset = parsedOffset
+		}
+	}
+
+	events, err := h.service.ListEvents(currentUser(c), limit, offset)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve alerts"})
+		return
+	}
+	c.JSON(http.StatusOK, events)
+}
+
+func writeAlertError(c *gin.Context, err error) {
+	if errors.Is(err, services.ErrAlertRuleNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Alert rule not found"})
+		return
+	}
+	c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to process alert rule"})
+}
@@ -0,0 +1,122 @@
+package handlers
+
+import (
+	"net/http"
+	"recommender/internal/adapters/notifiers"
+	"recommender/internal/core/domain"
+	"recommender/internal/core/services"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+)
+
+type fakeAlertRepository struct {
+	rules  []domain.AlertRule
+	events []domain.AlertEvent
+}
+
+func (f *fakeAlertRepository) CreateRule(rule *domain.AlertRule) error {
+	rule.ID = uint(len(f.rules) + 1)
+	f.rules = append(f.rules, *rule)
+	return nil
+}
+
+func (f *fakeAlertRepository) DeleteRule(userID string, id uint) error {
+	if _, err := f.GetRule(userID, id); err != nil {
+		return err
+	}
+	f.rules[id-1].UserID = ""
+	return nil
+}
+
+func (f *fakeAlertRepository) GetRule(userID string, id uint) (*domain.AlertRule, error) {
+	if id == 0 || int(id) > len(f.rules) || f.rules[id-1].UserID != userID {
+		return nil, gorm.ErrRecordNotFound
+	}
+	return &f.rules[id-1], nil
+}
+
+func (f *fakeAlertRepository) ListRules(userID string) ([]domain.AlertRule, error) {
+	return f.rules, nil
+}
+
+func (f *fakeAlertRepository) ListActiveRules() ([]domain.AlertRule, error) {
+	return f.rules, nil
+}
+
+func (f *fakeAlertRepository) SaveEvent(event *domain.AlertEvent) error {
+	f.events = append(f.events, *event)
+	return nil
+}
+
+func (f *fakeAlertRepository) ListEvents(userID string, limit, offset int) ([]domain.AlertEvent, error) {
+	return f.events, nil
+}
+
+type fakeOutboxCursorStore struct {
+	cursors map[string]uint
+}
+
+func (f *fakeOutboxCursorStore) Cursor(consumer string) (uint, bool, error) {
+	id, ok := f.cursors[consumer]
+	return id, ok, nil
+}
+
+func (f *fakeOutboxCursorStore) InitCursor(consumer string, id uint) error {
+	if _, ok := f.cursors[consumer]; !ok {
+		f.cursors[consumer] = id
+	}
+	return nil
+}
+
+func (f *fakeOutboxCursorStore) AdvanceCursor(consumer string, from, to uint) (bool, error) {
+	if f.cursors[consumer] != from {
+		return false, nil
+	}
+	f.cursors[consumer] = to
+	return true, nil
+}
+
+func TestAlertEndpoints(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	outbox := &fakeOutboxReader{}
+	service := services.NewAlertService(&fakeAlertRepository{}, &fakeWatchlistRepository{}).
+		RegisterNotifier(notifiers.NewLogNotifier()).
+		WithOutbox(outbox, &fakeOutboxCursorStore{cursors: map[string]uint{}})
+	service.Poll() // Fija el cursor al final del outbox
+	handler := NewAlertHandler(service)
+
+	router := gin.New()
+	alerts := router.Group("/alerts", RequireUser())
+	alerts.GET("/rules", handler.GetRules)
+	alerts.POST("/rules", handler.PostRule)
+	alerts.GET("/rules/:id", handler.GetRule)
+	alerts.DELETE("/rules/:id", handler.DeleteRule)
+	alerts.GET("/events", handler.GetEvents)
+
+	resp := watchlistRequestAs(router, "alice", "POST", "/alerts/rules", `{"name":"downgrades","action_types":["downgrade"]}`)
+	assert.Equal(t, http.StatusCreated, resp.Code)
+	assert.Contains(t, resp.Body.String(), `"active":true`)
+
+	resp = watchlistRequestAs(router, "alice", "POST", "/alerts/rules", `{"name":"hook","tickers":["AAPL"],"notifier":"webhook"}`)
+	assert.Equal(t, http.StatusBadRequest, resp.Code)
+	assert.Contains(t, resp.Body.String(), "unknown notifier")
+
+	resp = watchlistRequestAs(router, "bob", "GET", "/alerts/rules/1", "")
+	assert.Equal(t, http.StatusNotFound, resp.Code)
+
+	event, _ := domain.NewStockCreatedEvent(domain.Stock{Ticker: "AAPL", Action: "downgraded by"})
+	event.ID = 1
+	outbox.events = append(outbox.events, event)
+	_, err := service.Poll()
+	assert.Nil(t, err)
+	resp = watchlistRequestAs(router, "alice", "GET", "/alerts/events", "")
+	assert.Equal(t, http.StatusOK, resp.Code)
+	assert.Contains(t, resp.Body.String(), "AAPL")
+
+	resp = watchlistRequestAs(router, "alice", "DELETE", "/alerts/rules/1", "")
+	assert.Equal(t, http.StatusNoContent, resp.Code)
+}
@@ -0,0 +1,41 @@
+package handlers
+
+import (
+	"net/http"
+	"recommender/internal/core/services"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+type BrokerageHandler struct {
+	accuracy *services.BrokerageAccuracyService
+}
+
+func NewBrokerageHandler(accuracy *services.BrokerageAccuracyService) *BrokerageHandler {
+	return &BrokerageHandler{accuracy: accuracy}
+}
+
+// GetAccuracy mide la precisión de cada corredora sobre los eventos de los últimos `since_days`.
+func (h *BrokerageHandler) GetAccuracy(c *gin.Context) {
+	sinceDays := 365 // Valor por defecto
+
+	if d, exists := c.GetQuery("since_days"); exists {
+		parsedDays, err := strconv.Atoi(d)
+		if err != nil || parsedDays <= 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "since_days must be a positive integer"})
+			return
+		}
+		sinceDays = parsedDays
+	}
+
+	now := time.Now()
+	accuracies, err := h.accuracy.Measure(now.AddDate(0, 0, -sinceDays), now)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to measure brokerage accuracy"})
+		return
+	}
+
+	c.JSON(http.StatusOK, accuracies)
+}
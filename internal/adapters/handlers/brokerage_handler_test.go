@@ -0,0 +1,36 @@
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"recommender/internal/adapters/prices"
+	"recommender/internal/core/domain"
+	"recommender/internal/core/services"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetAccuracy(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	accuracy := services.NewBrokerageAccuracyService(&fakeStockRepositoryWithRecommendations{}, prices.NewMemoryPriceFeed([]domain.PriceBar{}), 30, 5)
+	handler := NewBrokerageHandler(accuracy)
+
+	router := gin.New()
+	router.GET("/brokerages/accuracy", handler.GetAccuracy)
+
+	req, _ := http.NewRequest("GET", "/brokerages/accuracy?since_days=90", nil)
+	resp := httptest.NewRecorder()
+	router.ServeHTTP(resp, req)
+
+	assert.Equal(t, http.StatusOK, resp.Code)
+	assert.Equal(t, "[]", resp.Body.String())
+
+	req, _ = http.NewRequest("GET", "/brokerages/accuracy?since_days=-1", nil)
+	resp = httptest.NewRecorder()
+	router.ServeHTTP(resp, req)
+
+	assert.Equal(t, http.StatusBadRequest, resp.Code)
+}
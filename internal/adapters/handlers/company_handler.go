@@ -0,0 +1,55 @@
+package handlers
+
+import (
+	"net/http"
+	"recommender/internal/core/services"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+type CompanyHandler struct {
+	service *services.CompanyService
+}
+
+func NewCompanyHandler(service *services.CompanyService) *CompanyHandler {
+	return &CompanyHandler{service: service}
+}
+
+// GetCompanies lista compañías; `q` filtra por prefijo del nombre.
+func (h *CompanyHandler) GetCompanies(c *gin.Context) {
+	limit := 20 // Valor por defecto
+	offset := 0 // Valor por defecto
+
+	if l, exists := c.GetQuery("limit"); exists {
+		parsedLimit, err := strconv.Atoi(l)
+		if err == nil && parsedLimit > 0 {
+			limit = parsedLimit
+		}
+	}
+
+	if o, exists := c.GetQuery("offset"); exists {
+		parsedOffset, err := strconv.Atoi(o)
+		if err == nil && parsedOffset >= 0 {
+			offset = parsedOffset
+		}
+	}
+
+	companies, err := h.service.SearchCompanies(c.Query("q"), limit, offset)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve companies"})
+		return
+	}
+
+	c.JSON(http.StatusOK, companies)
+}
+
+func (h *CompanyHandler) GetCompanyBySymbol(c *gin.Context) {
+	company, err := h.service.GetCompany(c.Param("symbol"))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Company not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, company)
+}
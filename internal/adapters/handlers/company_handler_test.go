@@ -0,0 +1,65 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"recommender/internal/core/domain"
+	"recommender/internal/core/services"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+type fakeCompanyRepository struct{}
+
+func (f *fakeCompanyRepository) Save(company *domain.Company) error {
+	return nil
+}
+
+func (f *fakeCompanyRepository) GetBySymbol(symbol string) (*domain.Company, error) {
+	if symbol == "AAPL" {
+		return &domain.Company{Symbol: "AAPL", Name: "Apple Inc.", Sector: "Technology", Active: true}, nil
+	}
+	return nil, errors.New("company not found")
+}
+
+func (f *fakeCompanyRepository) SearchByNamePrefix(prefix string, limit, offset int) ([]domain.Company, error) {
+	if prefix == "App" {
+		return []domain.Company{{Symbol: "AAPL", Name: "Apple Inc.", Active: true}}, nil
+	}
+	return []domain.Company{}, nil
+}
+
+func setupCompanyRouter() *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	handler := NewCompanyHandler(services.NewCompanyService(&fakeCompanyRepository{}))
+
+	router := gin.New()
+	router.GET("/companies", handler.GetCompanies)
+	router.GET("/companies/:symbol", handler.GetCompanyBySymbol)
+	return router
+}
+
+func TestGetCompanies_SearchByPrefix(t *testing.T) {
+	router := setupCompanyRouter()
+
+	req, _ := http.NewRequest("GET", "/companies?q=App", nil)
+	resp := httptest.NewRecorder()
+	router.ServeHTTP(resp, req)
+
+	assert.Equal(t, http.StatusOK, resp.Code)
+	assert.Contains(t, resp.Body.String(), "Apple Inc.")
+}
+
+func TestGetCompanyBySymbol_NotFound(t *testing.T) {
+	router := setupCompanyRouter()
+
+	req, _ := http.NewRequest("GET", "/companies/NOPE", nil)
+	resp := httptest.NewRecorder()
+	router.ServeHTTP(resp, req)
+
+	assert.Equal(t, http.StatusNotFound, resp.Code)
+	assert.Contains(t, resp.Body.String(), "Company not found")
+}
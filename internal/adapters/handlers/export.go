@@ -0,0 +1,79 @@
+package handlers
+
+import (
+	"log"
+	"mime"
+	"net/http"
+	"recommender/internal/adapters/export"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+const formatJSON = "json"
+
+// exportFormat elige el formato con `?format=` (json, csv o xlsx) o, si no se indica,
+// con el encabezado Accept. Si el formato es inválido responde 400 y devuelve ok en false.
+func exportFormat(c *gin.Context) (format string, ok bool) {
+	if f, exists := c.GetQuery("format"); exists {
+		switch f {
+		case formatJSON, export.FormatCSV, export.FormatXLSX:
+			return f, true
+		}
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid format", "allowed": []string{formatJSON, export.FormatCSV, export.FormatXLSX}})
+		return "", false
+	}
+	switch c.NegotiateFormat(gin.MIMEJSON, export.ContentTypeCSV, export.ContentTypeXLSX) {
+	case export.ContentTypeCSV:
+		return export.FormatCSV, true
+	case export.ContentTypeXLSX:
+		return export.FormatXLSX, true
+	default:
+		return formatJSON, true
+	}
+}
+
+// writeExport envía la tabla como archivo adjunto. rows escribe las filas a medida que
+// las lee, de modo que la respuesta sale por partes sin armarse en memoria. Si falla a
+// mitad de camino el estado ya se envió; se registra el error y se corta la respuesta.
+func writeExport(c *gin.Context, format, name string, header []any, rows func(write func([]any) error) error) {
+	c.Header("Content-Type", export.ContentType(format))
+	// name puede venir de la URL (el ticker del historial): FormatMediaType lo escapa
+	disposition := mime.FormatMediaType("attachment", map[string]string{"filename": name + "." + format})
+	if disposition == "" {
+		disposition = "attachment"
+	}
+	c.Header("Content-Disposition", disposition)
+	c.Status(http.StatusOK)
+
+	w := export.NewWriter(format, c.Writer, name)
+	err := w.WriteRow(header)
+	if err == nil {
+		err = rows(w.WriteRow)
+	}
+	if err == nil {
+		err = w.Close()
+	}
+	if err != nil {
+		log.Printf("⚠ Exportación de %s interrumpida: %v", name, err)
+		c.Abort()
+	}
+}
+
+// parsePage lee `limit` y `offset`; los valores inválidos se ignoran.
+func parsePage(c *gin.Context, defaultLimit int) (limit, offset int) {
+	limit = defaultLimit
+	if l, exists := c.GetQuery("limit"); exists {
+		parsedLimit, err := strconv.Atoi(l)
+		if err == nil && parsedLimit > 0 {
+			limit = parsedLimit
+		}
+	}
+	if o, exists := c.GetQuery("offset"); exists {
+		parsedOffset, err := strconv.Atoi(o)
+		if err == nil && parsedOffset >= 0 {
+			offset = parsedOffset
+		}
+	}
+	return limit, offset
+}
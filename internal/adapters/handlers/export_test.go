@@ -0,0 +1,171 @@
+package handlers
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/csv"
+	"mime"
+	"net/http"
+	"net/http/httptest"
+	"recommender/internal/adapters/export"
+	"recommender/internal/core/domain"
+	"recommender/internal/core/services"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+// fakeStreamingStockRepository implementa además ports.StockStreamer.
+type fakeStreamingStockRepository struct {
+	fakeStockRepository
+	stocks []domain.Stock
+}
+
+func (f *fakeStreamingStockRepository) StreamFiltered(filter domain.StockFilter, limit, offset int, fn func(domain.Stock) error) error {
+	return f.each(func(s domain.Stock) bool { return true }, limit, offset, fn)
+}
+
+func (f *fakeStreamingStockRepository) StreamHistory(ticker string, limit, offset int, fn func(domain.Stock) error) error {
+	return f.each(func(s domain.Stock) bool { return s.Ticker == ticker }, limit, offset, fn)
+}
+
+func (f *fakeStreamingStockRepository) each(keep func(domain.Stock) bool, limit, offset int, fn func(domain.Stock) error) error {
+	sent := 0
+	for _, s := range f.stocks {
+		if !keep(s) {
+			continue
+		}
+		if offset > 0 {
+			offset--
+			continue
+		}
+		if limit > 0 && sent == limit {
+			break
+		}
+		if err := fn(s); err != nil {
+			return err
+		}
+		sent++
+	}
+	return nil
+}
+
+func exportRouter() *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	now := time.Now()
+	repo := &fakeStreamingStockRepository{}
+	for i := 0; i < 25; i++ {
+		repo.stocks = append(repo.stocks, domain.Stock{ID: uint(i + 1), Ticker: "AAPL", Brokerage: "JP Morgan", TargetFrom: 100, TargetTo: 110, Time: now.Add(time.Duration(i) * time.Minute)})
+	}
+	repo.stocks = append(repo.stocks, domain.Stock{ID: 26, Ticker: "MSFT", Time: now})
+
+	handler := NewStockHandler(services.NewStockService(repo, &fakeStockAPIClient{}))
+	router := gin.New()
+	router.GET("/stocks", handler.GetStocks)
+	router.GET("/stocks/:ticker/history", handler.GetStockHistory)
+	return router
+}
+
+func TestGetStocks_CSVExportStreamsAllRows(t *testing.T) {
+	router := exportRouter()
+
+	req, _ := http.NewRequest("GET", "/stocks", nil)
+	req.Header.Set("Accept", "text/csv")
+	resp := httptest.NewRecorder()
+	router.ServeHTTP(resp, req)
+
+	assert.Equal(t, http.StatusOK, resp.Code)
+	assert.Equal(t, export.ContentTypeCSV, resp.Header().Get("Content-Type"))
+	assert.Equal(t, `attachment; filename=stocks.csv`, resp.Header().Get("Content-Disposition"))
+	records, err := csv.NewReader(resp.Body).ReadAll()
+	assert.Nil(t, err)
+	assert.Len(t, records, 27) // Encabezado y todos los eventos, sin el límite por defecto de 10
+	assert.Equal(t, "ticker", records[0][1])
+
+	req, _ = http.NewRequest("GET", "/stocks?format=csv&limit=5&offset=20", nil)
+	resp = httptest.NewRecorder()
+	router.ServeHTTP(resp, req)
+	records, _ = csv.NewReader(resp.Body).ReadAll()
+	assert.Len(t, records, 6)
+	assert.Equal(t, "21", records[1][0])
+}
+
+func TestGetStocks_XLSXExport(t *testing.T) {
+	router := exportRouter()
+
+	req, _ := http.NewRequest("GET", "/stocks?format=xlsx", nil)
+	resp := httptest.NewRecorder()
+	router.ServeHTTP(resp, req)
+
+	assert.Equal(t, http.StatusOK, resp.Code)
+	assert.Equal(t, export.ContentTypeXLSX, resp.Header().Get("Content-Type"))
+	_, err := zip.NewReader(bytes.NewReader(resp.Body.Bytes()), int64(resp.Body.Len()))
+	assert.Nil(t, err)
+}
+
+func TestGetStocks_InvalidFormat(t *testing.T) {
+	router := exportRouter()
+
+	req, _ := http.NewRequest("GET", "/stocks?format=pdf", nil)
+	resp := httptest.NewRecorder()
+	router.ServeHTTP(resp, req)
+
+	assert.Equal(t, http.StatusBadRequest, resp.Code)
+	assert.Contains(t, resp.Body.String(), "Invalid format")
+}
+
+func TestGetStockHistory(t *testing.T) {
+	router := exportRouter()
+
+	req, _ := http.NewRequest("GET", "/stocks/MSFT/history", nil)
+	resp := httptest.NewRecorder()
+	router.ServeHTTP(resp, req)
+	assert.Equal(t, http.StatusOK, resp.Code)
+	assert.Contains(t, resp.Body.String(), `"id":26`)
+
+	req, _ = http.NewRequest("GET", "/stocks/AAPL/history?format=csv", nil)
+	resp = httptest.NewRecorder()
+	router.ServeHTTP(resp, req)
+	assert.Equal(t, `attachment; filename=AAPL-history.csv`, resp.Header().Get("Content-Disposition"))
+	records, _ := csv.NewReader(resp.Body).ReadAll()
+	assert.Len(t, records, 26)
+
+	// El ticker viene de la URL: el nombre del archivo se escapa en el encabezado
+	req, _ = http.NewRequest("GET", `/stocks/A%22B;%20x=y/history?format=csv`, nil)
+	resp = httptest.NewRecorder()
+	router.ServeHTTP(resp, req)
+	disposition, params, err := mime.ParseMediaType(resp.Header().Get("Content-Disposition"))
+	assert.Nil(t, err)
+	assert.Equal(t, "attachment", disposition)
+	assert.Equal(t, map[string]string{"filename": `A"B; x=y-history.csv`}, params)
+
+	req, _ = http.NewRequest("GET", "/stocks/NOPE/history", nil)
+	resp = httptest.NewRecorder()
+	router.ServeHTTP(resp, req)
+	assert.Equal(t, http.StatusNotFound, resp.Code)
+}
+
+func TestGetRecommendations_CSVExport(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	service := services.NewStockService(&fakeStockRepositoryWithRecommendations{}, &fakeStockAPIClient{})
+	router := gin.New()
+	router.GET("/stocks/recommendations", NewStockHandler(service).GetRecommendations)
+
+	req, _ := http.NewRequest("GET", "/stocks/recommendations?format=csv", nil)
+	resp := httptest.NewRecorder()
+	router.ServeHTTP(resp, req)
+
+	assert.Equal(t, http.StatusOK, resp.Code)
+	records, err := csv.NewReader(resp.Body).ReadAll()
+	assert.Nil(t, err)
+	assert.Equal(t, "rank", records[0][0])
+	assert.Equal(t, []string{"1", "TSLA"}, records[1][:2])
+
+	// Los errores de parámetros siguen respondiendo JSON
+	req, _ = http.NewRequest("GET", "/stocks/recommendations?format=csv&limit=0", nil)
+	resp = httptest.NewRecorder()
+	router.ServeHTTP(resp, req)
+	assert.Equal(t, http.StatusBadRequest, resp.Code)
+}
@@ -0,0 +1,31 @@
+package handlers
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// UserIDHeader es el encabezado con la identidad del usuario que completa el
+// mecanismo de autenticación ubicado delante de la API (gateway o proxy).
+const UserIDHeader = "X-User-ID"
+
+const userIDKey = "user_id"
+
+// RequireUser rechaza con 401 las peticiones sin identidad y la guarda en el contexto.
+func RequireUser() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID := strings.TrimSpace(c.GetHeader(UserIDHeader))
+		if userID == "" {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "Missing user identity"})
+			return
+		}
+		c.Set(userIDKey, userID)
+		c.Next()
+	}
+}
+
+func currentUser(c *gin.Context) string {
+	return c.GetString(userIDKey)
+}
@@ -0,0 +1,143 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"recommender/internal/core/domain"
+	"recommender/internal/core/services"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gorilla/websocket"
+)
+
+const feedWriteTimeout = 10 * time.Second
+
+type RecommendationFeedHandler struct {
+	feed     *services.RecommendationFeed
+	service  *services.StockService
+	ping     time.Duration
+	upgrader websocket.Upgrader
+}
+
+func NewRecommendationFeedHandler(feed *services.RecommendationFeed, service *services.StockService, ping time.Duration) *RecommendationFeedHandler {
+	return &RecommendationFeedHandler{
+		feed:    feed,
+		service: service,
+		ping:    ping,
+		upgrader: websocket.Upgrader{
+			// La API acepta cualquier origen (ver CORS en routes)
+			CheckOrigin: func(r *http.Request) bool { return true },
+		},
+	}
+}
+
+// GetRecommendationFeed abre un WebSocket con la lista de recomendaciones en vivo. Los
+// parámetros iniciales son los mismos de GET /stocks/recommendations; el cliente puede
+// cambiarlos enviando {"type":"subscribe","params":{...}}. El servidor envía primero la
+// lista completa y luego, tras cada importación, solo los cambios de ranking.
+func (h *RecommendationFeedHandler) GetRecommendationFeed(c *gin.Context) {
+	params, err := parseRecommendationParams(c, h.service.RecommendationDefaults())
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	// Los parámetros se validan antes del upgrade para responder con un 400 normal
+	client, err := h.feed.Subscribe(params)
+	if err != nil {
+		writeRecommendations(c, h.service, nil, err)
+		return
+	}
+	defer h.feed.Unsubscribe(client)
+
+	conn, err := h.upgrader.Upgrade(c.Writer, c.Request, nil)
+	if err != nil {
+		return // El upgrader ya respondió con el error
+	}
+	defer conn.Close()
+
+	errs := make(chan string, 1)
+	done := make(chan struct{})
+	go h.readRequests(conn, client, errs, done)
+
+	ping := time.NewTicker(h.ping)
+	defer ping.Stop()
+	for {
+		var update domain.RecommendationUpdate
+		select {
+		case <-done:
+			return
+		case message := <-errs:
+			update = domain.RecommendationUpdate{Type: domain.FeedError, Error: message}
+		case next, open := <-client.C:
+			if !open {
+				return
+			}
+			update = next
+		case <-ping.C:
+			if err := conn.WriteControl(websocket.PingMessage, nil, time.Now().Add(feedWriteTimeout)); err != nil {
+				return
+			}
+			continue
+		}
+		// Un cliente que no lee a tiempo se desconecta aquí; mientras tanto el feed
+		// reemplaza sus diffs pendientes por la lista completa
+		conn.SetWriteDeadline(time.Now().Add(feedWriteTimeout))
+		if err := conn.WriteJSON(update); err != nil {
+			return
+		}
+	}
+}
+
+// readRequests aplica los cambios de parámetros del cliente hasta que la conexión se
+// cierre o deje de responder a los pings.
+func (h *RecommendationFeedHandler) readRequests(conn *websocket.Conn, client *services.FeedClient, errs chan<- string, done chan<- struct{}) {
+	defer close(done)
+
+	pongWait := 2 * h.ping
+	conn.SetReadDeadline(time.Now().Add(pongWait))
+	conn.SetPongHandler(func(string) error {
+		return conn.SetReadDeadline(time.Now().Add(pongWait))
+	})
+
+	for {
+		_, message, err := conn.ReadMessage()
+		if err != nil {
+			return
+		}
+		request := domain.FeedRequest{Params: h.service.RecommendationDefaults()}
+		if err := json.Unmarshal(message, &request); err != nil {
+			reportFeedError(errs, "Invalid message")
+			continue
+		}
+		if request.Type != "subscribe" {
+			reportFeedError(errs, "Unknown message type")
+			continue
+		}
+		if err := h.feed.Update(client, request.Params); err != nil {
+			reportFeedError(errs, feedErrorMessage(err))
+		}
+	}
+}
+
+// reportFeedError no bloquea la lectura: si ya hay un error pendiente descarta el nuevo.
+func reportFeedError(errs chan<- string, message string) {
+	select {
+	case errs <- message:
+	default:
+	}
+}
+
+// feedErrorMessage traduce el error del servicio como lo hace writeRecommendations.
+func feedErrorMessage(err error) string {
+	switch {
+	case errors.Is(err, services.ErrUnknownStrategy):
+		return "Unknown strategy"
+	case errors.Is(err, services.ErrInvalidRecommendationParams):
+		return err.Error()
+	default:
+		return "Failed to fetch recommendations"
+	}
+}
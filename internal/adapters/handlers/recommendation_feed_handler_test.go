@@ -0,0 +1,59 @@
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"recommender/internal/core/domain"
+	"recommender/internal/core/services"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gorilla/websocket"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetRecommendationFeed(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	service := services.NewStockService(&fakeStockRepositoryWithRecommendations{}, &fakeStockAPIClient{})
+	feed := services.NewRecommendationFeed(service, &fakeOutboxReader{}, time.Hour)
+	router := gin.New()
+	router.GET("/stocks/recommendations/ws", NewRecommendationFeedHandler(feed, service, time.Minute).GetRecommendationFeed)
+	server := httptest.NewServer(router)
+	defer server.Close()
+	url := "ws" + strings.TrimPrefix(server.URL, "http") + "/stocks/recommendations/ws"
+
+	// Parámetros inválidos: 400 sin upgrade
+	_, resp, err := websocket.DefaultDialer.Dial(url+"?limit=0", nil)
+	assert.Error(t, err)
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+
+	conn, _, err := websocket.DefaultDialer.Dial(url+"?limit=1", nil)
+	assert.Nil(t, err)
+	defer conn.Close()
+	conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+
+	var update domain.RecommendationUpdate
+	assert.Nil(t, conn.ReadJSON(&update))
+	assert.Equal(t, domain.FeedSnapshot, update.Type)
+	assert.Len(t, update.List.Items, 1)
+
+	// Cambio de parámetros: nueva lista completa
+	assert.Nil(t, conn.WriteMessage(websocket.TextMessage, []byte(`{"type":"subscribe","params":{"limit":2}}`)))
+	assert.Nil(t, conn.ReadJSON(&update))
+	assert.Equal(t, domain.FeedSnapshot, update.Type)
+	assert.Len(t, update.List.Items, 2)
+	assert.Equal(t, 30, update.List.Params.LookbackDays) // Los campos omitidos usan los valores por defecto
+
+	// Estrategia desconocida: error y se conserva la suscripción
+	assert.Nil(t, conn.WriteMessage(websocket.TextMessage, []byte(`{"type":"subscribe","params":{"strategy":"nope"}}`)))
+	assert.Nil(t, conn.ReadJSON(&update))
+	assert.Equal(t, domain.FeedError, update.Type)
+	assert.Equal(t, "Unknown strategy", update.Error)
+
+	assert.Nil(t, conn.WriteMessage(websocket.TextMessage, []byte("not json")))
+	assert.Nil(t, conn.ReadJSON(&update))
+	assert.Equal(t, "Invalid message", update.Error)
+}
@@ -0,0 +1,71 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"recommender/internal/core/services"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+type SnapshotHandler struct {
+	service *services.SnapshotService
+}
+
+func NewSnapshotHandler(service *services.SnapshotService) *SnapshotHandler {
+	return &SnapshotHandler{service: service}
+}
+
+// GetSnapshots lista los snapshots más recientes; `strategy` filtra por estrategia.
+func (h *SnapshotHandler) GetSnapshots(c *gin.Context) {
+	limit := 20 // Valor por defecto
+	offset := 0 // Valor por defecto
+
+	if l, exists := c.GetQuery("limit"); exists {
+		parsedLimit, err := strconv.Atoi(l)
+		if err == nil && parsedLimit > 0 {
+			limit = parsedLimit
+		}
+	}
+
+	if o, exists := c.GetQuery("offset"); exists {
+		parsedOffset, err := strconv.Atoi(o)
+		if err == nil && parsedOffset >= 0 {
+			offset = parsedOffset
+		}
+	}
+
+	snapshots, err := h.service.List(c.Query("strategy"), limit, offset)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve snapshots"})
+		return
+	}
+
+	c.JSON(http.StatusOK, snapshots)
+}
+
+// GetDiff compara dos snapshots; `from` y `to` aceptan un ID o una fecha.
+func (h *SnapshotHandler) GetDiff(c *gin.Context) {
+	from, exists := c.GetQuery("from")
+	if !exists || from == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "from is required"})
+		return
+	}
+
+	diff, err := h.service.Diff(from, c.Query("to"), c.Query("strategy"))
+	if errors.Is(err, services.ErrInvalidSnapshotRef) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if errors.Is(err, services.ErrSnapshotNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to compare snapshots"})
+		return
+	}
+
+	c.JSON(http.StatusOK, diff)
+}
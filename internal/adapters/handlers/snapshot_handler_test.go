@@ -0,0 +1,85 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"recommender/internal/core/domain"
+	"recommender/internal/core/services"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+)
+
+type fakeSnapshotRepository struct {
+	snapshots []domain.RecommendationSnapshot
+}
+
+func (f *fakeSnapshotRepository) Save(snapshot *domain.RecommendationSnapshot) error {
+	snapshot.ID = uint(len(f.snapshots) + 1)
+	f.snapshots = append(f.snapshots, *snapshot)
+	return nil
+}
+
+func (f *fakeSnapshotRepository) List(strategy string, limit, offset int) ([]domain.RecommendationSnapshot, error) {
+	return f.snapshots, nil
+}
+
+func (f *fakeSnapshotRepository) GetByID(id uint) (*domain.RecommendationSnapshot, error) {
+	if id == 0 || int(id) > len(f.snapshots) {
+		return nil, gorm.ErrRecordNotFound
+	}
+	return &f.snapshots[id-1], nil
+}
+
+func (f *fakeSnapshotRepository) GetLatestAt(paramsKey string, at time.Time) (*domain.RecommendationSnapshot, error) {
+	if len(f.snapshots) == 0 {
+		return nil, gorm.ErrRecordNotFound
+	}
+	return &f.snapshots[len(f.snapshots)-1], nil
+}
+
+func TestSnapshotEndpoints(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	repo := &fakeSnapshotRepository{}
+	service := services.NewSnapshotService(repo)
+	now := time.Now()
+	service.Record(domain.RecommendationList{Strategy: "default", GeneratedAt: now.Add(-time.Hour),
+		Items: []domain.Recommendation{{Rank: 1, Ticker: "AAPL"}}})
+	service.Record(domain.RecommendationList{Strategy: "default", GeneratedAt: now,
+		Items: []domain.Recommendation{{Rank: 1, Ticker: "MSFT"}}})
+
+	handler := NewSnapshotHandler(service)
+	router := gin.New()
+	router.GET("/recommendations/snapshots", handler.GetSnapshots)
+	router.GET("/recommendations/diff", handler.GetDiff)
+
+	req, _ := http.NewRequest("GET", "/recommendations/snapshots", nil)
+	resp := httptest.NewRecorder()
+	router.ServeHTTP(resp, req)
+	assert.Equal(t, http.StatusOK, resp.Code)
+
+	req, _ = http.NewRequest("GET", "/recommendations/diff?from=1&to=2", nil)
+	resp = httptest.NewRecorder()
+	router.ServeHTTP(resp, req)
+	assert.Equal(t, http.StatusOK, resp.Code)
+
+	var diff domain.RecommendationDiff
+	assert.Nil(t, json.Unmarshal(resp.Body.Bytes(), &diff))
+	assert.Equal(t, "MSFT", diff.Entries[0].Ticker)
+	assert.Equal(t, "AAPL", diff.Exits[0].Ticker)
+
+	req, _ = http.NewRequest("GET", "/recommendations/diff?from=yesterday", nil)
+	resp = httptest.NewRecorder()
+	router.ServeHTTP(resp, req)
+	assert.Equal(t, http.StatusBadRequest, resp.Code)
+
+	req, _ = http.NewRequest("GET", "/recommendations/diff?from=9", nil)
+	resp = httptest.NewRecorder()
+	router.ServeHTTP(resp, req)
+	assert.Equal(t, http.StatusNotFound, resp.Code)
+}
@@ -1,11 +1,15 @@
 package handlers
 
 import (
+	"errors"
+	"fmt"
 	"net/http"
+	"recommender/internal/adapters/export"
 	"recommender/internal/core/domain"
 	"recommender/internal/core/services"
 
 	"strconv"
+	"strings"
 	"github.com/gin-gonic/gin"
 )
 
@@ -17,26 +21,34 @@ func NewStockHandler(service *services.StockService) *StockHandler {
 	return &StockHandler{service: service}
 }
 
+// GetStocks lista eventos paginados. Con `?format=csv|xlsx` (o el encabezado Accept)
+// exporta todos los eventos del filtro, salvo que se indique `limit`.
 func (h *StockHandler) GetStocks(c *gin.Context) {
-	// Leer parámetros de la query
-	limit := 10 // Valor por defecto
-	offset := 0 // Valor por defecto
-
-	if l, exists := c.GetQuery("limit"); exists {
-		parsedLimit, err := strconv.Atoi(l)
-		if err == nil && parsedLimit > 0 {
-			limit = parsedLimit
-		}
+	format, ok := exportFormat(c)
+	if !ok {
+		return
 	}
+	defaultLimit := 10
+	if format != formatJSON {
+		defaultLimit = 0
+	}
+	limit, offset := parsePage(c, defaultLimit)
 
-	if o, exists := c.GetQuery("offset"); exists {
-		parsedOffset, err := strconv.Atoi(o)
-		if err == nil && parsedOffset >= 0 {
-			offset = parsedOffset
-		}
+	filter, ok := parseStockFilter(c)
+	if !ok {
+		return
+	}
+
+	if format != formatJSON {
+		writeExport(c, format, "stocks", export.StockHeader, func(write func([]any) error) error {
+			return h.service.StreamStocks(filter, limit, offset, func(stock domain.Stock) error {
+				return write(export.StockRow(stock))
+			})
+		})
+		return
 	}
 
-	stocks, err := h.service.FetchStocks(limit, offset)
+	stocks, err := h.service.SearchStocks(filter, limit, offset)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve stocks"})
 		return
@@ -58,13 +70,97 @@ func (h *StockHandler) PostStock(c *gin.Context) {
 	c.JSON(http.StatusCreated, stock)
 }
 func (h *StockHandler) GetRecommendations(c *gin.Context) {
-	limit := 5 // Número de acciones recomendadas
-	stocks, err := h.service.GetTopRecommendedStocks(limit)
+	params, err := parseRecommendationParams(c, h.service.RecommendationDefaults())
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	format, ok := exportFormat(c)
+	if !ok {
+		return
+	}
+
+	recommendations, err := h.service.GetTopRecommendedStocks(params)
+	if err != nil || format == formatJSON {
+		writeRecommendations(c, h.service, recommendations, err)
+		return
+	}
+	writeExport(c, format, "recommendations", export.RecommendationHeader, func(write func([]any) error) error {
+		for _, rec := range recommendations.Items {
+			if err := write(export.RecommendationRow(rec)); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// writeRecommendations responde con la lista o traduce el error del servicio a un código HTTP.
+func writeRecommendations(c *gin.Context, service *services.StockService, recommendations *domain.RecommendationList, err error) {
+	if errors.Is(err, services.ErrUnknownStrategy) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Unknown strategy", "allowed": service.ScoringStrategies()})
+		return
+	}
+	if errors.Is(err, services.ErrInvalidRecommendationParams) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch recommendations"})
 		return
 	}
-	c.JSON(http.StatusOK, stocks)
+	c.JSON(http.StatusOK, recommendations)
+}
+
+// parseStockFilter lee `?action=` (lista separada por comas). Si es inválido responde
+// 400 y devuelve ok en false.
+func parseStockFilter(c *gin.Context) (filter domain.StockFilter, ok bool) {
+	if a, exists := c.GetQuery("action"); exists {
+		for _, raw := range strings.Split(a, ",") {
+			action := domain.ActionType(strings.TrimSpace(raw))
+			if !action.Valid() {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid action filter", "allowed": domain.ActionTypes})
+				return filter, false
+			}
+			filter.ActionTypes = append(filter.ActionTypes, action)
+		}
+	}
+	return filter, true
+}
+
+// parseRecommendationParams parte de los valores configurados y aplica los de la query.
+// Los valores no numéricos se rechazan; los rangos los valida el servicio.
+func parseRecommendationParams(c *gin.Context, defaults domain.RecommendationParams) (domain.RecommendationParams, error) {
+	params := defaults
+
+	intParams := map[string]*int{
+		"limit":             &params.Limit,
+		"lookback_days":     &params.LookbackDays,
+		"candidate_pool":    &params.CandidatePool,
+		"max_per_sector":    &params.MaxPerSector,
+		"max_per_brokerage": &params.MaxPerBrokerage,
+		"min_brokerages":    &params.MinBrokerages,
+	}
+	for name, target := range intParams {
+		raw, exists := c.GetQuery(name)
+		if !exists {
+			continue
+		}
+		value, err := strconv.Atoi(raw)
+		if err != nil {
+			return params, fmt.Errorf("%s must be an integer", name)
+		}
+		*target = value
+	}
+
+	if strategy, exists := c.GetQuery("strategy"); exists {
+		params.Strategy = strategy
+	}
+	if groupBy, exists := c.GetQuery("group_by"); exists {
+		params.GroupBy = groupBy
+	}
+	return params, nil
 }
 
 func (h *StockHandler) GetStockByTicker(c *gin.Context) {
@@ -78,3 +174,53 @@ func (h *StockHandler) GetStockByTicker(c *gin.Context) {
 
 	c.JSON(http.StatusOK, stock)
 }
+
+// GetStockHistory lista los eventos de un ticker del más antiguo al más reciente, con
+// los mismos formatos de exportación que GET /stocks.
+func (h *StockHandler) GetStockHistory(c *gin.Context) {
+	ticker := c.Param("ticker")
+	format, ok := exportFormat(c)
+	if !ok {
+		return
+	}
+	defaultLimit := 100
+	if format != formatJSON {
+		defaultLimit = 0
+	}
+	limit, offset := parsePage(c, defaultLimit)
+
+	if format != formatJSON {
+		writeExport(c, format, ticker+"-history", export.StockHeader, func(write func([]any) error) error {
+			return h.service.StreamStockHistory(ticker, limit, offset, func(stock domain.Stock) error {
+				return write(export.StockRow(stock))
+			})
+		})
+		return
+	}
+
+	history, err := h.service.StockHistory(ticker, limit, offset)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve stock history"})
+		return
+	}
+	if len(history) == 0 && offset == 0 {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Stock not found"})
+		return
+	}
+	c.JSON(http.StatusOK, history)
+}
+
+// GetUnknownRatings lista las etiquetas de rating recibidas que no tienen mapeo.
+func (h *StockHandler) GetUnknownRatings(c *gin.Context) {
+	labels, err := h.service.UnknownRatingLabels()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to count unknown ratings"})
+		return
+	}
+	c.JSON(http.StatusOK, labels)
+}
+
+// GetStrategies lista las estrategias de puntuación disponibles para `?strategy=`.
+func (h *StockHandler) GetStrategies(c *gin.Context) {
+	c.JSON(http.StatusOK, h.service.ScoringStrategies())
+}
@@ -34,6 +34,19 @@ func (f *fakeStockRepository) GetAll(limit, offset int) ([]domain.Stock, error)
 	}, nil
 }
 
+func (f *fakeStockRepository) GetFiltered(filter domain.StockFilter, limit, offset int) ([]domain.Stock, error) {
+	all, _ := f.GetAll(limit, offset)
+	var stocks []domain.Stock
+	for _, s := range all {
+		for _, action := range filter.ActionTypes {
+			if domain.ParseAction(s.Action) == action {
+				stocks = append(stocks, s)
+			}
+		}
+	}
+	return stocks, nil
+}
+
 func (f *fakeStockRepository) Create(stock *domain.Stock) error {
 	return nil
 }
@@ -42,6 +55,10 @@ func (f *fakeStockRepository) GetStockByTickerAndTime(ticker string, t time.Time
 	return nil, nil
 }
 
+func (f *fakeStockRepository) GetStockByExternalID(source, externalID string) (*domain.Stock, error) {
+	return nil, nil
+}
+
 func (f *fakeStockRepository) GetTopStocksByTarget(limit int) ([]domain.Stock, error) {
 	return nil, nil
 }
@@ -50,7 +67,7 @@ func (f *fakeStockRepository) GetStockByTicker(ticker string) (*domain.Stock, er
 	return nil, nil
 }
 
-func (f *fakeStockRepository) GetRecentStocks(limit int) ([]domain.Stock, error) {
+func (f *fakeStockRepository) GetRecentStocks(since time.Time, limit int) ([]domain.Stock, error) {
 	return nil, nil
 }
 
@@ -173,6 +190,8 @@ func TestGetRecommendations_Success(t *testing.T) {
 	assert.Equal(t, http.StatusOK, resp.Code)
 	assert.Contains(t, resp.Body.String(), "TSLA")
 	assert.Contains(t, resp.Body.String(), "NVDA")
+	assert.Contains(t, resp.Body.String(), `"scorer_version":"1.1/none"`)
+	assert.Contains(t, resp.Body.String(), `"rationale"`)
 }
 
 func TestGetStockByTicker_Success(t *testing.T) {
@@ -231,6 +250,10 @@ func (f *fakeStockRepositoryWithRecommendations) GetAll(limit, offset int) ([]do
 	return []domain.Stock{}, nil
 }
 
+func (f *fakeStockRepositoryWithRecommendations) GetFiltered(filter domain.StockFilter, limit, offset int) ([]domain.Stock, error) {
+	return []domain.Stock{}, nil
+}
+
 func (f *fakeStockRepositoryWithRecommendations) Create(stock *domain.Stock) error {
 	return nil
 }
@@ -239,6 +262,10 @@ func (f *fakeStockRepositoryWithRecommendations) GetStockByTickerAndTime(ticker
 	return nil, nil
 }
 
+func (f *fakeStockRepositoryWithRecommendations) GetStockByExternalID(source, externalID string) (*domain.Stock, error) {
+	return nil, nil
+}
+
 func (f *fakeStockRepositoryWithRecommendations) GetTopStocksByTarget(limit int) ([]domain.Stock, error) {
 	return []domain.Stock{
 		{
@@ -272,7 +299,7 @@ func (f *fakeStockRepositoryWithRecommendations) GetStockByTicker(ticker string)
 	return nil, nil
 }
 
-func (f *fakeStockRepositoryWithRecommendations) GetRecentStocks(limit int) ([]domain.Stock, error) {
+func (f *fakeStockRepositoryWithRecommendations) GetRecentStocks(since time.Time, limit int) ([]domain.Stock, error) {
 	return []domain.Stock{
 		{
 			ID:         1,
@@ -308,6 +335,10 @@ func (f *fakeStockRepositoryWithTicker) GetAll(limit, offset int) ([]domain.Stoc
 	return []domain.Stock{}, nil
 }
 
+func (f *fakeStockRepositoryWithTicker) GetFiltered(filter domain.StockFilter, limit, offset int) ([]domain.Stock, error) {
+	return []domain.Stock{}, nil
+}
+
 func (f *fakeStockRepositoryWithTicker) Create(stock *domain.Stock) error {
 	return nil
 }
@@ -316,6 +347,10 @@ func (f *fakeStockRepositoryWithTicker) GetStockByTickerAndTime(ticker string, t
 	return nil, nil
 }
 
+func (f *fakeStockRepositoryWithTicker) GetStockByExternalID(source, externalID string) (*domain.Stock, error) {
+	return nil, nil
+}
+
 func (f *fakeStockRepositoryWithTicker) GetTopStocksByTarget(limit int) ([]domain.Stock, error) {
 	return nil, nil
 }
@@ -338,7 +373,7 @@ func (f *fakeStockRepositoryWithTicker) GetStockByTicker(ticker string) (*domain
 	return nil, errors.New("stock not found")
 }
 
-func (f *fakeStockRepositoryWithTicker) GetRecentStocks(limit int) ([]domain.Stock, error) {
+func (f *fakeStockRepositoryWithTicker) GetRecentStocks(since time.Time, limit int) ([]domain.Stock, error) {
 	return nil, nil
 }
 
@@ -349,6 +384,10 @@ func (f *fakeStockRepositoryNotFound) GetAll(limit, offset int) ([]domain.Stock,
 	return []domain.Stock{}, nil
 }
 
+func (f *fakeStockRepositoryNotFound) GetFiltered(filter domain.StockFilter, limit, offset int) ([]domain.Stock, error) {
+	return []domain.Stock{}, nil
+}
+
 func (f *fakeStockRepositoryNotFound) Create(stock *domain.Stock) error {
 	return nil
 }
@@ -357,6 +396,10 @@ func (f *fakeStockRepositoryNotFound) GetStockByTickerAndTime(ticker string, t t
 	return nil, nil
 }
 
+func (f *fakeStockRepositoryNotFound) GetStockByExternalID(source, externalID string) (*domain.Stock, error) {
+	return nil, nil
+}
+
 func (f *fakeStockRepositoryNotFound) GetTopStocksByTarget(limit int) ([]domain.Stock, error) {
 	return nil, nil
 }
@@ -365,6 +408,96 @@ func (f *fakeStockRepositoryNotFound) GetStockByTicker(ticker string) (*domain.S
 	return nil, errors.New("stock not found")
 }
 
-func (f *fakeStockRepositoryNotFound) GetRecentStocks(limit int) ([]domain.Stock, error) {
+func (f *fakeStockRepositoryNotFound) GetRecentStocks(since time.Time, limit int) ([]domain.Stock, error) {
 	return nil, nil
-}
\ No newline at end of file
+}
+func TestGetStocks_ActionFilter(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	service := services.NewStockService(&fakeStockRepository{}, &fakeStockAPIClient{})
+	handler := NewStockHandler(service)
+
+	router := gin.New()
+	router.GET("/stocks", handler.GetStocks)
+
+	req, _ := http.NewRequest("GET", "/stocks?action=target_raised", nil)
+	resp := httptest.NewRecorder()
+	router.ServeHTTP(resp, req)
+
+	assert.Equal(t, http.StatusOK, resp.Code)
+	assert.Contains(t, resp.Body.String(), "AAPL")
+
+	req, _ = http.NewRequest("GET", "/stocks?action=bought", nil)
+	resp = httptest.NewRecorder()
+	router.ServeHTTP(resp, req)
+
+	assert.Equal(t, http.StatusBadRequest, resp.Code)
+	assert.Contains(t, resp.Body.String(), "Invalid action filter")
+}
+
+func TestGetRecommendations_UnknownStrategy(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	service := services.NewStockService(&fakeStockRepositoryWithRecommendations{}, &fakeStockAPIClient{})
+	handler := NewStockHandler(service)
+
+	router := gin.New()
+	router.GET("/stocks/recommendations", handler.GetRecommendations)
+
+	req, _ := http.NewRequest("GET", "/stocks/recommendations?strategy=magic", nil)
+	resp := httptest.NewRecorder()
+	router.ServeHTTP(resp, req)
+
+	assert.Equal(t, http.StatusBadRequest, resp.Code)
+	assert.Contains(t, resp.Body.String(), "Unknown strategy")
+	assert.Contains(t, resp.Body.String(), "default")
+}
+
+func TestGetRecommendations_InvalidParams(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	service := services.NewStockService(&fakeStockRepositoryWithRecommendations{}, &fakeStockAPIClient{})
+	handler := NewStockHandler(service)
+
+	router := gin.New()
+	router.GET("/stocks/recommendations", handler.GetRecommendations)
+
+	for _, query := range []string{"limit=abc", "limit=0", "lookback_days=1000", "candidate_pool=-1"} {
+		req, _ := http.NewRequest("GET", "/stocks/recommendations?"+query, nil)
+		resp := httptest.NewRecorder()
+		router.ServeHTTP(resp, req)
+
+		assert.Equal(t, http.StatusBadRequest, resp.Code, query)
+	}
+
+	req, _ := http.NewRequest("GET", "/stocks/recommendations?limit=1&lookback_days=7", nil)
+	resp := httptest.NewRecorder()
+	router.ServeHTTP(resp, req)
+
+	assert.Equal(t, http.StatusOK, resp.Code)
+	assert.Contains(t, resp.Body.String(), `"lookback_days":7`)
+}
+
+func TestGetRecommendations_GroupBy(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	service := services.NewStockService(&fakeStockRepositoryWithRecommendations{}, &fakeStockAPIClient{})
+	handler := NewStockHandler(service)
+
+	router := gin.New()
+	router.GET("/stocks/recommendations", handler.GetRecommendations)
+
+	req, _ := http.NewRequest("GET", "/stocks/recommendations?group_by=event", nil)
+	resp := httptest.NewRecorder()
+	router.ServeHTTP(resp, req)
+
+	assert.Equal(t, http.StatusOK, resp.Code)
+	assert.Contains(t, resp.Body.String(), `"group_by":"event"`)
+	assert.NotContains(t, resp.Body.String(), `"aggregate"`)
+
+	req, _ = http.NewRequest("GET", "/stocks/recommendations?group_by=sector", nil)
+	resp = httptest.NewRecorder()
+	router.ServeHTTP(resp, req)
+
+	assert.Equal(t, http.StatusBadRequest, resp.Code)
+}
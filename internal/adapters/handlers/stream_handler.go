@@ -0,0 +1,98 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"recommender/internal/core/domain"
+	"recommender/internal/core/services"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+type StreamHandler struct {
+	hub       *services.StreamHub
+	heartbeat time.Duration
+}
+
+func NewStreamHandler(hub *services.StreamHub, heartbeat time.Duration) *StreamHandler {
+	return &StreamHandler{hub: hub, heartbeat: heartbeat}
+}
+
+// GetStockStream emite por SSE cada stock nuevo con `id` igual a su secuencia.
+// Filtros: `ticker` (lista separada por comas) y `brokerage`. Para reanudar se usa el
+// encabezado Last-Event-ID o `?last_event_id=`. Envía un comentario cada heartbeat.
+func (h *StreamHandler) GetStockStream(c *gin.Context) {
+	filter := services.StreamFilter{Brokerage: strings.TrimSpace(c.Query("brokerage"))}
+	if t, exists := c.GetQuery("ticker"); exists {
+		filter.Tickers = strings.Split(t, ",")
+	}
+
+	lastEventID := c.GetHeader("Last-Event-ID")
+	if lastEventID == "" {
+		lastEventID = c.Query("last_event_id")
+	}
+	var after uint64
+	if lastEventID != "" {
+		var err error
+		if after, err = strconv.ParseUint(lastEventID, 10, 64); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid Last-Event-ID"})
+			return
+		}
+	}
+
+	sub := h.hub.Subscribe(filter, uint(after), lastEventID != "")
+	defer h.hub.Unsubscribe(sub)
+
+	c.Header("Content-Type", "text/event-stream")
+	c.Header("Cache-Control", "no-cache")
+	c.Header("Connection", "keep-alive")
+	c.Header("X-Accel-Buffering", "no") // Evita el buffering de proxies como nginx
+	c.Status(http.StatusOK)
+
+	w := c.Writer
+	fmt.Fprintf(w, "retry: %d\n\n", 3000)
+	err := h.hub.Backlog(sub, func(event domain.StockEvent) error {
+		return writeStockEvent(w, event)
+	})
+	if err != nil {
+		log.Printf("⚠ Error enviando el backlog del stream: %v", err)
+		return // El cliente reanuda con el último ID que recibió
+	}
+	w.Flush()
+
+	heartbeat := time.NewTicker(h.heartbeat)
+	defer heartbeat.Stop()
+	for {
+		select {
+		case <-c.Request.Context().Done():
+			return
+		case event, open := <-sub.C:
+			if !open {
+				return // El hub desconectó al suscriptor; el cliente reanuda con Last-Event-ID
+			}
+			if err := writeStockEvent(w, event); err != nil {
+				return
+			}
+		case <-heartbeat.C:
+			if _, err := io.WriteString(w, ": heartbeat\n\n"); err != nil {
+				return
+			}
+		}
+		w.Flush()
+	}
+}
+
+func writeStockEvent(w io.Writer, event domain.StockEvent) error {
+	data, err := json.Marshal(event.Stock)
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(w, "id: %d\nevent: stock\ndata: %s\n\n", event.Sequence, data)
+	return err
+}
@@ -0,0 +1,86 @@
+package handlers
+
+import (
+	"bufio"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"recommender/internal/core/domain"
+	"recommender/internal/core/services"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+type fakeOutboxReader struct {
+	events []domain.OutboxEvent
+}
+
+func (f *fakeOutboxReader) EventsAfter(afterID uint, limit int) ([]domain.OutboxEvent, error) {
+	var events []domain.OutboxEvent
+	for _, event := range f.events {
+		if event.ID > afterID {
+			events = append(events, event)
+		}
+	}
+	return events, nil
+}
+
+func (f *fakeOutboxReader) LatestEventID() (uint, error) {
+	return uint(len(f.events)), nil
+}
+
+func TestGetStockStream(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	outbox := &fakeOutboxReader{}
+	for i, ticker := range []string{"AAPL", "MSFT", "AAPL"} {
+		event, _ := domain.NewStockCreatedEvent(domain.Stock{Ticker: ticker, Brokerage: "Goldman Sachs"})
+		event.ID = uint(i + 1)
+		outbox.events = append(outbox.events, event)
+	}
+	hub := services.NewStreamHub(outbox, time.Hour)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	assert.Nil(t, hub.Start(ctx))
+
+	router := gin.New()
+	router.GET("/stocks/stream", NewStreamHandler(hub, 20*time.Millisecond).GetStockStream)
+	server := httptest.NewServer(router)
+	defer server.Close()
+
+	req, _ := http.NewRequestWithContext(ctx, "GET", server.URL+"/stocks/stream?ticker=AAPL", nil)
+	req.Header.Set("Last-Event-ID", "1")
+	resp, err := http.DefaultClient.Do(req)
+	assert.Nil(t, err)
+	defer resp.Body.Close()
+	assert.Equal(t, "text/event-stream", resp.Header.Get("Content-Type"))
+
+	var lines []string
+	scanner := bufio.NewScanner(resp.Body)
+	for scanner.Scan() {
+		lines = append(lines, scanner.Text())
+		if scanner.Text() == ": heartbeat" {
+			break
+		}
+	}
+	body := strings.Join(lines, "\n")
+	assert.Contains(t, body, "id: 3\nevent: stock\ndata: {")
+	assert.NotContains(t, body, "id: 2")
+	assert.NotContains(t, body, "MSFT")
+}
+
+func TestGetStockStream_InvalidLastEventID(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	router := gin.New()
+	router.GET("/stocks/stream", NewStreamHandler(services.NewStreamHub(&fakeOutboxReader{}, time.Hour), time.Second).GetStockStream)
+
+	req, _ := http.NewRequest("GET", "/stocks/stream?last_event_id=abc", nil)
+	resp := httptest.NewRecorder()
+	router.ServeHTTP(resp, req)
+	assert.Equal(t, http.StatusBadRequest, resp.Code)
+}
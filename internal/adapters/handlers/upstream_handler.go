@@ -0,0 +1,30 @@
+package handlers
+
+import (
+	"net/http"
+	"recommender/internal/core/domain"
+	"recommender/internal/core/ports"
+
+	"github.com/gin-gonic/gin"
+)
+
+type UpstreamHandler struct {
+	monitor ports.UpstreamMonitor
+}
+
+func NewUpstreamHandler(monitor ports.UpstreamMonitor) *UpstreamHandler {
+	return &UpstreamHandler{monitor: monitor}
+}
+
+// GetUpstreamStatus muestra por proveedor los reintentos, los 429 y el estado del circuito.
+// Responde 503 si algún circuito está abierto, para que sirva como health check.
+func (h *UpstreamHandler) GetUpstreamStatus(c *gin.Context) {
+	stats := h.monitor.UpstreamStats()
+	status := http.StatusOK
+	for _, provider := range stats {
+		if provider.Circuit == domain.CircuitOpen {
+			status = http.StatusServiceUnavailable
+		}
+	}
+	c.JSON(status, stats)
+}
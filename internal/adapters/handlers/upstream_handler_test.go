@@ -0,0 +1,43 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"recommender/internal/core/domain"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+type fakeUpstreamMonitor struct {
+	stats []domain.UpstreamStats
+}
+
+func (f *fakeUpstreamMonitor) UpstreamStats() []domain.UpstreamStats {
+	return f.stats
+}
+
+func TestGetUpstreamStatus(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	monitor := &fakeUpstreamMonitor{stats: []domain.UpstreamStats{
+		{Source: "default", Circuit: domain.CircuitClosed, Requests: 12, Retries: 2, RateLimited: 1},
+	}}
+	router := gin.New()
+	router.GET("/upstream/status", NewUpstreamHandler(monitor).GetUpstreamStatus)
+
+	req, _ := http.NewRequest("GET", "/upstream/status", nil)
+	resp := httptest.NewRecorder()
+	router.ServeHTTP(resp, req)
+
+	assert.Equal(t, http.StatusOK, resp.Code)
+	var body []domain.UpstreamStats
+	assert.NoError(t, json.Unmarshal(resp.Body.Bytes(), &body))
+	assert.Equal(t, monitor.stats, body)
+
+	monitor.stats = append(monitor.stats, domain.UpstreamStats{Source: "acme", Circuit: domain.CircuitOpen})
+	resp = httptest.NewRecorder()
+	router.ServeHTTP(resp, req)
+	assert.Equal(t, http.StatusServiceUnavailable, resp.Code)
+}
@@ -0,0 +1,170 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"recommender/internal/core/services"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+type WatchlistHandler struct {
+	service *services.WatchlistService
+	stocks  *services.StockService
+}
+
+func NewWatchlistHandler(service *services.WatchlistService, stocks *services.StockService) *WatchlistHandler {
+	return &WatchlistHandler{service: service, stocks: stocks}
+}
+
+type watchlistRequest struct {
+	Name    string   `json:"name"`
+	Tickers []string `json:"tickers"`
+}
+
+func (h *WatchlistHandler) GetWatchlists(c *gin.Context) {
+	watchlists, err := h.service.List(currentUser(c))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve watchlists"})
+		return
+	}
+	c.JSON(http.StatusOK, watchlists)
+}
+
+func (h *WatchlistHandler) GetWatchlist(c *gin.Context) {
+	id, ok := watchlistID(c)
+	if !ok {
+		return
+	}
+	watchlist, err := h.service.Get(currentUser(c), id)
+	if err != nil {
+		writeWatchlistError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, watchlist)
+}
+
+func (h *WatchlistHandler) PostWatchlist(c *gin.Context) {
+	var req watchlistRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request"})
+		return
+	}
+	watchlist, err := h.service.Create(currentUser(c), req.Name, req.Tickers)
+	if err != nil {
+		writeWatchlistError(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, watchlist)
+}
+
+func (h *WatchlistHandler) PutWatchlist(c *gin.Context) {
+	id, ok := watchlistID(c)
+	if !ok {
+		return
+	}
+	var req watchlistRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request"})
+		return
+	}
+	watchlist, err := h.service.Update(currentUser(c), id, req.Name, req.Tickers)
+	if err != nil {
+		writeWatchlistError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, watchlist)
+}
+
+func (h *WatchlistHandler) DeleteWatchlist(c *gin.Context) {
+	id, ok := watchlistID(c)
+	if !ok {
+		return
+	}
+	if err := h.service.Delete(currentUser(c), id); err != nil {
+		writeWatchlistError(c, err)
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// GetWatchlistStocks es el feed de ratings acotado a la watchlist; acepta los mismos
+// parámetros que GET /stocks.
+func (h *WatchlistHandler) GetWatchlistStocks(c *gin.Context) {
+	id, ok := watchlistID(c)
+	if !ok {
+		return
+	}
+
+	limit := 10 // Valor por defecto
+	offset := 0 // Valor por defecto
+
+	if l, exists := c.GetQuery("limit"); exists {
+		parsedLimit, err := strconv.Atoi(l)
+		if err == nil && parsedLimit > 0 {
+			limit = parsedLimit
+		}
+	}
+
+	if o, exists := c.GetQuery("offset"); exists {
+		parsedOffset, err := strconv.Atoi(o)
+		if err == nil && parsedOffset >= 0 {
+			offset = parsedOffset
+		}
+	}
+
+	filter, ok := parseStockFilter(c)
+	if !ok {
+		return
+	}
+
+	stocks, err := h.service.Feed(currentUser(c), id, filter, limit, offset)
+	if err != nil {
+		writeWatchlistError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, stocks)
+}
+
+// GetWatchlistRecommendations acepta los mismos parámetros que /stocks/recommendations.
+func (h *WatchlistHandler) GetWatchlistRecommendations(c *gin.Context) {
+	id, ok := watchlistID(c)
+	if !ok {
+		return
+	}
+	params, err := parseRecommendationParams(c, h.stocks.RecommendationDefaults())
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	recommendations, err := h.service.Recommendations(currentUser(c), id, params)
+	if errors.Is(err, services.ErrWatchlistNotFound) {
+		writeWatchlistError(c, err)
+		return
+	}
+	writeRecommendations(c, h.stocks, recommendations, err)
+}
+
+func watchlistID(c *gin.Context) (uint, bool) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid watchlist id"})
+		return 0, false
+	}
+	return uint(id), true
+}
+
+func writeWatchlistError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, services.ErrWatchlistNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "Watchlist not found"})
+	case errors.Is(err, services.ErrInvalidWatchlist):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case errors.Is(err, services.ErrDuplicateWatchlist):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to process watchlist"})
+	}
+}
@@ -0,0 +1,126 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"recommender/internal/core/domain"
+	"recommender/internal/core/services"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+)
+
+type fakeWatchlistRepository struct {
+	watchlists []domain.Watchlist
+}
+
+func (f *fakeWatchlistRepository) Create(watchlist *domain.Watchlist) error {
+	watchlist.ID = uint(len(f.watchlists) + 1)
+	f.watchlists = append(f.watchlists, *watchlist)
+	return nil
+}
+
+func (f *fakeWatchlistRepository) Update(watchlist *domain.Watchlist) error {
+	f.watchlists[watchlist.ID-1] = *watchlist
+	return nil
+}
+
+func (f *fakeWatchlistRepository) Delete(userID string, id uint) error {
+	if _, err := f.GetByID(userID, id); err != nil {
+		return err
+	}
+	f.watchlists[id-1].UserID = ""
+	return nil
+}
+
+func (f *fakeWatchlistRepository) GetByID(userID string, id uint) (*domain.Watchlist, error) {
+	if id == 0 || int(id) > len(f.watchlists) || f.watchlists[id-1].UserID != userID {
+		return nil, gorm.ErrRecordNotFound
+	}
+	watchlist := f.watchlists[id-1]
+	return &watchlist, nil
+}
+
+func (f *fakeWatchlistRepository) GetByName(userID, name string) (*domain.Watchlist, error) {
+	for _, watchlist := range f.watchlists {
+		if watchlist.UserID == userID && watchlist.Name == name {
+			return &watchlist, nil
+		}
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+func (f *fakeWatchlistRepository) ListByUser(userID string) ([]domain.Watchlist, error) {
+	var watchlists []domain.Watchlist
+	for _, watchlist := range f.watchlists {
+		if watchlist.UserID == userID {
+			watchlists = append(watchlists, watchlist)
+		}
+	}
+	return watchlists, nil
+}
+
+func setupWatchlistRouter() *gin.Engine {
+	gin.SetMode(gin.TestMode)
+
+	stockService := services.NewStockService(&fakeStockRepositoryWithRecommendations{}, nil)
+	handler := NewWatchlistHandler(services.NewWatchlistService(&fakeWatchlistRepository{}, stockService), stockService)
+
+	router := gin.New()
+	watchlists := router.Group("/watchlists", RequireUser())
+	watchlists.GET("", handler.GetWatchlists)
+	watchlists.POST("", handler.PostWatchlist)
+	watchlists.GET("/:id", handler.GetWatchlist)
+	watchlists.PUT("/:id", handler.PutWatchlist)
+	watchlists.DELETE("/:id", handler.DeleteWatchlist)
+	watchlists.GET("/:id/recommendations", handler.GetWatchlistRecommendations)
+	return router
+}
+
+func watchlistRequestAs(router *gin.Engine, user, method, path, body string) *httptest.ResponseRecorder {
+	req, _ := http.NewRequest(method, path, strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	if user != "" {
+		req.Header.Set(UserIDHeader, user)
+	}
+	resp := httptest.NewRecorder()
+	router.ServeHTTP(resp, req)
+	return resp
+}
+
+func TestWatchlistEndpoints(t *testing.T) {
+	router := setupWatchlistRouter()
+
+	resp := watchlistRequestAs(router, "", "GET", "/watchlists", "")
+	assert.Equal(t, http.StatusUnauthorized, resp.Code)
+
+	resp = watchlistRequestAs(router, "alice", "POST", "/watchlists", `{"name":"evs","tickers":["tsla"]}`)
+	assert.Equal(t, http.StatusCreated, resp.Code)
+
+	resp = watchlistRequestAs(router, "alice", "POST", "/watchlists", `{"name":"evs"}`)
+	assert.Equal(t, http.StatusConflict, resp.Code)
+
+	resp = watchlistRequestAs(router, "bob", "GET", "/watchlists/1", "")
+	assert.Equal(t, http.StatusNotFound, resp.Code)
+
+	resp = watchlistRequestAs(router, "alice", "GET", "/watchlists/1/recommendations", "")
+	assert.Equal(t, http.StatusOK, resp.Code)
+	var list domain.RecommendationList
+	assert.Nil(t, json.Unmarshal(resp.Body.Bytes(), &list))
+	assert.Len(t, list.Items, 1)
+	assert.Equal(t, "TSLA", list.Items[0].Ticker)
+
+	resp = watchlistRequestAs(router, "alice", "PUT", "/watchlists/1", `{"name":"evs","tickers":["NVDA"]}`)
+	assert.Equal(t, http.StatusOK, resp.Code)
+	assert.Contains(t, resp.Body.String(), "NVDA")
+
+	resp = watchlistRequestAs(router, "alice", "DELETE", "/watchlists/1", "")
+	assert.Equal(t, http.StatusNoContent, resp.Code)
+
+	resp = watchlistRequestAs(router, "alice", "GET", "/watchlists/abc", "")
+	assert.Equal(t, http.StatusBadRequest, resp.Code)
+}
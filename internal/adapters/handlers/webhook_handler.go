@@ -0,0 +1,131 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"recommender/internal/core/domain"
+	"recommender/internal/core/services"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+type WebhookHandler struct {
+	service *services.WebhookService
+}
+
+func NewWebhookHandler(service *services.WebhookService) *WebhookHandler {
+	return &WebhookHandler{service: service}
+}
+
+type webhookRequest struct {
+	URL         string              `json:"url"`
+	Secret      string              `json:"secret"`
+	ActionTypes []domain.ActionType `json:"action_types"`
+	Tickers     []string            `json:"tickers"`
+}
+
+func (h *WebhookHandler) GetSubscriptions(c *gin.Context) {
+	subscriptions, err := h.service.ListSubscriptions(currentUser(c))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve webhooks"})
+		return
+	}
+	c.JSON(http.StatusOK, subscriptions)
+}
+
+// PostSubscription crea la suscripción; la respuesta es la única que incluye el secreto.
+func (h *WebhookHandler) PostSubscription(c *gin.Context) {
+	var req webhookRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request"})
+		return
+	}
+	subscription := &domain.WebhookSubscription{
+		UserID:      currentUser(c),
+		URL:         req.URL,
+		Secret:      req.Secret,
+		ActionTypes: req.ActionTypes,
+		Tickers:     req.Tickers,
+		Active:      true,
+	}
+	if err := h.service.CreateSubscription(subscription); err != nil {
+		writeWebhookError(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, subscription)
+}
+
+func (h *WebhookHandler) DeleteSubscription(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid webhook id"})
+		return
+	}
+	if err := h.service.DeleteSubscription(currentUser(c), uint(id)); err != nil {
+		writeWebhookError(c, err)
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// GetDeliveries es el registro de entregas de la suscripción, de la más nueva a la más vieja.
+func (h *WebhookHandler) GetDeliveries(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid webhook id"})
+		return
+	}
+
+	limit := 50 // Valor por defecto
+	offset := 0 // Valor por defecto
+
+	if l, exists := c.GetQuery("limit"); exists {
+		parsedLimit, err := strconv.Atoi(l)
+		if err == nil && parsedLimit > 0 {
+			limit = parsedLimit
+		}
+	}
+
+	if o, exists := c.GetQuery("offset"); exists {
+		parsedOffset, err := strconv.Atoi(o)
+		if err == nil && parsedOffset >= 0 {
+			offset = parsedOffset
+		}
+	}
+
+	deliveries, err := h.service.ListDeliveries(currentUser(c), uint(id), limit, offset)
+	if err != nil {
+		writeWebhookError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, deliveries)
+}
+
+// PostReplay vuelve a encolar una entrega para enviarla en el próximo ciclo.
+func (h *WebhookHandler) PostReplay(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid delivery id"})
+		return
+	}
+	delivery, err := h.service.Replay(currentUser(c), uint(id))
+	if err != nil {
+		writeWebhookError(c, err)
+		return
+	}
+	c.JSON(http.StatusAccepted, delivery)
+}
+
+func writeWebhookError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, services.ErrInvalidWebhook):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case errors.Is(err, services.ErrWebhookNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "Webhook not found"})
+	case errors.Is(err, services.ErrWebhookDeliveryNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "Delivery not found"})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to process webhook"})
+	}
+}
@@ -0,0 +1,115 @@
+package handlers
+
+import (
+	"net/http"
+	"recommender/internal/core/domain"
+	"recommender/internal/core/services"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+)
+
+type fakeWebhookRepository struct {
+	subscriptions []domain.WebhookSubscription
+	deliveries    []domain.WebhookDelivery
+}
+
+func (f *fakeWebhookRepository) CreateSubscription(subscription *domain.WebhookSubscription) error {
+	subscription.ID = uint(len(f.subscriptions) + 1)
+	f.subscriptions = append(f.subscriptions, *subscription)
+	return nil
+}
+
+func (f *fakeWebhookRepository) GetSubscription(userID string, id uint) (*domain.WebhookSubscription, error) {
+	if id == 0 || int(id) > len(f.subscriptions) || f.subscriptions[id-1].UserID != userID {
+		return nil, gorm.ErrRecordNotFound
+	}
+	return &f.subscriptions[id-1], nil
+}
+
+func (f *fakeWebhookRepository) ListSubscriptions(userID string) ([]domain.WebhookSubscription, error) {
+	return append([]domain.WebhookSubscription(nil), f.subscriptions...), nil
+}
+
+func (f *fakeWebhookRepository) DeleteSubscription(userID string, id uint) error {
+	_, err := f.GetSubscription(userID, id)
+	return err
+}
+
+func (f *fakeWebhookRepository) ListActiveSubscriptions() ([]domain.WebhookSubscription, error) {
+	return f.subscriptions, nil
+}
+
+func (f *fakeWebhookRepository) PendingOutboxEvents(limit int) ([]domain.OutboxEvent, error) {
+	return nil, nil
+}
+
+func (f *fakeWebhookRepository) GetOutboxEvent(id uint) (*domain.OutboxEvent, error) {
+	return nil, gorm.ErrRecordNotFound
+}
+
+func (f *fakeWebhookRepository) FanOut(event domain.OutboxEvent, deliveries []domain.WebhookDelivery, at time.Time) error {
+	return nil
+}
+
+func (f *fakeWebhookRepository) DueDeliveries(now time.Time, limit int) ([]domain.WebhookDelivery, error) {
+	return nil, nil
+}
+
+func (f *fakeWebhookRepository) GetDelivery(id uint) (*domain.WebhookDelivery, error) {
+	if id == 0 || int(id) > len(f.deliveries) {
+		return nil, gorm.ErrRecordNotFound
+	}
+	delivery := f.deliveries[id-1]
+	return &delivery, nil
+}
+
+func (f *fakeWebhookRepository) SaveDelivery(delivery *domain.WebhookDelivery) error {
+	f.deliveries[delivery.ID-1] = *delivery
+	return nil
+}
+
+func (f *fakeWebhookRepository) ListDeliveries(subscriptionID uint, limit, offset int) ([]domain.WebhookDelivery, error) {
+	return f.deliveries, nil
+}
+
+func TestWebhookEndpoints(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	repo := &fakeWebhookRepository{}
+	handler := NewWebhookHandler(services.NewWebhookService(repo, nil, services.DefaultWebhookRetryPolicy()))
+
+	router := gin.New()
+	webhooks := router.Group("/webhooks", RequireUser())
+	webhooks.GET("", handler.GetSubscriptions)
+	webhooks.POST("", handler.PostSubscription)
+	webhooks.DELETE("/:id", handler.DeleteSubscription)
+	webhooks.GET("/:id/deliveries", handler.GetDeliveries)
+	webhooks.POST("/deliveries/:id/replay", handler.PostReplay)
+
+	resp := watchlistRequestAs(router, "alice", "POST", "/webhooks", `{"url":"not a url"}`)
+	assert.Equal(t, http.StatusBadRequest, resp.Code)
+
+	resp = watchlistRequestAs(router, "alice", "POST", "/webhooks", `{"url":"https://example.com/hook","action_types":["downgrade"]}`)
+	assert.Equal(t, http.StatusCreated, resp.Code)
+	assert.Contains(t, resp.Body.String(), `"secret":"`)
+
+	resp = watchlistRequestAs(router, "alice", "GET", "/webhooks", "")
+	assert.Equal(t, http.StatusOK, resp.Code)
+	assert.NotContains(t, resp.Body.String(), `"secret"`)
+
+	repo.deliveries = []domain.WebhookDelivery{{ID: 1, SubscriptionID: 1, Status: domain.DeliveryFailed, Attempts: 8}}
+
+	resp = watchlistRequestAs(router, "bob", "GET", "/webhooks/1/deliveries", "")
+	assert.Equal(t, http.StatusNotFound, resp.Code)
+
+	resp = watchlistRequestAs(router, "alice", "POST", "/webhooks/deliveries/1/replay", "")
+	assert.Equal(t, http.StatusAccepted, resp.Code)
+	assert.Equal(t, domain.DeliveryPending, repo.deliveries[0].Status)
+
+	resp = watchlistRequestAs(router, "alice", "POST", "/webhooks/deliveries/7/replay", "")
+	assert.Equal(t, http.StatusNotFound, resp.Code)
+}
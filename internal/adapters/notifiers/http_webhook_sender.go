@@ -0,0 +1,36 @@
+package notifiers
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"time"
+
+	"recommender/internal/core/ports"
+)
+
+// HTTPWebhookSender hace los POST de las suscripciones de webhooks.
+type HTTPWebhookSender struct {
+	client *http.Client
+}
+
+func NewHTTPWebhookSender(timeout time.Duration) ports.WebhookSender {
+	return &HTTPWebhookSender{client: &http.Client{Timeout: timeout}}
+}
+
+func (s *HTTPWebhookSender) Send(url string, headers map[string]string, body []byte) (int, error) {
+	req, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return 0, err
+	}
+	for name, value := range headers {
+		req.Header.Set(name, value)
+	}
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, resp.Body) // Permite reutilizar la conexión
+	return resp.StatusCode, nil
+}
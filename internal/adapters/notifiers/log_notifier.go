@@ -0,0 +1,22 @@
+package notifiers
+
+import (
+	"log"
+
+	"recommender/internal/core/domain"
+	"recommender/internal/core/ports"
+)
+
+// LogNotifier escribe la alerta en el log del servidor.
+type LogNotifier struct{}
+
+func NewLogNotifier() ports.Notifier {
+	return LogNotifier{}
+}
+
+func (LogNotifier) Name() string { return domain.NotifierLog }
+
+func (LogNotifier) Notify(alert domain.Alert) error {
+	log.Printf("🔔 Alerta para %s: %s", alert.Rule.UserID, alert.Event.Message)
+	return nil
+}
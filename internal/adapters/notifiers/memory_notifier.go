@@ -0,0 +1,39 @@
+package notifiers
+
+import (
+	"log"
+	"sync"
+
+	"recommender/internal/core/domain"
+)
+
+// MemoryNotifier es el reemplazo local de los canales externos: guarda las alertas
+// en memoria y las escribe en el log en lugar de enviarlas. Se registra con el nombre
+// del canal que reemplaza ("webhook", "smtp").
+type MemoryNotifier struct {
+	name string
+
+	mu   sync.Mutex
+	sent []domain.Alert
+}
+
+func NewMemoryNotifier(name string) *MemoryNotifier {
+	return &MemoryNotifier{name: name}
+}
+
+func (n *MemoryNotifier) Name() string { return n.name }
+
+func (n *MemoryNotifier) Notify(alert domain.Alert) error {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	n.sent = append(n.sent, alert)
+	log.Printf("🧪 [%s local] %s -> %s", n.name, alert.Event.Message, alert.Rule.Target)
+	return nil
+}
+
+// Sent devuelve una copia de las alertas recibidas.
+func (n *MemoryNotifier) Sent() []domain.Alert {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	return append([]domain.Alert(nil), n.sent...)
+}
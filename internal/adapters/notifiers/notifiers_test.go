@@ -0,0 +1,85 @@
+package notifiers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/smtp"
+	"strings"
+	"testing"
+	"time"
+
+	"recommender/internal/core/domain"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func sampleAlert(target string) domain.Alert {
+	return domain.Alert{
+		Rule:  domain.AlertRule{ID: 1, UserID: "alice", Name: "downgrades", Target: target},
+		Stock: domain.Stock{Ticker: "AAPL", Action: "downgraded by"},
+		Event: domain.AlertEvent{Message: "[downgrades] AAPL downgraded by"},
+	}
+}
+
+func TestWebhookNotifier(t *testing.T) {
+	var received domain.Alert
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "application/json", r.Header.Get("Content-Type"))
+		json.NewDecoder(r.Body).Decode(&received)
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	notifier := NewWebhookNotifier(time.Second)
+	assert.Nil(t, notifier.Notify(sampleAlert(server.URL)))
+	assert.Equal(t, "AAPL", received.Stock.Ticker)
+}
+
+func TestWebhookNotifier_ErrorStatus(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer server.Close()
+
+	err := NewWebhookNotifier(time.Second).Notify(sampleAlert(server.URL))
+	assert.NotNil(t, err)
+}
+
+func TestSMTPNotifier(t *testing.T) {
+	var gotAddr string
+	var gotTo []string
+	var gotMsg string
+	notifier := &SMTPNotifier{
+		config: SMTPConfig{Host: "mail.local", Port: "25", From: "alerts@example.com"},
+		sendMail: func(addr string, a smtp.Auth, from string, to []string, msg []byte) error {
+			gotAddr, gotTo, gotMsg = addr, to, string(msg)
+			return nil
+		},
+	}
+
+	assert.Nil(t, notifier.Notify(sampleAlert("alice@example.com")))
+	assert.Equal(t, "mail.local:25", gotAddr)
+	assert.Equal(t, []string{"alice@example.com"}, gotTo)
+	assert.True(t, strings.Contains(gotMsg, "Subject: Alert: AAPL downgraded by"))
+}
+
+func TestMemoryNotifier(t *testing.T) {
+	notifier := NewMemoryNotifier(domain.NotifierWebhook)
+
+	assert.Nil(t, notifier.Notify(sampleAlert("http://example.invalid")))
+	assert.Equal(t, domain.NotifierWebhook, notifier.Name())
+	assert.Len(t, notifier.Sent(), 1)
+}
+
+func TestHTTPWebhookSender(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "sha256=abc", r.Header.Get("X-Webhook-Signature"))
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer server.Close()
+
+	status, err := NewHTTPWebhookSender(time.Second).Send(server.URL, map[string]string{"X-Webhook-Signature": "sha256=abc"}, []byte(`{}`))
+	assert.Nil(t, err)
+	assert.Equal(t, http.StatusAccepted, status)
+}
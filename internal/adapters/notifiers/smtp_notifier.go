@@ -0,0 +1,53 @@
+package notifiers
+
+import (
+	"fmt"
+	"net"
+	"net/smtp"
+	"strings"
+
+	"recommender/internal/core/domain"
+	"recommender/internal/core/ports"
+)
+
+// SMTPConfig son los datos del servidor de correo; sin Username no se autentica.
+type SMTPConfig struct {
+	Host     string
+	Port     string
+	Username string
+	Password string
+	From     string
+}
+
+// SMTPNotifier envía la alerta por correo a la dirección de la regla (Target).
+type SMTPNotifier struct {
+	config   SMTPConfig
+	sendMail func(addr string, a smtp.Auth, from string, to []string, msg []byte) error
+}
+
+func NewSMTPNotifier(config SMTPConfig) ports.Notifier {
+	return &SMTPNotifier{config: config, sendMail: smtp.SendMail}
+}
+
+func (n *SMTPNotifier) Name() string { return domain.NotifierSMTP }
+
+func (n *SMTPNotifier) Notify(alert domain.Alert) error {
+	var auth smtp.Auth
+	if n.config.Username != "" {
+		auth = smtp.PlainAuth("", n.config.Username, n.config.Password, n.config.Host)
+	}
+	addr := net.JoinHostPort(n.config.Host, n.config.Port)
+	return n.sendMail(addr, auth, n.config.From, []string{alert.Rule.Target}, buildAlertMail(n.config.From, alert))
+}
+
+func buildAlertMail(from string, alert domain.Alert) []byte {
+	subject := fmt.Sprintf("Alert: %s %s", alert.Stock.Ticker, alert.Stock.Action)
+	headers := []string{
+		"From: " + from,
+		"To: " + alert.Rule.Target,
+		"Subject: " + subject,
+		"MIME-Version: 1.0",
+		"Content-Type: text/plain; charset=UTF-8",
+	}
+	return []byte(strings.Join(headers, "\r\n") + "\r\n\r\n" + alert.Event.Message + "\r\n")
+}
@@ -0,0 +1,39 @@
+package notifiers
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"recommender/internal/core/domain"
+	"recommender/internal/core/ports"
+)
+
+// WebhookNotifier envía la alerta como JSON por POST a la URL de la regla (Target).
+type WebhookNotifier struct {
+	client *http.Client
+}
+
+func NewWebhookNotifier(timeout time.Duration) ports.Notifier {
+	return &WebhookNotifier{client: &http.Client{Timeout: timeout}}
+}
+
+func (n *WebhookNotifier) Name() string { return domain.NotifierWebhook }
+
+func (n *WebhookNotifier) Notify(alert domain.Alert) error {
+	body, err := json.Marshal(alert)
+	if err != nil {
+		return err
+	}
+	resp, err := n.client.Post(alert.Rule.Target, "application/json", bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("webhook respondió %d", resp.StatusCode)
+	}
+	return nil
+}
@@ -0,0 +1,38 @@
+package prices
+
+import (
+	"sort"
+	"time"
+
+	"recommender/internal/core/domain"
+	"recommender/internal/core/ports"
+)
+
+// MemoryPriceFeed sirve cierres cargados en memoria, por ejemplo desde un archivo
+// CSV local, sin pasar por la base de datos.
+type MemoryPriceFeed struct {
+	bars map[string][]domain.PriceBar
+}
+
+func NewMemoryPriceFeed(bars []domain.PriceBar) ports.PriceFeed {
+	byTicker := map[string][]domain.PriceBar{}
+	for _, bar := range bars {
+		byTicker[bar.Ticker] = append(byTicker[bar.Ticker], bar)
+	}
+	for ticker := range byTicker {
+		series := byTicker[ticker]
+		sort.Slice(series, func(i, j int) bool { return series[i].Date.Before(series[j].Date) })
+	}
+	return &MemoryPriceFeed{bars: byTicker}
+}
+
+func (f *MemoryPriceFeed) GetCloses(ticker string, from, to time.Time) ([]domain.PriceBar, error) {
+	series := f.bars[ticker]
+	start := sort.Search(len(series), func(i int) bool { return !series[i].Date.Before(from) })
+
+	var result []domain.PriceBar
+	for i := start; i < len(series) && !series[i].Date.After(to); i++ {
+		result = append(result, series[i])
+	}
+	return result, nil
+}
@@ -0,0 +1,30 @@
+package prices
+
+import (
+	"testing"
+	"time"
+
+	"recommender/internal/core/domain"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMemoryPriceFeed_GetCloses(t *testing.T) {
+	day := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	feed := NewMemoryPriceFeed([]domain.PriceBar{
+		{Ticker: "AAPL", Date: day.AddDate(0, 0, 2), Close: 102},
+		{Ticker: "AAPL", Date: day, Close: 100},
+		{Ticker: "AAPL", Date: day.AddDate(0, 0, 1), Close: 101},
+		{Ticker: "MSFT", Date: day, Close: 300},
+	})
+
+	bars, err := feed.GetCloses("AAPL", day.AddDate(0, 0, 1), day.AddDate(0, 0, 5))
+	assert.NoError(t, err)
+	assert.Len(t, bars, 2)
+	assert.Equal(t, 101.0, bars[0].Close)
+	assert.Equal(t, 102.0, bars[1].Close)
+
+	missing, err := feed.GetCloses("TSLA", day, day.AddDate(0, 0, 5))
+	assert.NoError(t, err)
+	assert.Empty(t, missing)
+}
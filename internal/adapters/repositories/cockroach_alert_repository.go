@@ -0,0 +1,63 @@
+package repository
+
+import (
+	"recommender/internal/core/domain"
+	port "recommender/internal/core/ports"
+
+	"gorm.io/gorm"
+)
+
+type CockroachAlertRepository struct {
+	db *gorm.DB
+}
+
+func NewCockroachAlertRepository(db *gorm.DB) port.AlertRepository {
+	return &CockroachAlertRepository{db: db}
+}
+
+func (r *CockroachAlertRepository) CreateRule(rule *domain.AlertRule) error {
+	return r.db.Create(rule).Error
+}
+
+// DeleteRule devuelve gorm.ErrRecordNotFound si la regla no existe o es de otro usuario.
+func (r *CockroachAlertRepository) DeleteRule(userID string, id uint) error {
+	result := r.db.Where("user_id = ?", userID).Delete(&domain.AlertRule{}, id)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+func (r *CockroachAlertRepository) GetRule(userID string, id uint) (*domain.AlertRule, error) {
+	var rule domain.AlertRule
+	result := r.db.Where("user_id = ?", userID).First(&rule, id)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return &rule, nil
+}
+
+func (r *CockroachAlertRepository) ListRules(userID string) ([]domain.AlertRule, error) {
+	var rules []domain.AlertRule
+	result := r.db.Where("user_id = ?", userID).Order("id ASC").Find(&rules)
+	return rules, result.Error
+}
+
+func (r *CockroachAlertRepository) ListActiveRules() ([]domain.AlertRule, error) {
+	var rules []domain.AlertRule
+	result := r.db.Where("active = ?", true).Order("id ASC").Find(&rules)
+	return rules, result.Error
+}
+
+func (r *CockroachAlertRepository) SaveEvent(event *domain.AlertEvent) error {
+	return r.db.Save(event).Error
+}
+
+func (r *CockroachAlertRepository) ListEvents(userID string, limit, offset int) ([]domain.AlertEvent, error) {
+	var events []domain.AlertEvent
+	result := r.db.Where("user_id = ?", userID).Order("created_at DESC, id DESC").Limit(limit).Offset(offset).Find(&events)
+	return events, result.Error
+}
@@ -0,0 +1,54 @@
+package repository
+
+import (
+	"errors"
+	"testing"
+
+	"recommender/internal/core/domain"
+
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+)
+
+func setupAlertRepository(t *testing.T) *CockroachAlertRepository {
+	db := setupTestDB(t)
+	if err := db.AutoMigrate(&domain.AlertRule{}, &domain.AlertEvent{}); err != nil {
+		t.Fatalf("failed to migrate: %v", err)
+	}
+	return NewCockroachAlertRepository(db).(*CockroachAlertRepository)
+}
+
+func TestAlertRules(t *testing.T) {
+	repo := setupAlertRepository(t)
+
+	rule := &domain.AlertRule{UserID: "alice", Name: "downgrades", ActionTypes: []domain.ActionType{domain.ActionDowngrade}, Notifier: domain.NotifierLog, Active: true}
+	assert.Nil(t, repo.CreateRule(rule))
+	assert.Nil(t, repo.CreateRule(&domain.AlertRule{UserID: "bob", Name: "paused", Brokerage: "Goldman", Notifier: domain.NotifierLog}))
+
+	active, err := repo.ListActiveRules()
+	assert.Nil(t, err)
+	assert.Len(t, active, 1)
+	assert.Equal(t, []domain.ActionType{domain.ActionDowngrade}, active[0].ActionTypes)
+
+	_, err = repo.GetRule("bob", rule.ID)
+	assert.True(t, errors.Is(err, gorm.ErrRecordNotFound))
+
+	assert.True(t, errors.Is(repo.DeleteRule("bob", rule.ID), gorm.ErrRecordNotFound))
+	assert.Nil(t, repo.DeleteRule("alice", rule.ID))
+
+	rules, err := repo.ListRules("alice")
+	assert.Nil(t, err)
+	assert.Empty(t, rules)
+}
+
+func TestAlertEvents(t *testing.T) {
+	repo := setupAlertRepository(t)
+
+	assert.Nil(t, repo.SaveEvent(&domain.AlertEvent{RuleID: 1, UserID: "alice", Ticker: "AAPL", Delivered: true}))
+	assert.Nil(t, repo.SaveEvent(&domain.AlertEvent{RuleID: 2, UserID: "bob", Ticker: "MSFT"}))
+
+	events, err := repo.ListEvents("alice", 10, 0)
+	assert.Nil(t, err)
+	assert.Len(t, events, 1)
+	assert.Equal(t, "AAPL", events[0].Ticker)
+}
@@ -0,0 +1,47 @@
+package repository
+
+import (
+	"strings"
+
+	"recommender/internal/core/domain"
+	port "recommender/internal/core/ports"
+
+	"gorm.io/gorm"
+)
+
+type CockroachCompanyRepository struct {
+	db *gorm.DB
+}
+
+func NewCockroachCompanyRepository(db *gorm.DB) port.CompanyRepository {
+	return &CockroachCompanyRepository{db: db}
+}
+
+// Save inserta la compañía o actualiza todas sus columnas si el símbolo ya existe.
+func (r *CockroachCompanyRepository) Save(company *domain.Company) error {
+	return r.db.Save(company).Error
+}
+
+func (r *CockroachCompanyRepository) GetBySymbol(symbol string) (*domain.Company, error) {
+	var company domain.Company
+	result := r.db.Where("symbol = ?", symbol).First(&company)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return &company, nil
+}
+
+// likeEscaper escapa los comodines de LIKE para buscar el texto tal cual.
+var likeEscaper = strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`)
+
+// SearchByNamePrefix busca sin distinguir mayúsculas; un prefijo vacío lista todas.
+// Los % y _ del prefijo se buscan como caracteres, no como comodines.
+func (r *CockroachCompanyRepository) SearchByNamePrefix(prefix string, limit, offset int) ([]domain.Company, error) {
+	var companies []domain.Company
+	query := r.db.Order("name ASC").Limit(limit).Offset(offset)
+	if prefix != "" {
+		query = query.Where(`LOWER(name) LIKE ? ESCAPE '\'`, likeEscaper.Replace(strings.ToLower(prefix))+"%")
+	}
+	result := query.Find(&companies)
+	return companies, result.Error
+}
@@ -0,0 +1,79 @@
+package repository
+
+import (
+	"errors"
+	"testing"
+
+	"recommender/internal/core/domain"
+
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+)
+
+func setupCompanyRepository(t *testing.T) *CockroachCompanyRepository {
+	db := setupTestDB(t)
+	if err := db.AutoMigrate(&domain.Company{}); err != nil {
+		t.Fatalf("failed to migrate: %v", err)
+	}
+	return NewCockroachCompanyRepository(db).(*CockroachCompanyRepository)
+}
+
+func TestCompanySaveAndGetBySymbol(t *testing.T) {
+	repo := setupCompanyRepository(t)
+
+	err := repo.Save(&domain.Company{Symbol: "AAPL", Name: "Apple Inc.", Active: true})
+	assert.Nil(t, err)
+
+	err = repo.Save(&domain.Company{Symbol: "AAPL", Name: "Apple Inc.", Sector: "Technology", Active: true})
+	assert.Nil(t, err)
+
+	company, err := repo.GetBySymbol("AAPL")
+	assert.Nil(t, err)
+	assert.Equal(t, "Technology", company.Sector)
+}
+
+func TestCompanyGetBySymbol_NotFound(t *testing.T) {
+	repo := setupCompanyRepository(t)
+
+	company, err := repo.GetBySymbol("NOPE")
+	assert.Nil(t, company)
+	assert.True(t, errors.Is(err, gorm.ErrRecordNotFound))
+}
+
+func TestCompanySearchByNamePrefix(t *testing.T) {
+	repo := setupCompanyRepository(t)
+
+	assert.Nil(t, repo.Save(&domain.Company{Symbol: "AAPL", Name: "Apple Inc.", Active: true}))
+	assert.Nil(t, repo.Save(&domain.Company{Symbol: "APLD", Name: "Applied Digital", Active: true}))
+	assert.Nil(t, repo.Save(&domain.Company{Symbol: "MSFT", Name: "Microsoft", Active: true}))
+
+	companies, err := repo.SearchByNamePrefix("app", 10, 0)
+	assert.Nil(t, err)
+	assert.Len(t, companies, 2)
+	assert.Equal(t, "AAPL", companies[0].Symbol)
+
+	all, err := repo.SearchByNamePrefix("", 10, 0)
+	assert.Nil(t, err)
+	assert.Len(t, all, 3)
+}
+
+func TestCompanySearchByNamePrefix_EscapesWildcards(t *testing.T) {
+	repo := setupCompanyRepository(t)
+
+	assert.Nil(t, repo.Save(&domain.Company{Symbol: "PCT", Name: "100% Pure Co", Active: true}))
+	assert.Nil(t, repo.Save(&domain.Company{Symbol: "ZRO", Name: "1000 Holdings", Active: true}))
+	assert.Nil(t, repo.Save(&domain.Company{Symbol: "USC", Name: "A_B Corp", Active: true}))
+	assert.Nil(t, repo.Save(&domain.Company{Symbol: "AXB", Name: "AXB Corp", Active: true}))
+	assert.Nil(t, repo.Save(&domain.Company{Symbol: "BSL", Name: `C\D Inc`, Active: true}))
+
+	cases := map[string][]string{"100%": {"PCT"}, "a_b": {"USC"}, "%": nil, `c\`: {"BSL"}}
+	for prefix, want := range cases {
+		companies, err := repo.SearchByNamePrefix(prefix, 10, 0)
+		assert.Nil(t, err)
+		var symbols []string
+		for _, company := range companies {
+			symbols = append(symbols, company.Symbol)
+		}
+		assert.Equal(t, want, symbols, "prefijo %q", prefix)
+	}
+}
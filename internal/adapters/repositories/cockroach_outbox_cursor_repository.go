@@ -0,0 +1,44 @@
+package repository
+
+import (
+	"errors"
+
+	"recommender/internal/core/domain"
+	port "recommender/internal/core/ports"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type CockroachOutboxCursorRepository struct {
+	db *gorm.DB
+}
+
+func NewCockroachOutboxCursorRepository(db *gorm.DB) port.OutboxCursorStore {
+	return &CockroachOutboxCursorRepository{db: db}
+}
+
+func (r *CockroachOutboxCursorRepository) Cursor(consumer string) (uint, bool, error) {
+	var cursor domain.OutboxCursor
+	err := r.db.Where("consumer = ?", consumer).First(&cursor).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return 0, false, nil
+	}
+	if err != nil {
+		return 0, false, err
+	}
+	return cursor.LastEventID, true, nil
+}
+
+func (r *CockroachOutboxCursorRepository) InitCursor(consumer string, id uint) error {
+	cursor := domain.OutboxCursor{Consumer: consumer, LastEventID: id}
+	return r.db.Clauses(clause.OnConflict{DoNothing: true}).Create(&cursor).Error
+}
+
+// AdvanceCursor es un compare-and-swap: el UPDATE solo afecta la fila si nadie la movió.
+func (r *CockroachOutboxCursorRepository) AdvanceCursor(consumer string, from, to uint) (bool, error) {
+	result := r.db.Model(&domain.OutboxCursor{}).
+		Where("consumer = ? AND last_event_id = ?", consumer, from).
+		Update("last_event_id", to)
+	return result.RowsAffected == 1, result.Error
+}
@@ -0,0 +1,37 @@
+package repository
+
+import (
+	"testing"
+
+	"recommender/internal/core/domain"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOutboxCursor_InitAndAdvance(t *testing.T) {
+	db := setupTestDB(t)
+	assert.Nil(t, db.AutoMigrate(&domain.OutboxCursor{}))
+	repo := NewCockroachOutboxCursorRepository(db)
+
+	_, ok, err := repo.Cursor("alerts")
+	assert.Nil(t, err)
+	assert.False(t, ok)
+
+	assert.Nil(t, repo.InitCursor("alerts", 7))
+	assert.Nil(t, repo.InitCursor("alerts", 1)) // Ya existe: no retrocede
+	id, ok, err := repo.Cursor("alerts")
+	assert.Nil(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, uint(7), id)
+
+	advanced, err := repo.AdvanceCursor("alerts", 7, 12)
+	assert.Nil(t, err)
+	assert.True(t, advanced)
+
+	// Otro proceso que leyó el cursor viejo no puede reclamar el mismo lote
+	advanced, err = repo.AdvanceCursor("alerts", 7, 12)
+	assert.Nil(t, err)
+	assert.False(t, advanced)
+	id, _, _ = repo.Cursor("alerts")
+	assert.Equal(t, uint(12), id)
+}
@@ -0,0 +1,29 @@
+package repository
+
+import (
+	"recommender/internal/core/domain"
+	port "recommender/internal/core/ports"
+
+	"gorm.io/gorm"
+)
+
+type CockroachOutboxRepository struct {
+	db *gorm.DB
+}
+
+func NewCockroachOutboxRepository(db *gorm.DB) port.OutboxReader {
+	return &CockroachOutboxRepository{db: db}
+}
+
+func (r *CockroachOutboxRepository) EventsAfter(afterID uint, limit int) ([]domain.OutboxEvent, error) {
+	var events []domain.OutboxEvent
+	result := r.db.Where("id > ?", afterID).Order("id ASC").Limit(limit).Find(&events)
+	return events, result.Error
+}
+
+// LatestEventID devuelve 0 si el outbox está vacío.
+func (r *CockroachOutboxRepository) LatestEventID() (uint, error) {
+	var id uint
+	result := r.db.Model(&domain.OutboxEvent{}).Select("COALESCE(MAX(id), 0)").Scan(&id)
+	return id, result.Error
+}
@@ -0,0 +1,34 @@
+package repository
+
+import (
+	"testing"
+	"time"
+
+	"recommender/internal/core/domain"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOutboxEventsAfter(t *testing.T) {
+	db := setupTestDB(t)
+	stocks := NewCockroachStockRepository(db)
+	outbox := NewCockroachOutboxRepository(db)
+
+	latest, err := outbox.LatestEventID()
+	assert.Nil(t, err)
+	assert.Equal(t, uint(0), latest)
+
+	for _, ticker := range []string{"AAPL", "MSFT", "TSLA"} {
+		assert.Nil(t, stocks.Create(&domain.Stock{Ticker: ticker, Time: time.Now()}))
+	}
+
+	latest, err = outbox.LatestEventID()
+	assert.Nil(t, err)
+	assert.Equal(t, uint(3), latest)
+
+	events, err := outbox.EventsAfter(1, 10)
+	assert.Nil(t, err)
+	assert.Len(t, events, 2)
+	stock, _ := events[0].Stock()
+	assert.Equal(t, "MSFT", stock.Ticker)
+}
@@ -0,0 +1,38 @@
+package repository
+
+import (
+	"recommender/internal/core/domain"
+	port "recommender/internal/core/ports"
+
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type CockroachPriceRepository struct {
+	db *gorm.DB
+}
+
+func NewCockroachPriceRepository(db *gorm.DB) port.PriceRepository {
+	return &CockroachPriceRepository{db: db}
+}
+
+// SaveBars inserta en lotes; un cierre ya existente para (ticker, fecha) se actualiza.
+func (r *CockroachPriceRepository) SaveBars(bars []domain.PriceBar) error {
+	if len(bars) == 0 {
+		return nil
+	}
+	return r.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "ticker"}, {Name: "date"}},
+		DoUpdates: clause.AssignmentColumns([]string{"close"}),
+	}).CreateInBatches(bars, 500).Error
+}
+
+func (r *CockroachPriceRepository) GetCloses(ticker string, from, to time.Time) ([]domain.PriceBar, error) {
+	var bars []domain.PriceBar
+	result := r.db.Where("ticker = ? AND date >= ? AND date <= ?", ticker, from, to).
+		Order("date ASC").
+		Find(&bars)
+	return bars, result.Error
+}
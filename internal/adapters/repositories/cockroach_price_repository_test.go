@@ -0,0 +1,30 @@
+package repository
+
+import (
+	"testing"
+	"time"
+
+	"recommender/internal/core/domain"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPriceSaveBarsAndGetCloses(t *testing.T) {
+	db := setupTestDB(t)
+	assert.Nil(t, db.AutoMigrate(&domain.PriceBar{}))
+	repo := NewCockroachPriceRepository(db)
+
+	day := time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC)
+	assert.Nil(t, repo.SaveBars([]domain.PriceBar{
+		{Ticker: "AAPL", Date: day, Close: 185},
+		{Ticker: "AAPL", Date: day.AddDate(0, 0, 1), Close: 184},
+		{Ticker: "MSFT", Date: day, Close: 370},
+	}))
+	// Reimportar el mismo día actualiza el cierre
+	assert.Nil(t, repo.SaveBars([]domain.PriceBar{{Ticker: "AAPL", Date: day, Close: 186}}))
+
+	bars, err := repo.GetCloses("AAPL", day, day.AddDate(0, 0, 5))
+	assert.Nil(t, err)
+	assert.Len(t, bars, 2)
+	assert.Equal(t, 186.0, bars[0].Close)
+}
@@ -0,0 +1,51 @@
+package repository
+
+import (
+	"time"
+
+	"recommender/internal/core/domain"
+	port "recommender/internal/core/ports"
+
+	"gorm.io/gorm"
+)
+
+type CockroachSnapshotRepository struct {
+	db *gorm.DB
+}
+
+func NewCockroachSnapshotRepository(db *gorm.DB) port.SnapshotRepository {
+	return &CockroachSnapshotRepository{db: db}
+}
+
+func (r *CockroachSnapshotRepository) Save(snapshot *domain.RecommendationSnapshot) error {
+	return r.db.Create(snapshot).Error
+}
+
+func (r *CockroachSnapshotRepository) List(strategy string, limit, offset int) ([]domain.RecommendationSnapshot, error) {
+	var snapshots []domain.RecommendationSnapshot
+	query := r.db.Order("generated_at DESC, id DESC").Limit(limit).Offset(offset)
+	if strategy != "" {
+		query = query.Where("strategy = ?", strategy)
+	}
+	result := query.Find(&snapshots)
+	return snapshots, result.Error
+}
+
+func (r *CockroachSnapshotRepository) GetByID(id uint) (*domain.RecommendationSnapshot, error) {
+	var snapshot domain.RecommendationSnapshot
+	result := r.db.First(&snapshot, id)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return &snapshot, nil
+}
+
+func (r *CockroachSnapshotRepository) GetLatestAt(paramsKey string, at time.Time) (*domain.RecommendationSnapshot, error) {
+	var snapshot domain.RecommendationSnapshot
+	result := r.db.Where("params_key = ? AND generated_at <= ?", paramsKey, at).
+		Order("generated_at DESC, id DESC").First(&snapshot)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return &snapshot, nil
+}
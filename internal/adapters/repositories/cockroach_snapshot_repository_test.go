@@ -0,0 +1,60 @@
+package repository
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"recommender/internal/core/domain"
+
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+)
+
+func setupSnapshotRepository(t *testing.T) *CockroachSnapshotRepository {
+	db := setupTestDB(t)
+	if err := db.AutoMigrate(&domain.RecommendationSnapshot{}); err != nil {
+		t.Fatalf("failed to migrate: %v", err)
+	}
+	return NewCockroachSnapshotRepository(db).(*CockroachSnapshotRepository)
+}
+
+func TestSnapshotSaveListAndGet(t *testing.T) {
+	repo := setupSnapshotRepository(t)
+	base := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	params := domain.RecommendationParams{Limit: 2, LookbackDays: 30}
+	first := &domain.RecommendationSnapshot{
+		Strategy:    "default",
+		GeneratedAt: base,
+		Params:      params,
+		ParamsKey:   domain.SnapshotParamsKey(params, "default"),
+		Items:       []domain.SnapshotItem{{Rank: 1, Ticker: "AAPL", Score: 4.5}},
+	}
+	assert.Nil(t, repo.Save(first))
+	assert.Nil(t, repo.Save(&domain.RecommendationSnapshot{Strategy: "upside", GeneratedAt: base.Add(time.Hour), ParamsKey: domain.SnapshotParamsKey(params, "upside")}))
+	// Mismo momento y estrategia, otros parámetros: no debe confundirse con first
+	assert.Nil(t, repo.Save(&domain.RecommendationSnapshot{Strategy: "default", GeneratedAt: base.Add(time.Hour), ParamsKey: domain.SnapshotParamsKey(domain.RecommendationParams{Limit: 1}, "default")}))
+	assert.Nil(t, repo.Save(&domain.RecommendationSnapshot{Strategy: "default", GeneratedAt: base.Add(2 * time.Hour), ParamsKey: first.ParamsKey}))
+
+	snapshots, err := repo.List("", 10, 0)
+	assert.Nil(t, err)
+	assert.Len(t, snapshots, 4)
+	assert.True(t, snapshots[0].GeneratedAt.After(snapshots[1].GeneratedAt))
+
+	snapshots, err = repo.List("default", 10, 0)
+	assert.Nil(t, err)
+	assert.Len(t, snapshots, 3)
+
+	stored, err := repo.GetByID(first.ID)
+	assert.Nil(t, err)
+	assert.Equal(t, 2, stored.Params.Limit)
+	assert.Equal(t, "AAPL", stored.Items[0].Ticker)
+
+	latest, err := repo.GetLatestAt(first.ParamsKey, base.Add(90*time.Minute))
+	assert.Nil(t, err)
+	assert.Equal(t, first.ID, latest.ID)
+
+	_, err = repo.GetLatestAt(first.ParamsKey, base.Add(-time.Hour))
+	assert.True(t, errors.Is(err, gorm.ErrRecordNotFound))
+}
@@ -17,19 +17,65 @@ func NewCockroachStockRepository(db *gorm.DB) port.StockRepository {
 	return &CockroachStockRepository{db: db}
 }
 
+// stockColumns lee el nombre de la compañía de la tabla companies, que es la que se
+// mantiene al día; stocks.company solo queda como respaldo para tickers sin compañía.
+const stockColumns = `stocks.id, stocks.ticker, COALESCE(NULLIF(companies.name, ''), stocks.company) AS company,
+	stocks.brokerage, stocks.action, stocks.action_type, stocks.rating_from, stocks.rating_to,
+	stocks.rating_from_score, stocks.rating_to_score, stocks.target_from, stocks.target_to,
+	stocks.time, stocks.source, stocks.external_id`
+
+// withCompany arma las lecturas de eventos que se devuelven al resto de la aplicación.
+func (r *CockroachStockRepository) withCompany() *gorm.DB {
+	return r.db.Model(&domain.Stock{}).Select(stockColumns).Joins("LEFT JOIN companies ON companies.symbol = stocks.ticker")
+}
+
 func (r *CockroachStockRepository) GetAll(limit, offset int) ([]domain.Stock, error) {
 	var stocks []domain.Stock
-	result := r.db.Limit(limit).Offset(offset).Find(&stocks) // ✅ Aplica paginación
+	result := r.withCompany().Limit(limit).Offset(offset).Find(&stocks) // ✅ Aplica paginación
+	return stocks, result.Error
+}
+
+func (r *CockroachStockRepository) GetFiltered(filter domain.StockFilter, limit, offset int) ([]domain.Stock, error) {
+	var stocks []domain.Stock
+	query := r.withCompany().Limit(limit).Offset(offset)
+	if len(filter.ActionTypes) > 0 {
+		query = query.Where("stocks.action_type IN ?", filter.ActionTypes)
+	}
+	if len(filter.Tickers) > 0 {
+		query = query.Where("stocks.ticker IN ?", filter.Tickers)
+	}
+	result := query.Find(&stocks)
 	return stocks, result.Error
 }
 
+// Create guarda el stock y su evento de outbox en la misma transacción.
 func (r *CockroachStockRepository) Create(stock *domain.Stock) error {
-	return r.db.Create(stock).Error
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(stock).Error; err != nil {
+			return err
+		}
+		event, err := domain.NewStockCreatedEvent(*stock)
+		if err != nil {
+			return err
+		}
+		return tx.Create(&event).Error
+	})
 }
 
+// GetStockByTickerAndTime prefiere las filas sin external_id (anteriores a que se
+// guardara), que son las únicas que se deduplican por ticker y fecha.
 func (r *CockroachStockRepository) GetStockByTickerAndTime(ticker string, t time.Time) (*domain.Stock, error) {
 	var stock domain.Stock
-	result := r.db.Where("ticker = ? AND time = ?", ticker, t).First(&stock)
+	result := r.db.Where("ticker = ? AND time = ?", ticker, t).Order("external_id").First(&stock)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return &stock, nil
+}
+
+func (r *CockroachStockRepository) GetStockByExternalID(source, externalID string) (*domain.Stock, error) {
+	var stock domain.Stock
+	result := r.db.Where("source = ? AND external_id = ?", source, externalID).First(&stock)
 	if result.Error != nil {
 		return nil, result.Error
 	}
@@ -38,27 +84,115 @@ func (r *CockroachStockRepository) GetStockByTickerAndTime(ticker string, t time
 
 func (r *CockroachStockRepository) GetTopStocksByTarget(limit int) ([]domain.Stock, error) {
 	var stocks []domain.Stock
-	result := r.db.Order("target_to DESC").Limit(limit).Find(&stocks)
+	result := r.withCompany().Order("stocks.target_to DESC").Limit(limit).Find(&stocks)
 	return stocks, result.Error
 }
 
 func (r *CockroachStockRepository) GetStockByTicker(ticker string) (*domain.Stock, error) {
 	var stock domain.Stock
-	result := r.db.Where("ticker = ?", ticker).First(&stock)
+	result := r.withCompany().Where("stocks.ticker = ?", ticker).First(&stock)
 	if result.Error != nil {
 		return nil, result.Error
 	}
 	return &stock, nil
 }
 
-func (r *CockroachStockRepository) GetRecentStocks(limit int) ([]domain.Stock, error) {
+func (r *CockroachStockRepository) GetRecentStocks(since time.Time, limit int) ([]domain.Stock, error) {
 	var stocks []domain.Stock
-	err := r.db.Where("time >= NOW() - INTERVAL '30 days'").
-		Order("target_to DESC, time DESC").
-		Limit(limit).
-		Find(&stocks).Error
+	query := r.withCompany().Where("stocks.time >= ?", since).Order("stocks.time DESC")
+	if limit > 0 {
+		query = query.Limit(limit)
+	}
+	err := query.Find(&stocks).Error
 	if err != nil {
 		return nil, err
 	}
 	return stocks, nil
 }
+
+func (r *CockroachStockRepository) CountUnknownRatingLabels() (map[string]int, error) {
+	counts := map[string]int{}
+	for _, column := range []string{"rating_from", "rating_to"} {
+		var rows []struct {
+			Label string
+			Count int
+		}
+		err := r.db.Model(&domain.Stock{}).
+			Select(column+" AS label, COUNT(*) AS count").
+			Where(column+"_score = ? AND "+column+" <> ''", domain.RatingUnknown).
+			Group(column).Scan(&rows).Error
+		if err != nil {
+			return nil, err
+		}
+		for _, row := range rows {
+			counts[row.Label] += row.Count
+		}
+	}
+	return counts, nil
+}
+
+func (r *CockroachStockRepository) StreamFiltered(filter domain.StockFilter, limit, offset int, fn func(domain.Stock) error) error {
+	query := r.withCompany().Order("stocks.id")
+	if len(filter.ActionTypes) > 0 {
+		query = query.Where("stocks.action_type IN ?", filter.ActionTypes)
+	}
+	if len(filter.Tickers) > 0 {
+		query = query.Where("stocks.ticker IN ?", filter.Tickers)
+	}
+	return r.stream(query, limit, offset, fn)
+}
+
+func (r *CockroachStockRepository) StreamHistory(ticker string, limit, offset int, fn func(domain.Stock) error) error {
+	query := r.withCompany().Where("stocks.ticker = ?", ticker).Order("stocks.time").Order("stocks.id")
+	return r.stream(query, limit, offset, fn)
+}
+
+// stream lee el resultado con un cursor y entrega cada fila a fn; un error de fn corta la lectura.
+func (r *CockroachStockRepository) stream(query *gorm.DB, limit, offset int, fn func(domain.Stock) error) error {
+	if limit > 0 {
+		query = query.Limit(limit)
+	}
+	if offset > 0 {
+		query = query.Offset(offset)
+	}
+	rows, err := query.Rows()
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var stock domain.Stock
+		if err := r.db.ScanRows(rows, &stock); err != nil {
+			return err
+		}
+		if err := fn(stock); err != nil {
+			return err
+		}
+	}
+	return rows.Err()
+}
+
+// BackfillActionTypes clasifica los eventos guardados antes de que existiera action_type,
+// para que el filtro por acción también los encuentre. Actualiza por texto de acción
+// distinto, que son pocos, y devuelve cuántas filas cambió.
+func BackfillActionTypes(db *gorm.DB) (int64, error) {
+	var actions []string
+	err := db.Model(&domain.Stock{}).Where("action_type = '' OR action_type IS NULL").
+		Distinct().Pluck("action", &actions).Error
+	if err != nil {
+		return 0, err
+	}
+
+	var updated int64
+	for _, action := range actions {
+		result := db.Model(&domain.Stock{}).
+			Where("(action_type = '' OR action_type IS NULL) AND action = ?", action).
+			Update("action_type", domain.ParseAction(action))
+		if result.Error != nil {
+			return updated, result.Error
+		}
+		updated += result.RowsAffected
+	}
+	return updated, nil
+}
@@ -2,6 +2,7 @@ package repository
 
 import (
 	"errors"
+	"fmt"
 	"testing"
 	"time"
 
@@ -15,12 +16,13 @@ import (
 )
 
 func setupTestDB(t *testing.T) *gorm.DB {
-	// Usamos el driver sqlite puro Go con la DSN para memoria compartida
-	db, err := gorm.Open(sqlite.Open("file::memory:?cache=shared"), &gorm.Config{})
+	// Usamos el driver sqlite puro Go con una base en memoria por test para aislar los datos
+	dsn := fmt.Sprintf("file:%s?mode=memory&cache=shared", t.Name())
+	db, err := gorm.Open(sqlite.Open(dsn), &gorm.Config{})
 	if err != nil {
 		t.Fatalf("failed to connect to in-memory DB: %v", err)
 	}
-	if err := db.AutoMigrate(&domain.Stock{}); err != nil {
+	if err := db.AutoMigrate(&domain.Stock{}, &domain.Company{}, &domain.OutboxEvent{}); err != nil {
 		t.Fatalf("failed to migrate: %v", err)
 	}
 	return db
@@ -53,3 +55,190 @@ func TestGetStockByTicker_NotFound(t *testing.T) {
 	assert.Error(t, err)
 	assert.True(t, errors.Is(err, gorm.ErrRecordNotFound))
 }
+
+func TestGetFiltered_ByActionType(t *testing.T) {
+	db := setupTestDB(t)
+	repo := NewCockroachStockRepository(db)
+
+	assert.Nil(t, repo.Create(&domain.Stock{Ticker: "UPG", ActionType: domain.ActionUpgrade, Time: time.Now()}))
+	assert.Nil(t, repo.Create(&domain.Stock{Ticker: "DWN", ActionType: domain.ActionDowngrade, Time: time.Now()}))
+
+	stocks, err := repo.GetFiltered(domain.StockFilter{ActionTypes: []domain.ActionType{domain.ActionDowngrade}}, 10, 0)
+	assert.Nil(t, err)
+	assert.NotEmpty(t, stocks)
+	for _, s := range stocks {
+		assert.Equal(t, domain.ActionDowngrade, s.ActionType)
+	}
+}
+
+func TestBackfillActionTypes_LegacyRowsMatchFilter(t *testing.T) {
+	db := setupTestDB(t)
+	repo := NewCockroachStockRepository(db)
+
+	// Filas guardadas antes de action_type: la columna quedó vacía
+	assert.Nil(t, db.Create(&domain.Stock{Ticker: "OLD", Action: "downgraded by", Time: time.Now()}).Error)
+	assert.Nil(t, db.Create(&domain.Stock{Ticker: "ODD", Action: "reiterated by", Time: time.Now()}).Error)
+	assert.Nil(t, repo.Create(&domain.Stock{Ticker: "NEW", Action: "upgraded by", ActionType: domain.ActionUpgrade, Time: time.Now()}))
+
+	filter := domain.StockFilter{ActionTypes: []domain.ActionType{domain.ActionDowngrade}}
+	stocks, err := repo.GetFiltered(filter, 10, 0)
+	assert.Nil(t, err)
+	assert.Empty(t, stocks)
+
+	updated, err := BackfillActionTypes(db)
+	assert.Nil(t, err)
+	assert.Equal(t, int64(2), updated)
+
+	stocks, err = repo.GetFiltered(filter, 10, 0)
+	assert.Nil(t, err)
+	if assert.Len(t, stocks, 1) {
+		assert.Equal(t, "OLD", stocks[0].Ticker)
+		assert.Equal(t, domain.ActionDowngrade, stocks[0].ActionType)
+	}
+
+	// Una segunda corrida no encuentra nada pendiente
+	updated, err = BackfillActionTypes(db)
+	assert.Nil(t, err)
+	assert.Equal(t, int64(0), updated)
+}
+
+func TestGetRecentStocks_WindowOrderedByTime(t *testing.T) {
+	db := setupTestDB(t)
+	repo := NewCockroachStockRepository(db)
+	now := time.Now()
+
+	assert.Nil(t, repo.Create(&domain.Stock{Ticker: "RCNT1", TargetTo: 1, Time: now.Add(-time.Hour)}))
+	assert.Nil(t, repo.Create(&domain.Stock{Ticker: "RCNT2", TargetTo: 900, Time: now.Add(-2 * time.Hour)}))
+	assert.Nil(t, repo.Create(&domain.Stock{Ticker: "RCNT3", TargetTo: 500, Time: now.AddDate(0, 0, -90)}))
+
+	stocks, err := repo.GetRecentStocks(now.Add(-3*time.Hour), 0)
+	assert.Nil(t, err)
+	assert.Len(t, stocks, 2)
+	assert.Equal(t, "RCNT1", stocks[0].Ticker) // el más reciente primero, sin importar el target
+
+	limited, err := repo.GetRecentStocks(now.Add(-3*time.Hour), 1)
+	assert.Nil(t, err)
+	assert.Len(t, limited, 1)
+}
+
+func TestCreateWritesOutboxEvent(t *testing.T) {
+	db := setupTestDB(t)
+	repo := NewCockroachStockRepository(db)
+
+	stock := &domain.Stock{Ticker: "AAPL", Time: time.Now(), TargetTo: 180.0}
+	assert.Nil(t, repo.Create(stock))
+
+	var events []domain.OutboxEvent
+	assert.Nil(t, db.Find(&events).Error)
+	assert.Len(t, events, 1)
+	assert.Equal(t, domain.EventStockCreated, events[0].Type)
+	assert.Equal(t, stock.ID, events[0].StockID)
+
+	payload, err := events[0].Stock()
+	assert.Nil(t, err)
+	assert.Equal(t, "AAPL", payload.Ticker)
+}
+
+func TestStreamHistoryAndFiltered(t *testing.T) {
+	db := setupTestDB(t)
+	repo := NewCockroachStockRepository(db).(*CockroachStockRepository)
+	now := time.Now()
+
+	assert.Nil(t, repo.Create(&domain.Stock{Ticker: "AAPL", ActionType: domain.ActionUpgrade, Time: now}))
+	assert.Nil(t, repo.Create(&domain.Stock{Ticker: "AAPL", ActionType: domain.ActionDowngrade, Time: now.Add(-48 * time.Hour)}))
+	assert.Nil(t, repo.Create(&domain.Stock{Ticker: "MSFT", ActionType: domain.ActionUpgrade, Time: now.Add(-time.Hour)}))
+
+	var history []domain.Stock
+	assert.Nil(t, repo.StreamHistory("AAPL", 0, 0, func(s domain.Stock) error {
+		history = append(history, s)
+		return nil
+	}))
+	assert.Len(t, history, 2)
+	assert.Equal(t, domain.ActionDowngrade, history[0].ActionType) // del más antiguo al más reciente
+
+	var upgrades []string
+	assert.Nil(t, repo.StreamFiltered(domain.StockFilter{ActionTypes: []domain.ActionType{domain.ActionUpgrade}}, 0, 0, func(s domain.Stock) error {
+		upgrades = append(upgrades, s.Ticker)
+		return nil
+	}))
+	assert.Equal(t, []string{"AAPL", "MSFT"}, upgrades)
+
+	// Un error del callback corta la lectura
+	stop := errors.New("stop")
+	count := 0
+	err := repo.StreamFiltered(domain.StockFilter{}, 0, 1, func(s domain.Stock) error {
+		count++
+		return stop
+	})
+	assert.ErrorIs(t, err, stop)
+	assert.Equal(t, 1, count)
+}
+
+func TestGetStockByExternalID_UniquePerSource(t *testing.T) {
+	db := setupTestDB(t)
+	repo := NewCockroachStockRepository(db)
+	at := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+
+	assert.Nil(t, repo.Create(&domain.Stock{Ticker: "AAPL", Time: at, Source: "benzinga", ExternalID: "e1"}))
+	assert.Nil(t, repo.Create(&domain.Stock{Ticker: "AAPL", Time: at, Source: "zacks", ExternalID: "e1"}))
+	assert.Error(t, repo.Create(&domain.Stock{Ticker: "MSFT", Time: at, Source: "benzinga", ExternalID: "e1"}))
+
+	// Las filas sin external_id no chocan entre sí
+	assert.Nil(t, repo.Create(&domain.Stock{Ticker: "TSLA", Time: at}))
+	assert.Nil(t, repo.Create(&domain.Stock{Ticker: "TSLA", Time: at}))
+
+	found, err := repo.GetStockByExternalID("zacks", "e1")
+	assert.Nil(t, err)
+	assert.Equal(t, "zacks", found.Source)
+	_, err = repo.GetStockByExternalID("zacks", "e2")
+	assert.True(t, errors.Is(err, gorm.ErrRecordNotFound))
+
+	// Entre filas del mismo ticker y fecha se prefiere la que no tiene external_id
+	assert.Nil(t, repo.Create(&domain.Stock{Ticker: "AAPL", Time: at}))
+	legacy, err := repo.GetStockByTickerAndTime("AAPL", at)
+	assert.Nil(t, err)
+	assert.Empty(t, legacy.ExternalID)
+}
+
+func TestStockReadsUseCompanyName(t *testing.T) {
+	db := setupTestDB(t)
+	repo := NewCockroachStockRepository(db)
+	at := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+
+	assert.Nil(t, repo.Create(&domain.Stock{Ticker: "META", Company: "Facebook", Time: at}))
+	assert.Nil(t, repo.Create(&domain.Stock{Ticker: "NEW", Company: "New Co", Time: at}))
+	assert.Nil(t, db.Create(&domain.Company{Symbol: "META", Name: "Meta Platforms", Active: true}).Error)
+
+	stock, err := repo.GetStockByTicker("META")
+	assert.Nil(t, err)
+	assert.Equal(t, "Meta Platforms", stock.Company)
+
+	recent, err := repo.GetRecentStocks(at.Add(-time.Hour), 0)
+	assert.Nil(t, err)
+	companies := map[string]string{}
+	for _, s := range recent {
+		companies[s.Ticker] = s.Company
+	}
+	assert.Equal(t, map[string]string{"META": "Meta Platforms", "NEW": "New Co"}, companies)
+
+	var streamed []string
+	err = repo.(*CockroachStockRepository).StreamHistory("META", 0, 0, func(s domain.Stock) error {
+		streamed = append(streamed, s.Company)
+		return nil
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"Meta Platforms"}, streamed)
+}
+
+func TestCountUnknownRatingLabels(t *testing.T) {
+	db := setupTestDB(t)
+	repo := NewCockroachStockRepository(db).(*CockroachStockRepository)
+
+	assert.Nil(t, repo.Create(&domain.Stock{Ticker: "A", RatingFrom: "Speculative Hold", RatingTo: "Buy", RatingToScore: domain.RatingBuy}))
+	assert.Nil(t, repo.Create(&domain.Stock{Ticker: "B", RatingFrom: "Hold", RatingFromScore: domain.RatingHold, RatingTo: "Speculative Hold"}))
+	assert.Nil(t, repo.Create(&domain.Stock{Ticker: "C", RatingFrom: "", RatingTo: "Sector Sell"}))
+
+	counts, err := repo.CountUnknownRatingLabels()
+	assert.Nil(t, err)
+	assert.Equal(t, map[string]int{"Speculative Hold": 2, "Sector Sell": 1}, counts)
+}
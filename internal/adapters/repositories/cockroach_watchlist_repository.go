@@ -0,0 +1,60 @@
+package repository
+
+import (
+	"recommender/internal/core/domain"
+	port "recommender/internal/core/ports"
+
+	"gorm.io/gorm"
+)
+
+type CockroachWatchlistRepository struct {
+	db *gorm.DB
+}
+
+func NewCockroachWatchlistRepository(db *gorm.DB) port.WatchlistRepository {
+	return &CockroachWatchlistRepository{db: db}
+}
+
+func (r *CockroachWatchlistRepository) Create(watchlist *domain.Watchlist) error {
+	return r.db.Create(watchlist).Error
+}
+
+func (r *CockroachWatchlistRepository) Update(watchlist *domain.Watchlist) error {
+	return r.db.Save(watchlist).Error
+}
+
+// Delete devuelve gorm.ErrRecordNotFound si la watchlist no existe o es de otro usuario.
+func (r *CockroachWatchlistRepository) Delete(userID string, id uint) error {
+	result := r.db.Where("user_id = ?", userID).Delete(&domain.Watchlist{}, id)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+func (r *CockroachWatchlistRepository) GetByID(userID string, id uint) (*domain.Watchlist, error) {
+	var watchlist domain.Watchlist
+	result := r.db.Where("user_id = ?", userID).First(&watchlist, id)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return &watchlist, nil
+}
+
+func (r *CockroachWatchlistRepository) GetByName(userID, name string) (*domain.Watchlist, error) {
+	var watchlist domain.Watchlist
+	result := r.db.Where("user_id = ? AND name = ?", userID, name).First(&watchlist)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return &watchlist, nil
+}
+
+func (r *CockroachWatchlistRepository) ListByUser(userID string) ([]domain.Watchlist, error) {
+	var watchlists []domain.Watchlist
+	result := r.db.Where("user_id = ?", userID).Order("name ASC").Find(&watchlists)
+	return watchlists, result.Error
+}
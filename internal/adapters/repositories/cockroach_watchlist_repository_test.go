@@ -0,0 +1,56 @@
+package repository
+
+import (
+	"errors"
+	"testing"
+
+	"recommender/internal/core/domain"
+
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+)
+
+func setupWatchlistRepository(t *testing.T) *CockroachWatchlistRepository {
+	db := setupTestDB(t)
+	if err := db.AutoMigrate(&domain.Watchlist{}); err != nil {
+		t.Fatalf("failed to migrate: %v", err)
+	}
+	return NewCockroachWatchlistRepository(db).(*CockroachWatchlistRepository)
+}
+
+func TestWatchlistCRUD(t *testing.T) {
+	repo := setupWatchlistRepository(t)
+
+	watchlist := &domain.Watchlist{UserID: "alice", Name: "chips", Tickers: []string{"NVDA", "AMD"}}
+	assert.Nil(t, repo.Create(watchlist))
+	assert.Nil(t, repo.Create(&domain.Watchlist{UserID: "bob", Name: "chips", Tickers: []string{"INTC"}}))
+
+	stored, err := repo.GetByID("alice", watchlist.ID)
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"NVDA", "AMD"}, stored.Tickers)
+
+	_, err = repo.GetByID("bob", watchlist.ID)
+	assert.True(t, errors.Is(err, gorm.ErrRecordNotFound))
+
+	stored.Tickers = []string{"NVDA"}
+	assert.Nil(t, repo.Update(stored))
+	byName, err := repo.GetByName("alice", "chips")
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"NVDA"}, byName.Tickers)
+
+	lists, err := repo.ListByUser("alice")
+	assert.Nil(t, err)
+	assert.Len(t, lists, 1)
+
+	assert.True(t, errors.Is(repo.Delete("bob", watchlist.ID), gorm.ErrRecordNotFound))
+	assert.Nil(t, repo.Delete("alice", watchlist.ID))
+	_, err = repo.GetByID("alice", watchlist.ID)
+	assert.True(t, errors.Is(err, gorm.ErrRecordNotFound))
+}
+
+func TestWatchlistDuplicateName(t *testing.T) {
+	repo := setupWatchlistRepository(t)
+
+	assert.Nil(t, repo.Create(&domain.Watchlist{UserID: "alice", Name: "chips"}))
+	assert.NotNil(t, repo.Create(&domain.Watchlist{UserID: "alice", Name: "chips"}))
+}
@@ -0,0 +1,107 @@
+package repository
+
+import (
+	"time"
+
+	"recommender/internal/core/domain"
+	port "recommender/internal/core/ports"
+
+	"gorm.io/gorm"
+)
+
+type CockroachWebhookRepository struct {
+	db *gorm.DB
+}
+
+func NewCockroachWebhookRepository(db *gorm.DB) port.WebhookRepository {
+	return &CockroachWebhookRepository{db: db}
+}
+
+func (r *CockroachWebhookRepository) CreateSubscription(subscription *domain.WebhookSubscription) error {
+	return r.db.Create(subscription).Error
+}
+
+func (r *CockroachWebhookRepository) GetSubscription(userID string, id uint) (*domain.WebhookSubscription, error) {
+	var subscription domain.WebhookSubscription
+	result := r.db.Where("user_id = ?", userID).First(&subscription, id)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return &subscription, nil
+}
+
+func (r *CockroachWebhookRepository) ListSubscriptions(userID string) ([]domain.WebhookSubscription, error) {
+	var subscriptions []domain.WebhookSubscription
+	result := r.db.Where("user_id = ?", userID).Order("id ASC").Find(&subscriptions)
+	return subscriptions, result.Error
+}
+
+// DeleteSubscription devuelve gorm.ErrRecordNotFound si no existe o es de otro usuario.
+func (r *CockroachWebhookRepository) DeleteSubscription(userID string, id uint) error {
+	result := r.db.Where("user_id = ?", userID).Delete(&domain.WebhookSubscription{}, id)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+func (r *CockroachWebhookRepository) ListActiveSubscriptions() ([]domain.WebhookSubscription, error) {
+	var subscriptions []domain.WebhookSubscription
+	result := r.db.Where("active = ?", true).Order("id ASC").Find(&subscriptions)
+	return subscriptions, result.Error
+}
+
+func (r *CockroachWebhookRepository) PendingOutboxEvents(limit int) ([]domain.OutboxEvent, error) {
+	var events []domain.OutboxEvent
+	result := r.db.Where("dispatched_at IS NULL").Order("id ASC").Limit(limit).Find(&events)
+	return events, result.Error
+}
+
+func (r *CockroachWebhookRepository) GetOutboxEvent(id uint) (*domain.OutboxEvent, error) {
+	var event domain.OutboxEvent
+	result := r.db.First(&event, id)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return &event, nil
+}
+
+func (r *CockroachWebhookRepository) FanOut(event domain.OutboxEvent, deliveries []domain.WebhookDelivery, at time.Time) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if len(deliveries) > 0 {
+			if err := tx.Create(&deliveries).Error; err != nil {
+				return err
+			}
+		}
+		return tx.Model(&domain.OutboxEvent{}).Where("id = ?", event.ID).Update("dispatched_at", at).Error
+	})
+}
+
+func (r *CockroachWebhookRepository) DueDeliveries(now time.Time, limit int) ([]domain.WebhookDelivery, error) {
+	var deliveries []domain.WebhookDelivery
+	result := r.db.Where("status = ? AND next_attempt_at <= ?", domain.DeliveryPending, now).
+		Order("next_attempt_at ASC, id ASC").Limit(limit).Find(&deliveries)
+	return deliveries, result.Error
+}
+
+func (r *CockroachWebhookRepository) GetDelivery(id uint) (*domain.WebhookDelivery, error) {
+	var delivery domain.WebhookDelivery
+	result := r.db.First(&delivery, id)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return &delivery, nil
+}
+
+func (r *CockroachWebhookRepository) SaveDelivery(delivery *domain.WebhookDelivery) error {
+	return r.db.Save(delivery).Error
+}
+
+func (r *CockroachWebhookRepository) ListDeliveries(subscriptionID uint, limit, offset int) ([]domain.WebhookDelivery, error) {
+	var deliveries []domain.WebhookDelivery
+	result := r.db.Where("subscription_id = ?", subscriptionID).Order("id DESC").Limit(limit).Offset(offset).Find(&deliveries)
+	return deliveries, result.Error
+}
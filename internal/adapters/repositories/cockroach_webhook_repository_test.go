@@ -0,0 +1,58 @@
+package repository
+
+import (
+	"testing"
+	"time"
+
+	"recommender/internal/core/domain"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func setupWebhookRepository(t *testing.T) (*CockroachWebhookRepository, *CockroachStockRepository) {
+	db := setupTestDB(t)
+	if err := db.AutoMigrate(&domain.WebhookSubscription{}, &domain.WebhookDelivery{}); err != nil {
+		t.Fatalf("failed to migrate: %v", err)
+	}
+	return NewCockroachWebhookRepository(db).(*CockroachWebhookRepository),
+		NewCockroachStockRepository(db).(*CockroachStockRepository)
+}
+
+func TestWebhookOutboxFanOut(t *testing.T) {
+	repo, stocks := setupWebhookRepository(t)
+	now := time.Now()
+
+	subscription := &domain.WebhookSubscription{UserID: "alice", URL: "http://hook", Secret: "s", Active: true, Tickers: []string{"AAPL"}}
+	assert.Nil(t, repo.CreateSubscription(subscription))
+	assert.Nil(t, stocks.Create(&domain.Stock{Ticker: "AAPL", Time: now}))
+	assert.Nil(t, stocks.Create(&domain.Stock{Ticker: "MSFT", Time: now}))
+
+	pending, err := repo.PendingOutboxEvents(10)
+	assert.Nil(t, err)
+	assert.Len(t, pending, 2)
+	assert.True(t, pending[0].ID < pending[1].ID)
+
+	delivery := domain.WebhookDelivery{SubscriptionID: subscription.ID, OutboxEventID: pending[0].ID, Status: domain.DeliveryPending, NextAttemptAt: &now}
+	assert.Nil(t, repo.FanOut(pending[0], []domain.WebhookDelivery{delivery}, now))
+
+	pending, err = repo.PendingOutboxEvents(10)
+	assert.Nil(t, err)
+	assert.Len(t, pending, 1)
+
+	due, err := repo.DueDeliveries(now.Add(time.Second), 10)
+	assert.Nil(t, err)
+	assert.Len(t, due, 1)
+
+	later := now.Add(time.Hour)
+	due[0].Attempts = 1
+	due[0].NextAttemptAt = &later
+	assert.Nil(t, repo.SaveDelivery(&due[0]))
+
+	due, err = repo.DueDeliveries(now.Add(time.Second), 10)
+	assert.Nil(t, err)
+	assert.Empty(t, due)
+
+	deliveries, err := repo.ListDeliveries(subscription.ID, 10, 0)
+	assert.Nil(t, err)
+	assert.Equal(t, 1, deliveries[0].Attempts)
+}
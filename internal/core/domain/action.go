@@ -0,0 +1,71 @@
+package domain
+
+import "strings"
+
+// ActionType clasifica el texto libre de Stock.Action en una taxonomía fija.
+type ActionType string
+
+const (
+	ActionUpgrade       ActionType = "upgrade"
+	ActionDowngrade     ActionType = "downgrade"
+	ActionInitiate      ActionType = "initiate"
+	ActionReiterate     ActionType = "reiterate"
+	ActionTargetRaised  ActionType = "target_raised"
+	ActionTargetLowered ActionType = "target_lowered"
+	ActionOther         ActionType = "other"
+)
+
+// ActionTypes lista todos los valores válidos de la taxonomía.
+var ActionTypes = []ActionType{
+	ActionUpgrade,
+	ActionDowngrade,
+	ActionInitiate,
+	ActionReiterate,
+	ActionTargetRaised,
+	ActionTargetLowered,
+	ActionOther,
+}
+
+func (a ActionType) Valid() bool {
+	for _, action := range ActionTypes {
+		if a == action {
+			return true
+		}
+	}
+	return false
+}
+
+// ParseAction interpreta textos como "upgraded by", "target raised by" o
+// "initiated by". Lo que no se reconoce queda como ActionOther.
+func ParseAction(raw string) ActionType {
+	action := strings.ToLower(strings.TrimSpace(raw))
+	switch {
+	case action == "":
+		return ActionOther
+	case strings.Contains(action, "upgrade"):
+		return ActionUpgrade
+	case strings.Contains(action, "downgrade"):
+		return ActionDowngrade
+	case strings.Contains(action, "initiat"), strings.Contains(action, "resum"):
+		return ActionInitiate
+	case strings.Contains(action, "reiterat"), strings.Contains(action, "maintain"),
+		strings.Contains(action, "affirm"):
+		return ActionReiterate
+	case strings.Contains(action, "raise"):
+		return ActionTargetRaised
+	case strings.Contains(action, "lower"):
+		return ActionTargetLowered
+	default:
+		return ActionOther
+	}
+}
+
+// StockFilter agrupa los filtros opcionales para listar eventos de rating.
+type StockFilter struct {
+	ActionTypes []ActionType
+	Tickers     []string
+}
+
+func (f StockFilter) IsEmpty() bool {
+	return len(f.ActionTypes) == 0 && len(f.Tickers) == 0
+}
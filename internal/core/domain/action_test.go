@@ -0,0 +1,35 @@
+package domain_test
+
+import (
+	"testing"
+
+	"recommender/internal/core/domain"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseAction(t *testing.T) {
+	testCases := map[string]domain.ActionType{
+		"upgraded by":       domain.ActionUpgrade,
+		"downgraded by":     domain.ActionDowngrade,
+		"initiated by":      domain.ActionInitiate,
+		"coverage resumed":  domain.ActionInitiate,
+		"reiterated by":     domain.ActionReiterate,
+		"target raised by":  domain.ActionTargetRaised,
+		"target lowered by": domain.ActionTargetLowered,
+		"target set by":     domain.ActionOther,
+		"":                  domain.ActionOther,
+		"Buy":               domain.ActionOther,
+	}
+
+	for raw, expected := range testCases {
+		assert.Equal(t, expected, domain.ParseAction(raw), "ParseAction(%q)", raw)
+	}
+}
+
+func TestActionTypeValid(t *testing.T) {
+	assert.True(t, domain.ActionTargetRaised.Valid())
+	assert.False(t, domain.ActionType("raised").Valid())
+	assert.True(t, domain.StockFilter{}.IsEmpty())
+	assert.False(t, domain.StockFilter{ActionTypes: []domain.ActionType{domain.ActionUpgrade}}.IsEmpty())
+}
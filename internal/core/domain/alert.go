@@ -0,0 +1,56 @@
+package domain
+
+import "time"
+
+// Canales de entrega de alertas.
+const (
+	NotifierLog     = "log"
+	NotifierWebhook = "webhook"
+	NotifierSMTP    = "smtp"
+)
+
+// AlertRule describe qué eventos de rating le interesan a un usuario. Todas las
+// condiciones no vacías deben cumplirse; los tickers de la watchlist se suman a Tickers.
+// MinTargetChangePct positivo exige un target elevado al menos ese porcentaje y
+// negativo uno reducido al menos ese porcentaje.
+type AlertRule struct {
+	ID                 uint         `json:"id" gorm:"primaryKey"`
+	UserID             string       `json:"user_id" gorm:"index"`
+	Name               string       `json:"name"`
+	WatchlistID        *uint        `json:"watchlist_id,omitempty"`
+	Tickers            []string     `json:"tickers,omitempty" gorm:"serializer:json"`
+	ActionTypes        []ActionType `json:"action_types,omitempty" gorm:"serializer:json"`
+	Brokerage          string       `json:"brokerage,omitempty"` // Coincidencia parcial sin distinguir mayúsculas
+	MinTargetChangePct float64      `json:"min_target_change_pct,omitempty"`
+	Notifier           string       `json:"notifier"`
+	Target             string       `json:"target,omitempty"` // URL del webhook o dirección de correo
+	Active             bool         `json:"active" gorm:"index"`
+	CreatedAt          time.Time    `json:"created_at"`
+}
+
+// HasConditions indica si la regla filtra algo; una regla sin condiciones se rechaza.
+func (r AlertRule) HasConditions() bool {
+	return r.WatchlistID != nil || len(r.Tickers) > 0 || len(r.ActionTypes) > 0 ||
+		r.Brokerage != "" || r.MinTargetChangePct != 0
+}
+
+// AlertEvent registra que un evento de rating cumplió una regla y el resultado de la entrega.
+type AlertEvent struct {
+	ID            uint      `json:"id" gorm:"primaryKey"`
+	RuleID        uint      `json:"rule_id" gorm:"index"`
+	UserID        string    `json:"user_id" gorm:"index"`
+	StockID       uint      `json:"stock_id"`
+	Ticker        string    `json:"ticker"`
+	Message       string    `json:"message"`
+	Notifier      string    `json:"notifier"`
+	Delivered     bool      `json:"delivered"`
+	DeliveryError string    `json:"delivery_error,omitempty"`
+	CreatedAt     time.Time `json:"created_at"`
+}
+
+// Alert es lo que recibe un notificador: la regla, el evento de rating y el registro.
+type Alert struct {
+	Rule  AlertRule  `json:"rule"`
+	Stock Stock      `json:"stock"`
+	Event AlertEvent `json:"event"`
+}
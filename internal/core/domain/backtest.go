@@ -0,0 +1,34 @@
+package domain
+
+import "time"
+
+// BacktestConfig define cómo se reproduce el historial de eventos.
+type BacktestConfig struct {
+	Start        time.Time `json:"start"`
+	End          time.Time `json:"end"`
+	StepDays     int       `json:"step_days"`    // Cada cuántos días se recalcula el top-N
+	HorizonDays  int       `json:"horizon_days"` // Horizonte del retorno futuro de cada pick
+	TopN         int       `json:"top_n"`
+	LookbackDays int       `json:"lookback_days"`
+	GroupBy      string    `json:"group_by"`
+	Strategies   []string  `json:"strategies"`
+}
+
+// BacktestResult resume el desempeño de una estrategia. Los retornos son fracciones
+// (0.05 = 5%) y MaxDrawdown es la mayor caída desde un máximo de la curva de capital.
+type BacktestResult struct {
+	Strategy      string  `json:"strategy"`
+	ScorerVersion string  `json:"scorer_version"`
+	Periods       int     `json:"periods"`
+	Picks         int     `json:"picks"`
+	SkippedPicks  int     `json:"skipped_picks"` // Picks sin precio de entrada o de salida
+	HitRate       float64 `json:"hit_rate"`
+	AvgReturn     float64 `json:"avg_return"`
+	MaxDrawdown   float64 `json:"max_drawdown"`
+	Turnover      float64 `json:"turnover"`
+}
+
+type BacktestReport struct {
+	Config  BacktestConfig   `json:"config"`
+	Results []BacktestResult `json:"results"`
+}
@@ -0,0 +1,13 @@
+package domain
+
+// Company es la entidad de referencia de un ticker. Los eventos de rating
+// (Stock) la referencian por Ticker == Symbol, de modo que el nombre, la bolsa
+// y el sector se mantienen en un único lugar.
+type Company struct {
+	Symbol   string `json:"symbol" gorm:"primaryKey"`
+	Name     string `json:"name" gorm:"index"`
+	Exchange string `json:"exchange"`
+	Sector   string `json:"sector" gorm:"index"`
+	Industry string `json:"industry"`
+	Active   bool   `json:"active"`
+}
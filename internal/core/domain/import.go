@@ -0,0 +1,62 @@
+package domain
+
+import "time"
+
+// ImportLineError describe una fila rechazada o ignorada de un archivo de importación.
+type ImportLineError struct {
+	Line  int    `json:"line"`
+	Error string `json:"error"`
+}
+
+// ImportReport resume una importación masiva. Con DryRun, Inserted cuenta las filas
+// que se habrían insertado.
+type ImportReport struct {
+	DryRun     bool              `json:"dry_run"`
+	Inserted   int               `json:"inserted"`
+	Duplicates []ImportLineError `json:"duplicates"`
+	Invalid    []ImportLineError `json:"invalid"`
+}
+
+// Políticas ante una página de la API externa que falla (ya agotados los reintentos del cliente).
+const (
+	PageFailureAbort   = "abort"   // Se detiene la corrida y se reporta como fallida
+	PageFailureSkip    = "skip"    // Se saltea la página si el proveedor sabe cuál sigue
+	PageFailurePartial = "partial" // Se detiene y se conserva lo importado, marcando la corrida parcial
+)
+
+// Estados de una corrida de importación desde la API externa.
+const (
+	SyncCompleted = "completed"
+	SyncPartial   = "partial"
+	SyncFailed    = "failed"
+)
+
+// ValidPageFailurePolicy indica si policy es una de las políticas conocidas.
+func ValidPageFailurePolicy(policy string) bool {
+	switch policy {
+	case PageFailureAbort, PageFailureSkip, PageFailurePartial:
+		return true
+	}
+	return false
+}
+
+// PageFailure es una página que no se pudo importar; Page es el cursor con que se pidió.
+type PageFailure struct {
+	Page  string `json:"page"`
+	Error string `json:"error"`
+}
+
+// SyncResult resume una corrida de importación desde la API externa. ResumeFrom es el
+// cursor desde el que habría que retomar cuando la corrida se detuvo antes del final.
+type SyncResult struct {
+	Status      string        `json:"status"`
+	Policy      string        `json:"policy"`
+	Pages       int           `json:"pages"`
+	Inserted    int           `json:"inserted"`
+	Duplicates  int           `json:"duplicates"`
+	ItemErrors  int           `json:"item_errors"`
+	FailedPages []PageFailure `json:"failed_pages"`
+	ResumeFrom  string        `json:"resume_from,omitempty"`
+	StartedAt   time.Time     `json:"started_at"`
+	FinishedAt  time.Time     `json:"finished_at"`
+}
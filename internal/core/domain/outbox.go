@@ -0,0 +1,52 @@
+package domain
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// Tipos de evento publicados en el outbox.
+const EventStockCreated = "stock.created"
+
+// OutboxEvent se escribe en la misma transacción que el dato que lo origina, de modo
+// que ningún evento se pierde si el proceso muere después del insert. El ID creciente
+// sirve también como número de secuencia para los consumidores.
+type OutboxEvent struct {
+	ID           uint       `json:"id" gorm:"primaryKey"`
+	Type         string     `json:"type"`
+	StockID      uint       `json:"stock_id"`
+	Payload      string     `json:"payload"` // Stock serializado en JSON
+	CreatedAt    time.Time  `json:"created_at"`
+	DispatchedAt *time.Time `json:"dispatched_at,omitempty" gorm:"index"`
+}
+
+// OutboxCursor es hasta qué evento del outbox llegó un consumidor que lo recorre por
+// secuencia, para que retome donde quedó tras reiniciar o desde otro proceso.
+type OutboxCursor struct {
+	Consumer    string    `json:"consumer" gorm:"primaryKey"`
+	LastEventID uint      `json:"last_event_id"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+// NewStockCreatedEvent arma el evento de outbox de un stock recién guardado.
+func NewStockCreatedEvent(stock Stock) (OutboxEvent, error) {
+	payload, err := json.Marshal(stock)
+	if err != nil {
+		return OutboxEvent{}, err
+	}
+	return OutboxEvent{Type: EventStockCreated, StockID: stock.ID, Payload: string(payload)}, nil
+}
+
+// Stock decodifica el payload de un evento stock.created.
+func (e OutboxEvent) Stock() (Stock, error) {
+	var stock Stock
+	err := json.Unmarshal([]byte(e.Payload), &stock)
+	return stock, err
+}
+
+// StockEvent es un stock publicado en el stream junto con su número de secuencia
+// (el ID del evento de outbox), usado para reanudar con Last-Event-ID.
+type StockEvent struct {
+	Sequence uint  `json:"sequence"`
+	Stock    Stock `json:"stock"`
+}
@@ -0,0 +1,25 @@
+package domain
+
+import "time"
+
+// PriceBar es el precio de cierre diario de un ticker.
+type PriceBar struct {
+	Ticker string    `json:"ticker" gorm:"primaryKey"`
+	Date   time.Time `json:"date" gorm:"primaryKey"`
+	Close  float64   `json:"close"`
+}
+
+// BrokerageAccuracy mide qué tan acertadas fueron las acciones pasadas de una corredora.
+// HitRate es la fracción de targets alcanzados dentro del horizonte y DirectionAccuracy
+// la de upgrades/downgrades seguidos por un movimiento del precio en la misma dirección.
+type BrokerageAccuracy struct {
+	Brokerage         string  `json:"brokerage"`
+	EvaluatedEvents   int     `json:"evaluated_events"`
+	TargetEvents      int     `json:"target_events"`
+	TargetHits        int     `json:"target_hits"`
+	HitRate           float64 `json:"hit_rate"`
+	DirectionalEvents int     `json:"directional_events"`
+	DirectionHits     int     `json:"direction_hits"`
+	DirectionAccuracy float64 `json:"direction_accuracy"`
+	Weight            float64 `json:"weight"`
+}
@@ -0,0 +1,41 @@
+package domain
+
+// RatingScore es la escala normalizada de 5 puntos a la que se mapean las
+// etiquetas de rating de las corredoras. El valor cero indica una etiqueta desconocida.
+type RatingScore int
+
+const (
+	RatingUnknown    RatingScore = 0
+	RatingStrongSell RatingScore = 1
+	RatingSell       RatingScore = 2
+	RatingHold       RatingScore = 3
+	RatingBuy        RatingScore = 4
+	RatingStrongBuy  RatingScore = 5
+)
+
+func (r RatingScore) Known() bool {
+	return r >= RatingStrongSell && r <= RatingStrongBuy
+}
+
+func (r RatingScore) String() string {
+	switch r {
+	case RatingStrongSell:
+		return "Strong Sell"
+	case RatingSell:
+		return "Sell"
+	case RatingHold:
+		return "Hold"
+	case RatingBuy:
+		return "Buy"
+	case RatingStrongBuy:
+		return "Strong Buy"
+	default:
+		return "Unknown"
+	}
+}
+
+// UnknownRatingLabel reporta una etiqueta recibida que no está en el mapeo.
+type UnknownRatingLabel struct {
+	Label string `json:"label"`
+	Count int    `json:"count"`
+}
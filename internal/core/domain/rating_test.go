@@ -0,0 +1,19 @@
+package domain_test
+
+import (
+	"testing"
+
+	"recommender/internal/core/domain"
+)
+
+func TestRatingScoreKnownAndString(t *testing.T) {
+	if domain.RatingUnknown.Known() {
+		t.Errorf("expected RatingUnknown to be unknown")
+	}
+	if !domain.RatingStrongBuy.Known() || domain.RatingStrongBuy.String() != "Strong Buy" {
+		t.Errorf("unexpected RatingStrongBuy: %v", domain.RatingStrongBuy)
+	}
+	if domain.RatingScore(9).Known() {
+		t.Errorf("expected out of range score to be unknown")
+	}
+}
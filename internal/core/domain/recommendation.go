@@ -0,0 +1,110 @@
+package domain
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+const (
+	MaxRecommendationLimit = 100
+	MaxLookbackDays        = 365
+)
+
+// Modos de agrupación de las recomendaciones.
+const (
+	GroupByTicker = "ticker" // Una recomendación por ticker combinando sus eventos
+	GroupByEvent  = "event"  // Una recomendación por evento de rating (comportamiento original)
+)
+
+// RecommendationParams controla cuántas recomendaciones se devuelven y qué eventos
+// se consideran. CandidatePool en 0 evalúa todos los eventos de la ventana.
+// Las restricciones de diversificación en 0 no se aplican.
+type RecommendationParams struct {
+	Limit           int      `json:"limit"`
+	LookbackDays    int      `json:"lookback_days"`
+	CandidatePool   int      `json:"candidate_pool"`
+	Strategy        string   `json:"strategy"`
+	GroupBy         string   `json:"group_by"`
+	MaxPerSector    int      `json:"max_per_sector"`
+	MaxPerBrokerage int      `json:"max_per_brokerage"`
+	MinBrokerages   int      `json:"min_brokerages"`    // Corredoras distintas que deben respaldar cada pick
+	Tickers         []string `json:"tickers,omitempty"` // Si no está vacío, solo se consideran estos tickers
+}
+
+// Key identifica los parámetros de forma canónica: dos listas con la misma clave son
+// comparables entre sí.
+func (p RecommendationParams) Key() string {
+	key, _ := json.Marshal(p)
+	return string(key)
+}
+
+// HasConstraints indica si hay que re-rankear para diversificar el resultado.
+func (p RecommendationParams) HasConstraints() bool {
+	return p.MaxPerSector > 0 || p.MaxPerBrokerage > 0 || p.MinBrokerages > 0
+}
+
+func (p RecommendationParams) Validate() error {
+	if p.Limit < 1 || p.Limit > MaxRecommendationLimit {
+		return fmt.Errorf("limit must be between 1 and %d", MaxRecommendationLimit)
+	}
+	if p.LookbackDays < 1 || p.LookbackDays > MaxLookbackDays {
+		return fmt.Errorf("lookback_days must be between 1 and %d", MaxLookbackDays)
+	}
+	if p.CandidatePool < 0 {
+		return fmt.Errorf("candidate_pool must be zero (all events) or positive")
+	}
+	if p.GroupBy != "" && p.GroupBy != GroupByTicker && p.GroupBy != GroupByEvent {
+		return fmt.Errorf("group_by must be '%s' or '%s'", GroupByTicker, GroupByEvent)
+	}
+	if p.MaxPerSector < 0 || p.MaxPerBrokerage < 0 || p.MinBrokerages < 0 {
+		return fmt.Errorf("max_per_sector, max_per_brokerage and min_brokerages must be zero (no constraint) or positive")
+	}
+	return nil
+}
+
+// ScoreBreakdown explica cómo se llegó a la puntuación de una recomendación.
+type ScoreBreakdown struct {
+	Total           float64 `json:"total"`
+	PriceImpact     float64 `json:"price_impact_pct"`
+	RatingImpact    float64 `json:"rating_impact"`
+	ActionImpact    float64 `json:"action_impact"`
+	BrokerageWeight float64 `json:"brokerage_weight"`
+	DecayFactor     float64 `json:"decay_factor"` // 1 cuando el evento no pierde peso por antigüedad
+	AgeDays         float64 `json:"age_days"`
+	Rationale       string  `json:"rationale"`
+}
+
+// TickerAggregate resume los eventos combinados en una recomendación por ticker.
+type TickerAggregate struct {
+	EventCount           int     `json:"event_count"`
+	Brokerages           int     `json:"brokerages"`
+	Upgrades             int     `json:"upgrades"`
+	Downgrades           int     `json:"downgrades"`
+	ConsensusShift       float64 `json:"consensus_shift"` // Cambio medio ponderado de rating, en escalones de la escala de 5 puntos
+	WeightedTargetChange float64 `json:"weighted_target_change_pct"`
+}
+
+// Recommendation es un elemento del ranking. En modo ticker Stock es el evento más
+// reciente y Events contiene todos los eventos combinados.
+type Recommendation struct {
+	Rank      int              `json:"rank"`
+	Ticker    string           `json:"ticker"`
+	Company   string           `json:"company"`
+	Sector    string           `json:"sector,omitempty"` // Solo se completa al diversificar por sector
+	Stock     Stock            `json:"stock"`
+	Score     float64          `json:"score"`
+	Breakdown ScoreBreakdown   `json:"breakdown"`
+	Aggregate *TickerAggregate `json:"aggregate,omitempty"`
+	Events    []Stock          `json:"events,omitempty"`
+}
+
+// RecommendationList es la respuesta de /stocks/recommendations junto con la
+// estrategia y la versión del scorer que la produjo.
+type RecommendationList struct {
+	Strategy      string               `json:"strategy"`
+	ScorerVersion string               `json:"scorer_version"`
+	GeneratedAt   time.Time            `json:"generated_at"`
+	Params        RecommendationParams `json:"params"`
+	Items         []Recommendation     `json:"items"`
+}
@@ -0,0 +1,24 @@
+package domain
+
+// Tipos de mensaje del feed en vivo de recomendaciones.
+const (
+	FeedSnapshot = "snapshot" // Lista completa: al suscribirse o al resincronizar a un cliente lento
+	FeedDiff     = "diff"     // Solo los cambios de ranking respecto al mensaje anterior
+	FeedError    = "error"
+)
+
+// RecommendationUpdate es un mensaje del feed. Aplicar en orden los diffs sobre el
+// último snapshot recibido reproduce la lista vigente del servidor.
+type RecommendationUpdate struct {
+	Type  string              `json:"type"`
+	List  *RecommendationList `json:"list,omitempty"`
+	Diff  *RecommendationDiff `json:"diff,omitempty"`
+	Error string              `json:"error,omitempty"`
+}
+
+// FeedRequest es el mensaje con el que el cliente cambia sus parámetros de scoring.
+// Los campos omitidos conservan los valores por defecto del servidor.
+type FeedRequest struct {
+	Type   string               `json:"type"` // "subscribe"
+	Params RecommendationParams `json:"params"`
+}
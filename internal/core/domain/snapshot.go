@@ -0,0 +1,72 @@
+package domain
+
+import "time"
+
+// SnapshotItem es la forma guardada de un elemento del ranking.
+type SnapshotItem struct {
+	Rank    int     `json:"rank"`
+	Ticker  string  `json:"ticker"`
+	Company string  `json:"company"`
+	Score   float64 `json:"score"`
+}
+
+// RecommendationSnapshot guarda una lista de recomendaciones tal como se calculó.
+type RecommendationSnapshot struct {
+	ID            uint                 `json:"id" gorm:"primaryKey"`
+	Strategy      string               `json:"strategy" gorm:"index"`
+	ScorerVersion string               `json:"scorer_version"`
+	GeneratedAt   time.Time            `json:"generated_at" gorm:"index"`
+	Params        RecommendationParams `json:"params" gorm:"serializer:json"`
+	ParamsKey     string               `json:"-" gorm:"index"` // Params.Key() con la estrategia resuelta
+	Items         []SnapshotItem       `json:"items" gorm:"serializer:json"`
+}
+
+// NewRecommendationSnapshot resume la lista calculada para guardarla.
+func NewRecommendationSnapshot(list RecommendationList) RecommendationSnapshot {
+	items := make([]SnapshotItem, 0, len(list.Items))
+	for _, rec := range list.Items {
+		items = append(items, SnapshotItem{Rank: rec.Rank, Ticker: rec.Ticker, Company: rec.Company, Score: rec.Score})
+	}
+	return RecommendationSnapshot{
+		Strategy:      list.Strategy,
+		ScorerVersion: list.ScorerVersion,
+		GeneratedAt:   list.GeneratedAt,
+		Params:        list.Params,
+		ParamsKey:     SnapshotParamsKey(list.Params, list.Strategy),
+		Items:         items,
+	}
+}
+
+// SnapshotParamsKey es la clave con la que se agrupan los snapshots comparables. La
+// estrategia vacía de los parámetros se reemplaza por la que se usó en realidad.
+func SnapshotParamsKey(params RecommendationParams, strategy string) string {
+	params.Strategy = strategy
+	return params.Key()
+}
+
+// SnapshotRef identifica un snapshot en un diff sin repetir sus elementos.
+type SnapshotRef struct {
+	ID          uint      `json:"id"`
+	Strategy    string    `json:"strategy"`
+	GeneratedAt time.Time `json:"generated_at"`
+}
+
+// RankMove es el cambio de posición de un ticker presente en ambos snapshots.
+// Change es positivo cuando el ticker sube en el ranking.
+type RankMove struct {
+	Ticker   string `json:"ticker"`
+	FromRank int    `json:"from_rank"`
+	ToRank   int    `json:"to_rank"`
+	Change   int    `json:"change"`
+}
+
+// RecommendationDiff compara dos snapshots: qué tickers entraron, cuáles salieron
+// y cuáles cambiaron de posición.
+type RecommendationDiff struct {
+	From      SnapshotRef    `json:"from"`
+	To        SnapshotRef    `json:"to"`
+	Entries   []SnapshotItem `json:"entries"`
+	Exits     []SnapshotItem `json:"exits"`
+	Moves     []RankMove     `json:"moves"`
+	Unchanged int            `json:"unchanged"`
+}
@@ -3,18 +3,29 @@ package domain
 import "time"
 
 type Stock struct {
-	ID         uint      `json:"id" gorm:"primaryKey"`
-	Ticker     string    `json:"ticker"`
-	Company    string    `json:"company"`
-	Brokerage  string    `json:"brokerage"`
-	Action     string    `json:"action"`
-	RatingFrom string    `json:"rating_from"`
-	RatingTo   string    `json:"rating_to"`
-	TargetFrom float64   `json:"target_from"`
-	TargetTo   float64   `json:"target_to"`
-	Time       time.Time `json:"time"`
+	ID              uint        `json:"id" gorm:"primaryKey"`
+	Ticker          string      `json:"ticker"`
+	Company         string      `json:"company"`
+	Brokerage       string      `json:"brokerage"`
+	Action          string      `json:"action"`
+	ActionType      ActionType  `json:"action_type" gorm:"index"` // Action clasificado al ingerir
+	RatingFrom      string      `json:"rating_from"`
+	RatingTo        string      `json:"rating_to"`
+	RatingFromScore RatingScore `json:"rating_from_score"` // RatingFrom normalizado a la escala de 5 puntos
+	RatingToScore   RatingScore `json:"rating_to_score"`   // RatingTo normalizado a la escala de 5 puntos
+	TargetFrom      float64     `json:"target_from"`
+	TargetTo        float64     `json:"target_to"`
+	Time            time.Time   `json:"time"`
+	Source          string      `json:"source" gorm:"uniqueIndex:idx_stocks_source_external_id,where:external_id <> ''"` // Proveedor que originó el evento
+	ExternalID      string      `json:"external_id" gorm:"uniqueIndex:idx_stocks_source_external_id"`                    // ID del evento en ese proveedor
 }
 
+// Orígenes de los eventos que no vienen de un proveedor configurado.
+const (
+	SourceManual = "manual" // POST /stocks
+	SourceImport = "import" // Importación desde archivo
+)
+
 type APIResponse struct {
 	Items    []Stock `json:"items"`
 	NextPage string  `json:"next_page"`
@@ -1,5 +1,12 @@
 package domain
 
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
 type StockDTO struct {
 	Ticker     string `json:"ticker"`
 	TargetFrom string `json:"target_from"` // String como viene de la API
@@ -10,6 +17,8 @@ type StockDTO struct {
 	RatingFrom string `json:"rating_from"`
 	RatingTo   string `json:"rating_to"`
 	Time       string `json:"time"` // String antes de parsear a `time.Time`
+	Source     string `json:"source,omitempty"`
+	ExternalID string `json:"external_id,omitempty"`
 }
 
 // APIResponseDTO representa la estructura de respuesta de la API externa
@@ -17,3 +26,60 @@ type APIResponseDTO struct {
 	Items    []StockDTO `json:"items"`
 	NextPage string     `json:"next_page"`
 }
+
+// ParsePrice convierte precios como " $2,500.99 " a float64.
+func ParsePrice(priceStr string) (float64, error) {
+	//elimina caracteres no necesarios
+	priceStr = strings.TrimSpace(strings.Replace(priceStr, "$", "", -1))
+	priceStr = strings.Replace(priceStr, ",", "", -1)
+
+	if priceStr == "" {
+		return 0, fmt.Errorf("parsePrice: recibido string vacío")
+	}
+
+	price, err := strconv.ParseFloat(priceStr, 64)
+	if err != nil {
+		return 0, fmt.Errorf("parsePrice: error convirtiendo '%s' a float64", priceStr)
+	}
+
+	return price, nil
+}
+
+// ParseTime lee las fechas en RFC3339, como las envía la API.
+func ParseTime(timeStr string) (time.Time, error) {
+	return time.Parse(time.RFC3339, timeStr)
+}
+
+// ToStock valida y convierte el DTO. Lo usan tanto el cliente de la API como la
+// importación de archivos, para que ambos acepten exactamente lo mismo.
+func (d StockDTO) ToStock() (Stock, error) {
+	if strings.TrimSpace(d.Ticker) == "" {
+		return Stock{}, fmt.Errorf("error parsing Ticker: ticker vacío")
+	}
+	targetFrom, err := ParsePrice(d.TargetFrom)
+	if err != nil {
+		return Stock{}, fmt.Errorf("error parsing TargetFrom: %w", err)
+	}
+	targetTo, err := ParsePrice(d.TargetTo)
+	if err != nil {
+		return Stock{}, fmt.Errorf("error parsing TargetTo: %w", err)
+	}
+	parsedTime, err := ParseTime(d.Time)
+	if err != nil {
+		return Stock{}, fmt.Errorf("error parsing Time: %w", err)
+	}
+
+	return Stock{
+		Ticker:     strings.TrimSpace(d.Ticker),
+		TargetFrom: targetFrom,
+		TargetTo:   targetTo,
+		Company:    d.Company,
+		Brokerage:  d.Brokerage,
+		Action:     d.Action,
+		RatingFrom: d.RatingFrom,
+		RatingTo:   d.RatingTo,
+		Time:       parsedTime,
+		Source:     d.Source,
+		ExternalID: d.ExternalID,
+	}, nil
+}
@@ -60,4 +60,23 @@ func TestAPIResponseDTO_UnmarshalJSON(t *testing.T) {
 	assert.Len(t, response.Items, 1)
 	assert.Equal(t, "GOOGL", response.Items[0].Ticker)
 	assert.Equal(t, "page_2", response.NextPage)
-}
\ No newline at end of file
+}
+func TestStockDTO_ToStock(t *testing.T) {
+	dto := domain.StockDTO{Ticker: " AAPL ", TargetFrom: "$1,150.00", TargetTo: "180", RatingTo: "Buy", Time: "2024-06-01T15:04:05Z"}
+
+	stock, err := dto.ToStock()
+	assert.NoError(t, err)
+	assert.Equal(t, "AAPL", stock.Ticker)
+	assert.Equal(t, 1150.0, stock.TargetFrom)
+	assert.Equal(t, 180.0, stock.TargetTo)
+	assert.Equal(t, 2024, stock.Time.Year())
+
+	dto.Ticker = ""
+	_, err = dto.ToStock()
+	assert.ErrorContains(t, err, "Ticker")
+
+	dto.Ticker = "AAPL"
+	dto.Time = "2024-06-01"
+	_, err = dto.ToStock()
+	assert.ErrorContains(t, err, "error parsing Time")
+}
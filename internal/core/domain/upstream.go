@@ -0,0 +1,24 @@
+package domain
+
+import "time"
+
+// Estados del circuit breaker de un proveedor externo.
+const (
+	CircuitClosed   = "closed"    // Las llamadas pasan normalmente
+	CircuitOpen     = "open"      // Se rechazan sin llamar hasta que pase el enfriamiento
+	CircuitHalfOpen = "half_open" // Se deja pasar una llamada de prueba
+)
+
+// UpstreamStats resume cómo se está comportando un proveedor externo desde que arrancó el proceso.
+type UpstreamStats struct {
+	Source              string     `json:"source"`
+	Circuit             string     `json:"circuit"`
+	Requests            int        `json:"requests"`
+	Retries             int        `json:"retries"`
+	RateLimited         int        `json:"rate_limited"` // Respuestas 429
+	Failures            int        `json:"failures"`     // Llamadas que fallaron tras agotar los reintentos
+	Rejected            int        `json:"rejected"`     // Llamadas rechazadas con el circuito abierto
+	ConsecutiveFailures int        `json:"consecutive_failures"`
+	LastError           string     `json:"last_error,omitempty"`
+	OpenUntil           *time.Time `json:"open_until,omitempty"`
+}
@@ -0,0 +1,13 @@
+package domain
+
+import "time"
+
+// Watchlist es una lista con nombre de tickers de un usuario. El nombre es único por usuario.
+type Watchlist struct {
+	ID        uint      `json:"id" gorm:"primaryKey"`
+	UserID    string    `json:"user_id" gorm:"uniqueIndex:idx_watchlists_user_name"`
+	Name      string    `json:"name" gorm:"uniqueIndex:idx_watchlists_user_name"`
+	Tickers   []string  `json:"tickers" gorm:"serializer:json"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
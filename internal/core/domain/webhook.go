@@ -0,0 +1,63 @@
+package domain
+
+import "time"
+
+// Estados de una entrega de webhook.
+const (
+	DeliveryPending   = "pending"
+	DeliverySucceeded = "succeeded"
+	DeliveryFailed    = "failed" // Se agotaron los reintentos
+)
+
+// WebhookSubscription recibe los eventos de rating nuevos que pasan su filtro.
+// Filtros vacíos aceptan todos los eventos. Secret firma los payloads con HMAC-SHA256.
+type WebhookSubscription struct {
+	ID          uint         `json:"id" gorm:"primaryKey"`
+	UserID      string       `json:"user_id" gorm:"index"`
+	URL         string       `json:"url"`
+	Secret      string       `json:"secret,omitempty"` // Solo se devuelve al crear la suscripción
+	ActionTypes []ActionType `json:"action_types,omitempty" gorm:"serializer:json"`
+	Tickers     []string     `json:"tickers,omitempty" gorm:"serializer:json"`
+	Active      bool         `json:"active" gorm:"index"`
+	CreatedAt   time.Time    `json:"created_at"`
+}
+
+// Accepts indica si el evento pasa el filtro de la suscripción.
+func (s WebhookSubscription) Accepts(stock Stock, action ActionType) bool {
+	if len(s.ActionTypes) > 0 {
+		found := false
+		for _, wanted := range s.ActionTypes {
+			if wanted == action {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	if len(s.Tickers) > 0 {
+		for _, ticker := range s.Tickers {
+			if ticker == stock.Ticker {
+				return true
+			}
+		}
+		return false
+	}
+	return true
+}
+
+// WebhookDelivery es el registro de entrega de un evento a una suscripción.
+type WebhookDelivery struct {
+	ID             uint       `json:"id" gorm:"primaryKey"`
+	SubscriptionID uint       `json:"subscription_id" gorm:"index"`
+	OutboxEventID  uint       `json:"outbox_event_id" gorm:"index"`
+	Status         string     `json:"status" gorm:"index"`
+	Attempts       int        `json:"attempts"`
+	LastStatusCode int        `json:"last_status_code,omitempty"`
+	LastError      string     `json:"last_error,omitempty"`
+	NextAttemptAt  *time.Time `json:"next_attempt_at,omitempty" gorm:"index"`
+	DeliveredAt    *time.Time `json:"delivered_at,omitempty"`
+	CreatedAt      time.Time  `json:"created_at"`
+	UpdatedAt      time.Time  `json:"updated_at"`
+}
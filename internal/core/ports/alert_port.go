@@ -0,0 +1,25 @@
+package ports
+
+import "recommender/internal/core/domain"
+
+// StockObserver recibe cada evento de rating recién guardado.
+type StockObserver interface {
+	OnStockCreated(stock domain.Stock)
+}
+
+// AlertRepository guarda las reglas de cada usuario y las alertas disparadas.
+type AlertRepository interface {
+	CreateRule(rule *domain.AlertRule) error
+	DeleteRule(userID string, id uint) error
+	GetRule(userID string, id uint) (*domain.AlertRule, error)
+	ListRules(userID string) ([]domain.AlertRule, error)
+	ListActiveRules() ([]domain.AlertRule, error)
+	SaveEvent(event *domain.AlertEvent) error
+	ListEvents(userID string, limit, offset int) ([]domain.AlertEvent, error)
+}
+
+// Notifier entrega una alerta por un canal (log, webhook, SMTP...).
+type Notifier interface {
+	Name() string
+	Notify(alert domain.Alert) error
+}
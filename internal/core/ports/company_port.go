@@ -0,0 +1,9 @@
+package ports
+
+import "recommender/internal/core/domain"
+
+type CompanyRepository interface {
+	Save(company *domain.Company) error
+	GetBySymbol(symbol string) (*domain.Company, error)
+	SearchByNamePrefix(prefix string, limit, offset int) ([]domain.Company, error)
+}
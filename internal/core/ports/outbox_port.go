@@ -0,0 +1,20 @@
+package ports
+
+import "recommender/internal/core/domain"
+
+// OutboxReader lee el outbox en orden de secuencia, sin importar si ya fue despachado.
+type OutboxReader interface {
+	EventsAfter(afterID uint, limit int) ([]domain.OutboxEvent, error)
+	LatestEventID() (uint, error)
+}
+
+// OutboxCursorStore guarda el cursor de cada consumidor del outbox.
+type OutboxCursorStore interface {
+	// Cursor devuelve el último ID procesado; ok es false si el consumidor no tiene cursor.
+	Cursor(consumer string) (id uint, ok bool, err error)
+	// InitCursor crea el cursor en id; si ya existe no lo cambia.
+	InitCursor(consumer string, id uint) error
+	// AdvanceCursor mueve el cursor de from a to solo si sigue en from. Devuelve false si
+	// otro proceso ya lo movió, de modo que cada lote lo procesa un único proceso.
+	AdvanceCursor(consumer string, from, to uint) (bool, error)
+}
@@ -0,0 +1,22 @@
+package ports
+
+import (
+	"recommender/internal/core/domain"
+	"time"
+)
+
+// PriceFeed entrega cierres históricos ordenados por fecha ascendente.
+type PriceFeed interface {
+	GetCloses(ticker string, from, to time.Time) ([]domain.PriceBar, error)
+}
+
+type PriceRepository interface {
+	PriceFeed
+	SaveBars(bars []domain.PriceBar) error
+}
+
+// BrokerageWeigher decide cuánto pesa la opinión de cada corredora en la puntuación.
+type BrokerageWeigher interface {
+	Name() string
+	Weight(brokerage string) float64
+}
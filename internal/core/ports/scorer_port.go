@@ -0,0 +1,16 @@
+package ports
+
+import (
+	"recommender/internal/core/domain"
+	"time"
+)
+
+// Scorer calcula la puntuación de recomendación de un evento de rating.
+// Name identifica la estrategia en `?strategy=` y Version se reporta en la respuesta
+// para saber qué fórmula produjo cada lista. asOf es el instante de referencia
+// para medir la antigüedad del evento.
+type Scorer interface {
+	Name() string
+	Version() string
+	Score(stock domain.Stock, asOf time.Time) domain.ScoreBreakdown
+}
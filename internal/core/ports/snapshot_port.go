@@ -0,0 +1,16 @@
+package ports
+
+import (
+	"recommender/internal/core/domain"
+	"time"
+)
+
+type SnapshotRepository interface {
+	Save(snapshot *domain.RecommendationSnapshot) error
+	// List devuelve los snapshots más recientes primero; strategy vacía no filtra.
+	List(strategy string, limit, offset int) ([]domain.RecommendationSnapshot, error)
+	GetByID(id uint) (*domain.RecommendationSnapshot, error)
+	// GetLatestAt devuelve el último snapshot generado hasta `at` inclusive con esos
+	// parámetros (domain.SnapshotParamsKey).
+	GetLatestAt(paramsKey string, at time.Time) (*domain.RecommendationSnapshot, error)
+}
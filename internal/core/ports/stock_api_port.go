@@ -10,3 +10,22 @@ type APIResponse struct {
 type StockAPIClient interface {
 	FetchStocks(nextPage string) (*domain.APIResponse, error)
 }
+
+// StockProvider es un origen de datos con nombre. Cada proveedor convierte su propio
+// formato a domain.Stock y completa Source y ExternalID.
+type StockProvider interface {
+	StockAPIClient
+	Source() string
+}
+
+// UpstreamMonitor expone el estado de los proveedores (reintentos, 429, circuit breaker).
+type UpstreamMonitor interface {
+	UpstreamStats() []domain.UpstreamStats
+}
+
+// PageSkipper lo implementan los clientes que saben qué página sigue a una que falló,
+// para poder saltearla con la política domain.PageFailureSkip. ok es false si no se
+// conoce; un next vacío con ok significa que no quedan páginas.
+type PageSkipper interface {
+	NextPageAfter(failed string) (next string, ok bool)
+}
@@ -7,9 +7,31 @@ import (
 
 type StockRepository interface {
 	GetAll(limit, offset int) ([]domain.Stock, error)
+	GetFiltered(filter domain.StockFilter, limit, offset int) ([]domain.Stock, error)
 	Create(stock *domain.Stock) error
 	GetStockByTickerAndTime(ticker string, t time.Time) (*domain.Stock, error)
+	// GetStockByExternalID busca el evento por su proveedor y el ID que le dio ese proveedor.
+	GetStockByExternalID(source, externalID string) (*domain.Stock, error)
 	GetTopStocksByTarget(limit int) ([]domain.Stock, error)
 	GetStockByTicker(ticker string) (*domain.Stock, error) 
-	GetRecentStocks(limit int) ([]domain.Stock, error)
+	// GetRecentStocks devuelve los eventos desde `since`, del más reciente al más antiguo.
+	// Un limit <= 0 devuelve todos los eventos de la ventana.
+	GetRecentStocks(since time.Time, limit int) ([]domain.Stock, error)
+}
+
+// UnknownRatingCounter cuenta, entre los eventos guardados, las etiquetas crudas de
+// rating que al ingerirse quedaron como domain.RatingUnknown. Si el repositorio de stocks
+// lo implementa, el reporte de etiquetas desconocidas incluye lo ingerido por cualquier
+// proceso; si no, solo lo visto por este.
+type UnknownRatingCounter interface {
+	CountUnknownRatingLabels() (map[string]int, error)
+}
+
+// StockStreamer recorre eventos fila por fila sin cargar el resultado en memoria, para
+// exportaciones grandes. Un limit <= 0 recorre todos. Si el repositorio de stocks lo
+// implementa, StockService lo usa; si no, pagina con StockRepository.
+type StockStreamer interface {
+	StreamFiltered(filter domain.StockFilter, limit, offset int, fn func(domain.Stock) error) error
+	// StreamHistory recorre los eventos de un ticker del más antiguo al más reciente.
+	StreamHistory(ticker string, limit, offset int, fn func(domain.Stock) error) error
 }
@@ -23,6 +23,18 @@ func (m *mockStockRepository) GetAll(limit, offset int) ([]domain.Stock, error)
 	return m.stocks[offset:end], nil
 }
 
+func (m *mockStockRepository) GetFiltered(filter domain.StockFilter, limit, offset int) ([]domain.Stock, error) {
+	var stocks []domain.Stock
+	for _, s := range m.stocks {
+		for _, action := range filter.ActionTypes {
+			if s.ActionType == action {
+				stocks = append(stocks, s)
+			}
+		}
+	}
+	return stocks, nil
+}
+
 func (m *mockStockRepository) Create(stock *domain.Stock) error {
 	m.stocks = append(m.stocks, *stock)
 	return nil
@@ -37,6 +49,15 @@ func (m *mockStockRepository) GetStockByTickerAndTime(ticker string, t time.Time
 	return nil, errors.New("not found")
 }
 
+func (m *mockStockRepository) GetStockByExternalID(source, externalID string) (*domain.Stock, error) {
+	for _, s := range m.stocks {
+		if s.Source == source && s.ExternalID == externalID {
+			return &s, nil
+		}
+	}
+	return nil, errors.New("not found")
+}
+
 func (m *mockStockRepository) GetTopStocksByTarget(limit int) ([]domain.Stock, error) {
 	if limit > len(m.stocks) {
 		limit = len(m.stocks)
@@ -53,11 +74,17 @@ func (m *mockStockRepository) GetStockByTicker(ticker string) (*domain.Stock, er
 	return nil, errors.New("not found")
 }
 
-func (m *mockStockRepository) GetRecentStocks(limit int) ([]domain.Stock, error) {
-	if limit > len(m.stocks) {
-		limit = len(m.stocks)
+func (m *mockStockRepository) GetRecentStocks(since time.Time, limit int) ([]domain.Stock, error) {
+	var stocks []domain.Stock
+	for _, s := range m.stocks {
+		if !s.Time.Before(since) {
+			stocks = append(stocks, s)
+		}
 	}
-	return m.stocks[:limit], nil
+	if limit > 0 && limit < len(stocks) {
+		stocks = stocks[:limit]
+	}
+	return stocks, nil
 }
 
 func TestStockRepository_GetAll(t *testing.T) {
@@ -127,18 +154,27 @@ func TestStockRepository_GetTopStocksByTarget(t *testing.T) {
 }
 
 func TestStockRepository_GetRecentStocks(t *testing.T) {
+	now := time.Now()
 	repo := &mockStockRepository{
 		stocks: []domain.Stock{
-			{Ticker: "X"},
-			{Ticker: "Y"},
-			{Ticker: "Z"},
+			{Ticker: "X", Time: now},
+			{Ticker: "Y", Time: now},
+			{Ticker: "Z", Time: now.AddDate(0, 0, -60)},
 		},
 	}
-	recent, err := repo.GetRecentStocks(1)
+	recent, err := repo.GetRecentStocks(now.AddDate(0, 0, -30), 1)
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
 	if len(recent) != 1 || recent[0].Ticker != "X" {
 		t.Errorf("unexpected recent stocks: %+v", recent)
 	}
+
+	all, err := repo.GetRecentStocks(now.AddDate(0, 0, -30), 0)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(all) != 2 {
+		t.Errorf("expected every stock inside the window, got %+v", all)
+	}
 }
\ No newline at end of file
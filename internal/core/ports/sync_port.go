@@ -0,0 +1,6 @@
+package ports
+
+// SyncObserver recibe el aviso de que terminó una corrida de importación.
+type SyncObserver interface {
+	OnSyncCompleted(inserted int)
+}
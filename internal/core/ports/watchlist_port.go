@@ -0,0 +1,14 @@
+package ports
+
+import "recommender/internal/core/domain"
+
+// WatchlistRepository siempre opera dentro de las watchlists de un usuario: una
+// watchlist de otro usuario se comporta como inexistente.
+type WatchlistRepository interface {
+	Create(watchlist *domain.Watchlist) error
+	Update(watchlist *domain.Watchlist) error
+	Delete(userID string, id uint) error
+	GetByID(userID string, id uint) (*domain.Watchlist, error)
+	GetByName(userID, name string) (*domain.Watchlist, error)
+	ListByUser(userID string) ([]domain.Watchlist, error)
+}
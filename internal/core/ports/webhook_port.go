@@ -0,0 +1,31 @@
+package ports
+
+import (
+	"recommender/internal/core/domain"
+	"time"
+)
+
+// WebhookRepository guarda suscripciones, lee el outbox y lleva el registro de entregas.
+type WebhookRepository interface {
+	CreateSubscription(subscription *domain.WebhookSubscription) error
+	GetSubscription(userID string, id uint) (*domain.WebhookSubscription, error)
+	ListSubscriptions(userID string) ([]domain.WebhookSubscription, error)
+	DeleteSubscription(userID string, id uint) error
+	ListActiveSubscriptions() ([]domain.WebhookSubscription, error)
+
+	// PendingOutboxEvents devuelve los eventos aún no despachados en orden de ID.
+	PendingOutboxEvents(limit int) ([]domain.OutboxEvent, error)
+	GetOutboxEvent(id uint) (*domain.OutboxEvent, error)
+	// FanOut crea las entregas y marca el evento como despachado en una sola transacción.
+	FanOut(event domain.OutboxEvent, deliveries []domain.WebhookDelivery, at time.Time) error
+
+	DueDeliveries(now time.Time, limit int) ([]domain.WebhookDelivery, error)
+	GetDelivery(id uint) (*domain.WebhookDelivery, error)
+	SaveDelivery(delivery *domain.WebhookDelivery) error
+	ListDeliveries(subscriptionID uint, limit, offset int) ([]domain.WebhookDelivery, error)
+}
+
+// WebhookSender hace el POST de un payload ya firmado y devuelve el código HTTP.
+type WebhookSender interface {
+	Send(url string, headers map[string]string, body []byte) (int, error)
+}
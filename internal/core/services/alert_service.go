@@ -0,0 +1,338 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+	"time"
+
+	"recommender/internal/core/domain"
+	"recommender/internal/core/ports"
+
+	"gorm.io/gorm"
+)
+
+var (
+	ErrAlertRuleNotFound = errors.New("alert rule not found")
+	ErrInvalidAlertRule  = errors.New("invalid alert rule")
+)
+
+// AlertOutboxConsumer es el nombre del cursor de las alertas en el outbox.
+const AlertOutboxConsumer = "alerts"
+
+// AlertService evalúa las reglas activas contra los eventos de rating recién
+// ingeridos, registra las coincidencias y las entrega por el notificador de la regla.
+//
+// Los eventos se leen del outbox con un cursor guardado en la base, como los webhooks y
+// el stream: se evalúan los stocks guardados por cualquier proceso (serve, sync, import),
+// y un reinicio retoma donde quedó el cursor. Igual que en el stream, el cursor solo
+// avanza sobre eventos con más de safetyLag de antigüedad para no saltear un ID menor
+// que todavía no se confirmó.
+type AlertService struct {
+	repository ports.AlertRepository
+	watchlists ports.WatchlistRepository
+	notifiers  map[string]ports.Notifier
+	outbox     ports.OutboxReader
+	cursors    ports.OutboxCursorStore
+	safetyLag  time.Duration
+	now        func() time.Time
+}
+
+func NewAlertService(repo ports.AlertRepository, watchlists ports.WatchlistRepository) *AlertService {
+	return &AlertService{
+		repository: repo,
+		watchlists: watchlists,
+		notifiers:  map[string]ports.Notifier{},
+		safetyLag:  defaultStreamSafetyLag,
+		now:        time.Now,
+	}
+}
+
+// WithOutbox conecta el outbox del que se leen los eventos y el almacén del cursor.
+func (s *AlertService) WithOutbox(outbox ports.OutboxReader, cursors ports.OutboxCursorStore) *AlertService {
+	s.outbox = outbox
+	s.cursors = cursors
+	return s
+}
+
+// WithSafetyLag cambia la espera antes de evaluar un evento del outbox.
+func (s *AlertService) WithSafetyLag(lag time.Duration) *AlertService {
+	s.safetyLag = lag
+	return s
+}
+
+// WithClock reemplaza el reloj con el que se mide la antigüedad de los eventos.
+func (s *AlertService) WithClock(now func() time.Time) *AlertService {
+	s.now = now
+	return s
+}
+
+// RegisterNotifier habilita un canal de entrega; reemplaza uno existente con el mismo nombre.
+func (s *AlertService) RegisterNotifier(notifier ports.Notifier) *AlertService {
+	s.notifiers[notifier.Name()] = notifier
+	return s
+}
+
+// Notifiers lista los canales disponibles para las reglas.
+func (s *AlertService) Notifiers() []string {
+	names := make([]string, 0, len(s.notifiers))
+	for name := range s.notifiers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func (s *AlertService) CreateRule(rule *domain.AlertRule) error {
+	rule.Name = strings.TrimSpace(rule.Name)
+	rule.Tickers = normalizeTickers(rule.Tickers)
+	switch {
+	case rule.Name == "":
+		return fmt.Errorf("%w: name is required", ErrInvalidAlertRule)
+	case !rule.HasConditions():
+		return fmt.Errorf("%w: at least one condition is required", ErrInvalidAlertRule)
+	case s.notifiers[rule.Notifier] == nil:
+		return fmt.Errorf("%w: unknown notifier '%s'", ErrInvalidAlertRule, rule.Notifier)
+	case rule.Notifier != domain.NotifierLog && strings.TrimSpace(rule.Target) == "":
+		return fmt.Errorf("%w: target is required for notifier '%s'", ErrInvalidAlertRule, rule.Notifier)
+	}
+	for _, action := range rule.ActionTypes {
+		if !action.Valid() {
+			return fmt.Errorf("%w: unknown action type '%s'", ErrInvalidAlertRule, action)
+		}
+	}
+	if rule.WatchlistID != nil {
+		if _, err := s.watchlists.GetByID(rule.UserID, *rule.WatchlistID); err != nil {
+			return fmt.Errorf("%w: watchlist %d not found", ErrInvalidAlertRule, *rule.WatchlistID)
+		}
+	}
+	return s.repository.CreateRule(rule)
+}
+
+func (s *AlertService) ListRules(userID string) ([]domain.AlertRule, error) {
+	return s.repository.ListRules(userID)
+}
+
+func (s *AlertService) GetRule(userID string, id uint) (*domain.AlertRule, error) {
+	rule, err := s.repository.GetRule(userID, id)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrAlertRuleNotFound
+	}
+	return rule, err
+}
+
+func (s *AlertService) DeleteRule(userID string, id uint) error {
+	err := s.repository.DeleteRule(userID, id)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return ErrAlertRuleNotFound
+	}
+	return err
+}
+
+func (s *AlertService) ListEvents(userID string, limit, offset int) ([]domain.AlertEvent, error) {
+	return s.repository.ListEvents(userID, limit, offset)
+}
+
+// Run evalúa el outbox periódicamente hasta que ctx se cancele.
+func (s *AlertService) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		if _, err := s.Poll(); err != nil {
+			log.Printf("⚠ Error evaluando alertas del outbox: %v", err)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Poll evalúa los eventos del outbox posteriores al cursor contra las reglas activas
+// y entrega las alertas. Devuelve cuántas se dispararon.
+//
+// Sin cursor guardado arranca desde el último evento, sin alertar sobre el historial.
+// Cada lote se reclama moviendo el cursor antes de entregarlo: si otro proceso lo movió
+// primero, el lote es suyo. Las alertas se entregan a lo sumo una vez, como hasta ahora
+// (no hay reintentos). Si las reglas no cargan, el cursor no se mueve.
+func (s *AlertService) Poll() (int, error) {
+	if s.outbox == nil || s.cursors == nil {
+		return 0, nil
+	}
+	last, ok, err := s.cursors.Cursor(AlertOutboxConsumer)
+	if err != nil {
+		return 0, err
+	}
+	if !ok {
+		latest, err := s.outbox.LatestEventID()
+		if err != nil {
+			return 0, err
+		}
+		return 0, s.cursors.InitCursor(AlertOutboxConsumer, latest)
+	}
+
+	fired := 0
+	for {
+		events, err := s.outbox.EventsAfter(last, streamBatchSize)
+		if err != nil {
+			return fired, err
+		}
+		batch := settledEvents(events, s.now().Add(-s.safetyLag))
+		if len(batch) == 0 {
+			return fired, nil
+		}
+
+		rules, tickers, err := s.activeRules()
+		if err != nil {
+			return fired, fmt.Errorf("loading alert rules: %w", err)
+		}
+		next := batch[len(batch)-1].ID
+		claimed, err := s.cursors.AdvanceCursor(AlertOutboxConsumer, last, next)
+		if err != nil || !claimed {
+			return fired, err // Otro proceso ya evaluó este lote
+		}
+
+		for _, event := range batch {
+			stockEvent, ok := toStockEvent(event)
+			if !ok {
+				continue
+			}
+			for i, rule := range rules {
+				if ruleMatches(rule, tickers[i], stockEvent.Stock) {
+					s.fire(rule, stockEvent.Stock)
+					fired++
+				}
+			}
+		}
+
+		last = next
+		if len(batch) < len(events) || len(events) < streamBatchSize {
+			return fired, nil
+		}
+	}
+}
+
+// activeRules carga las reglas activas una vez por lote, con los tickers de su watchlist.
+func (s *AlertService) activeRules() ([]domain.AlertRule, [][]string, error) {
+	rules, err := s.repository.ListActiveRules()
+	if err != nil {
+		return nil, nil, err
+	}
+	active := make([]domain.AlertRule, 0, len(rules))
+	tickers := make([][]string, 0, len(rules))
+	for _, rule := range rules {
+		if ruleTickers, ok := s.ruleTickers(rule); ok {
+			active = append(active, rule)
+			tickers = append(tickers, ruleTickers)
+		}
+	}
+	return active, tickers, nil
+}
+
+// settledEvents devuelve el prefijo de events con más de safetyLag de antigüedad.
+func settledEvents(events []domain.OutboxEvent, settled time.Time) []domain.OutboxEvent {
+	for i, event := range events {
+		if event.CreatedAt.After(settled) {
+			return events[:i]
+		}
+	}
+	return events
+}
+
+// ruleTickers resuelve los tickers de la regla, incluidos los de su watchlist. Devuelve
+// false si la regla no puede coincidir con ningún evento del lote.
+func (s *AlertService) ruleTickers(rule domain.AlertRule) ([]string, bool) {
+	if rule.WatchlistID == nil {
+		return rule.Tickers, true
+	}
+	watchlist, err := s.watchlists.GetByID(rule.UserID, *rule.WatchlistID)
+	if err != nil {
+		log.Printf("⚠ Regla %d: watchlist %d no disponible: %v", rule.ID, *rule.WatchlistID, err)
+		return nil, false
+	}
+	tickers := append(append([]string{}, rule.Tickers...), watchlist.Tickers...)
+	return tickers, len(tickers) > 0 // Watchlist vacía: no hay tickers que vigilar
+}
+
+// ruleMatches evalúa las condiciones de la regla; tickers ya incluye los de la watchlist.
+func ruleMatches(rule domain.AlertRule, tickers []string, stock domain.Stock) bool {
+	if len(tickers) > 0 && !containsString(tickers, strings.ToUpper(stock.Ticker)) {
+		return false
+	}
+	if len(rule.ActionTypes) > 0 {
+		action := actionTypeOf(stock)
+		found := false
+		for _, wanted := range rule.ActionTypes {
+			if wanted == action {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	if rule.Brokerage != "" && !strings.Contains(strings.ToLower(stock.Brokerage), strings.ToLower(rule.Brokerage)) {
+		return false
+	}
+	if rule.MinTargetChangePct != 0 {
+		if stock.TargetFrom <= 0 {
+			return false
+		}
+		change := (stock.TargetTo - stock.TargetFrom) / stock.TargetFrom * 100
+		if rule.MinTargetChangePct > 0 && change < rule.MinTargetChangePct {
+			return false
+		}
+		if rule.MinTargetChangePct < 0 && change > rule.MinTargetChangePct {
+			return false
+		}
+	}
+	return true
+}
+
+// fire registra la alerta con el resultado de la entrega.
+func (s *AlertService) fire(rule domain.AlertRule, stock domain.Stock) {
+	event := domain.AlertEvent{
+		RuleID:   rule.ID,
+		UserID:   rule.UserID,
+		StockID:  stock.ID,
+		Ticker:   stock.Ticker,
+		Message:  alertMessage(rule, stock),
+		Notifier: rule.Notifier,
+	}
+
+	notifier := s.notifiers[rule.Notifier]
+	if notifier == nil {
+		event.DeliveryError = fmt.Sprintf("notifier '%s' not configured", rule.Notifier)
+	} else if err := notifier.Notify(domain.Alert{Rule: rule, Stock: stock, Event: event}); err != nil {
+		event.DeliveryError = err.Error()
+	} else {
+		event.Delivered = true
+	}
+	if event.DeliveryError != "" {
+		log.Printf("⚠ Error entregando alerta '%s' para %s: %s", rule.Name, stock.Ticker, event.DeliveryError)
+	}
+
+	if err := s.repository.SaveEvent(&event); err != nil {
+		log.Printf("⚠ Error guardando alerta '%s' para %s: %v", rule.Name, stock.Ticker, err)
+	}
+}
+
+func alertMessage(rule domain.AlertRule, stock domain.Stock) string {
+	return fmt.Sprintf("[%s] %s %s %s: %s -> %s, target $%.2f -> $%.2f",
+		rule.Name, stock.Ticker, stock.Action, stock.Brokerage,
+		stock.RatingFrom, stock.RatingTo, stock.TargetFrom, stock.TargetTo)
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
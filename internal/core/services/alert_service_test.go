@@ -0,0 +1,293 @@
+package services
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"recommender/internal/core/domain"
+
+	"gorm.io/gorm"
+)
+
+type mockAlertRepository struct {
+	rules     []domain.AlertRule
+	events    []domain.AlertEvent
+	ruleLoads int
+	failLoads bool
+}
+
+func (m *mockAlertRepository) CreateRule(rule *domain.AlertRule) error {
+	rule.ID = uint(len(m.rules) + 1)
+	m.rules = append(m.rules, *rule)
+	return nil
+}
+
+func (m *mockAlertRepository) DeleteRule(userID string, id uint) error {
+	for i, rule := range m.rules {
+		if rule.ID == id && rule.UserID == userID {
+			m.rules = append(m.rules[:i], m.rules[i+1:]...)
+			return nil
+		}
+	}
+	return gorm.ErrRecordNotFound
+}
+
+func (m *mockAlertRepository) GetRule(userID string, id uint) (*domain.AlertRule, error) {
+	for _, rule := range m.rules {
+		if rule.ID == id && rule.UserID == userID {
+			return &rule, nil
+		}
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+func (m *mockAlertRepository) ListRules(userID string) ([]domain.AlertRule, error) {
+	var rules []domain.AlertRule
+	for _, rule := range m.rules {
+		if rule.UserID == userID {
+			rules = append(rules, rule)
+		}
+	}
+	return rules, nil
+}
+
+func (m *mockAlertRepository) ListActiveRules() ([]domain.AlertRule, error) {
+	m.ruleLoads++
+	if m.failLoads {
+		return nil, errors.New("connection refused")
+	}
+	var rules []domain.AlertRule
+	for _, rule := range m.rules {
+		if rule.Active {
+			rules = append(rules, rule)
+		}
+	}
+	return rules, nil
+}
+
+func (m *mockAlertRepository) SaveEvent(event *domain.AlertEvent) error {
+	event.ID = uint(len(m.events) + 1)
+	m.events = append(m.events, *event)
+	return nil
+}
+
+func (m *mockAlertRepository) ListEvents(userID string, limit, offset int) ([]domain.AlertEvent, error) {
+	return m.events, nil
+}
+
+type recordingNotifier struct {
+	name string
+	err  error
+	sent []domain.Alert
+}
+
+func (n *recordingNotifier) Name() string { return n.name }
+
+func (n *recordingNotifier) Notify(alert domain.Alert) error {
+	n.sent = append(n.sent, alert)
+	return n.err
+}
+
+func TestRuleMatches(t *testing.T) {
+	stock := domain.Stock{Ticker: "AAPL", Brokerage: "The Goldman Sachs Group", Action: "target raised by", TargetFrom: 100, TargetTo: 125}
+
+	cases := []struct {
+		name    string
+		rule    domain.AlertRule
+		tickers []string
+		want    bool
+	}{
+		{"ticker in list", domain.AlertRule{}, []string{"AAPL"}, true},
+		{"ticker not in list", domain.AlertRule{}, []string{"MSFT"}, false},
+		{"action matches", domain.AlertRule{ActionTypes: []domain.ActionType{domain.ActionTargetRaised}}, nil, true},
+		{"action does not match", domain.AlertRule{ActionTypes: []domain.ActionType{domain.ActionDowngrade}}, nil, false},
+		{"brokerage partial match", domain.AlertRule{Brokerage: "goldman"}, nil, true},
+		{"target raised above threshold", domain.AlertRule{MinTargetChangePct: 20}, nil, true},
+		{"target raised below threshold", domain.AlertRule{MinTargetChangePct: 30}, nil, false},
+		{"target lowered threshold", domain.AlertRule{MinTargetChangePct: -10}, nil, false},
+	}
+	for _, tc := range cases {
+		if got := ruleMatches(tc.rule, tc.tickers, stock); got != tc.want {
+			t.Errorf("%s: expected %v, got %v", tc.name, tc.want, got)
+		}
+	}
+}
+
+// mockOutboxCursorStore guarda los cursores en memoria; varios AlertService pueden
+// compartirlo como si fueran procesos distintos sobre la misma base.
+type mockOutboxCursorStore struct {
+	cursors map[string]uint
+}
+
+func newMockOutboxCursorStore() *mockOutboxCursorStore {
+	return &mockOutboxCursorStore{cursors: map[string]uint{}}
+}
+
+func (m *mockOutboxCursorStore) Cursor(consumer string) (uint, bool, error) {
+	id, ok := m.cursors[consumer]
+	return id, ok, nil
+}
+
+func (m *mockOutboxCursorStore) InitCursor(consumer string, id uint) error {
+	if _, ok := m.cursors[consumer]; !ok {
+		m.cursors[consumer] = id
+	}
+	return nil
+}
+
+func (m *mockOutboxCursorStore) AdvanceCursor(consumer string, from, to uint) (bool, error) {
+	if m.cursors[consumer] != from {
+		return false, nil
+	}
+	m.cursors[consumer] = to
+	return true, nil
+}
+
+func TestAlertService_FiresOnNewStocks(t *testing.T) {
+	watchlists := newMockWatchlistRepository()
+	watchlist := &domain.Watchlist{UserID: "alice", Name: "mine", Tickers: []string{"AAPL"}}
+	watchlists.Create(watchlist)
+
+	outbox := &mockOutboxReader{}
+	outbox.add(domain.Stock{Ticker: "AAPL", Action: "downgraded by"}) // Anterior al primer sondeo
+	cursors := newMockOutboxCursorStore()
+	alerts := &mockAlertRepository{}
+	webhook := &recordingNotifier{name: domain.NotifierWebhook}
+	failing := &recordingNotifier{name: domain.NotifierSMTP, err: errors.New("connection refused")}
+	service := NewAlertService(alerts, watchlists).RegisterNotifier(webhook).RegisterNotifier(failing).
+		WithOutbox(outbox, cursors)
+
+	rules := []*domain.AlertRule{
+		{UserID: "alice", Name: "watchlist downgrades", WatchlistID: &watchlist.ID, ActionTypes: []domain.ActionType{domain.ActionDowngrade}, Notifier: domain.NotifierWebhook, Target: "http://hook", Active: true},
+		{UserID: "bob", Name: "goldman", Brokerage: "Goldman", Notifier: domain.NotifierSMTP, Target: "bob@example.com", Active: true},
+		{UserID: "bob", Name: "paused", Tickers: []string{"AAPL"}, Notifier: domain.NotifierWebhook, Target: "http://hook", Active: false},
+	}
+	for _, rule := range rules {
+		if err := service.CreateRule(rule); err != nil {
+			t.Fatalf("unexpected error creating rule: %v", err)
+		}
+	}
+
+	// El primer sondeo solo fija el cursor: el historial no dispara alertas
+	if fired, err := service.Poll(); err != nil || fired != 0 || cursors.cursors[AlertOutboxConsumer] != 1 {
+		t.Fatalf("expected the cursor to start at the latest event, got %d fired, cursor %d, err %v", fired, cursors.cursors[AlertOutboxConsumer], err)
+	}
+
+	// Eventos guardados por cualquier proceso, no solo por este
+	outbox.add(domain.Stock{Ticker: "AAPL", Brokerage: "Morgan Stanley", Action: "downgraded by"})
+	outbox.add(domain.Stock{Ticker: "MSFT", Brokerage: "Goldman Sachs", Action: "initiated by"})
+	if fired, err := service.Poll(); err != nil || fired != 2 {
+		t.Errorf("expected 2 alerts fired, got %d (err %v)", fired, err)
+	}
+
+	if len(webhook.sent) != 1 || webhook.sent[0].Stock.Ticker != "AAPL" {
+		t.Errorf("expected one webhook alert for AAPL, got %+v", webhook.sent)
+	}
+	if len(alerts.events) != 2 {
+		t.Fatalf("expected 2 alert events, got %+v", alerts.events)
+	}
+	if !alerts.events[0].Delivered || alerts.events[1].Delivered || alerts.events[1].DeliveryError == "" {
+		t.Errorf("unexpected delivery results: %+v", alerts.events)
+	}
+
+	// Un reinicio (u otro proceso) retoma desde el cursor guardado sin repetir alertas
+	restarted := NewAlertService(alerts, watchlists).RegisterNotifier(webhook).RegisterNotifier(failing).
+		WithOutbox(outbox, cursors)
+	if fired, _ := restarted.Poll(); fired != 0 || len(alerts.events) != 2 {
+		t.Errorf("expected no alerts to be repeated after a restart, got %d", fired)
+	}
+}
+
+func TestAlertService_CreateRuleValidation(t *testing.T) {
+	service := NewAlertService(&mockAlertRepository{}, newMockWatchlistRepository()).
+		RegisterNotifier(&recordingNotifier{name: domain.NotifierLog})
+	missing := uint(9)
+
+	invalid := []domain.AlertRule{
+		{Name: "", Tickers: []string{"AAPL"}, Notifier: domain.NotifierLog},
+		{Name: "everything", Notifier: domain.NotifierLog},
+		{Name: "no notifier", Tickers: []string{"AAPL"}, Notifier: domain.NotifierWebhook},
+		{Name: "bad action", ActionTypes: []domain.ActionType{"sideways"}, Notifier: domain.NotifierLog},
+		{Name: "missing watchlist", WatchlistID: &missing, Notifier: domain.NotifierLog},
+	}
+	for _, rule := range invalid {
+		if err := service.CreateRule(&rule); !errors.Is(err, ErrInvalidAlertRule) {
+			t.Errorf("%s: expected ErrInvalidAlertRule, got %v", rule.Name, err)
+		}
+	}
+}
+
+// countingWatchlistRepository cuenta las lecturas de watchlists.
+type countingWatchlistRepository struct {
+	*mockWatchlistRepository
+	reads int
+}
+
+func (c *countingWatchlistRepository) GetByID(userID string, id uint) (*domain.Watchlist, error) {
+	c.reads++
+	return c.mockWatchlistRepository.GetByID(userID, id)
+}
+
+func TestAlertService_EvaluatesOutboxBatchOnce(t *testing.T) {
+	watchlists := &countingWatchlistRepository{mockWatchlistRepository: newMockWatchlistRepository()}
+	watchlist := &domain.Watchlist{UserID: "alice", Name: "mine", Tickers: []string{"AAPL", "TSLA"}}
+	watchlists.Create(watchlist)
+
+	outbox := &mockOutboxReader{}
+	cursors := newMockOutboxCursorStore()
+	cursors.InitCursor(AlertOutboxConsumer, 0)
+	alerts := &mockAlertRepository{failLoads: true}
+	webhook := &recordingNotifier{name: domain.NotifierWebhook}
+	service := NewAlertService(alerts, watchlists).RegisterNotifier(webhook).WithOutbox(outbox, cursors)
+	service.CreateRule(&domain.AlertRule{UserID: "alice", Name: "watchlist", WatchlistID: &watchlist.ID, Notifier: domain.NotifierWebhook, Target: "http://hook", Active: true})
+	watchlists.reads = 0
+
+	for _, ticker := range []string{"AAPL", "MSFT", "TSLA"} {
+		outbox.add(domain.Stock{Ticker: ticker})
+	}
+
+	// Si las reglas no cargan, el cursor no se mueve y el lote se evalúa en el próximo intento
+	if fired, err := service.Poll(); err == nil || fired != 0 || cursors.cursors[AlertOutboxConsumer] != 0 {
+		t.Errorf("expected the cursor to stay while rules fail to load, got %d fired, err %v", fired, err)
+	}
+	alerts.failLoads = false
+	if fired, err := service.Poll(); err != nil || fired != 2 {
+		t.Errorf("expected 2 alerts, got %d (err %v)", fired, err)
+	}
+	if alerts.ruleLoads != 2 || watchlists.reads != 1 {
+		t.Errorf("expected one rule and watchlist load per batch, got %d and %d", alerts.ruleLoads, watchlists.reads)
+	}
+	if len(webhook.sent) != 2 || webhook.sent[0].Stock.Ticker != "AAPL" || webhook.sent[1].Stock.Ticker != "TSLA" {
+		t.Errorf("unexpected alerts: %+v", webhook.sent)
+	}
+}
+
+func TestAlertService_WaitsForSafetyLag(t *testing.T) {
+	now := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+	outbox := &mockOutboxReader{}
+	cursors := newMockOutboxCursorStore()
+	cursors.InitCursor(AlertOutboxConsumer, 0)
+	alerts := &mockAlertRepository{}
+	webhook := &recordingNotifier{name: domain.NotifierWebhook}
+	service := NewAlertService(alerts, newMockWatchlistRepository()).RegisterNotifier(webhook).
+		WithOutbox(outbox, cursors).
+		WithSafetyLag(2 * time.Second).
+		WithClock(func() time.Time { return now })
+	service.CreateRule(&domain.AlertRule{UserID: "alice", Name: "all", Tickers: []string{"AAPL", "MSFT"}, Notifier: domain.NotifierWebhook, Target: "http://hook", Active: true})
+
+	// El ID 2 confirmó primero; el 1 todavía puede estar en una transacción abierta
+	outbox.commit(2, now.Add(-time.Second), "MSFT")
+	if fired, _ := service.Poll(); fired != 0 || cursors.cursors[AlertOutboxConsumer] != 0 {
+		t.Fatalf("expected recent events to wait for the safety lag, got %d fired", fired)
+	}
+
+	outbox.commit(1, now.Add(-time.Second), "AAPL")
+	now = now.Add(5 * time.Second)
+	if fired, _ := service.Poll(); fired != 2 || cursors.cursors[AlertOutboxConsumer] != 2 {
+		t.Fatalf("expected both events once settled, got %d fired", fired)
+	}
+	if webhook.sent[0].Stock.Ticker != "AAPL" || webhook.sent[1].Stock.Ticker != "MSFT" {
+		t.Errorf("expected alerts in sequence order, got %+v", webhook.sent)
+	}
+}
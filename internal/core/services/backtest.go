@@ -0,0 +1,195 @@
+package services
+
+import (
+	"fmt"
+	"math"
+	"time"
+
+	"recommender/internal/core/domain"
+	"recommender/internal/core/ports"
+)
+
+// BacktestService reproduce los eventos guardados día a día, arma el top-N de cada
+// estrategia con la información disponible en esa fecha y evalúa el retorno futuro
+// de los picks contra una serie de precios.
+type BacktestService struct {
+	stocks  ports.StockRepository
+	prices  ports.PriceFeed
+	scorers *ScorerRegistry
+}
+
+func NewBacktestService(stocks ports.StockRepository, prices ports.PriceFeed, scorers *ScorerRegistry) *BacktestService {
+	return &BacktestService{stocks: stocks, prices: prices, scorers: scorers}
+}
+
+func validateBacktestConfig(cfg domain.BacktestConfig) error {
+	switch {
+	case cfg.Start.IsZero() || cfg.End.IsZero() || cfg.End.Before(cfg.Start):
+		return fmt.Errorf("backtest: rango de fechas inválido")
+	case cfg.StepDays < 1 || cfg.HorizonDays < 1 || cfg.TopN < 1 || cfg.LookbackDays < 1:
+		return fmt.Errorf("backtest: step, horizon, top y lookback deben ser positivos")
+	case len(cfg.Strategies) == 0:
+		return fmt.Errorf("backtest: no se indicaron estrategias")
+	}
+	return nil
+}
+
+// Run ejecuta el backtest para cada estrategia de cfg.Strategies.
+func (s *BacktestService) Run(cfg domain.BacktestConfig) (*domain.BacktestReport, error) {
+	if err := validateBacktestConfig(cfg); err != nil {
+		return nil, err
+	}
+
+	scorers := make([]ports.Scorer, 0, len(cfg.Strategies))
+	for _, name := range cfg.Strategies {
+		scorer, err := s.scorers.Get(name)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %s", err, name)
+		}
+		scorers = append(scorers, scorer)
+	}
+
+	events, err := s.stocks.GetRecentStocks(cfg.Start.AddDate(0, 0, -cfg.LookbackDays), 0)
+	if err != nil {
+		return nil, err
+	}
+
+	series := &priceCache{feed: s.prices, from: cfg.Start.Add(-basePriceLookback), to: cfg.End.AddDate(0, 0, cfg.HorizonDays), bars: map[string][]domain.PriceBar{}}
+	report := &domain.BacktestReport{Config: cfg}
+	for _, scorer := range scorers {
+		result, err := s.runStrategy(cfg, scorer, events, series)
+		if err != nil {
+			return nil, err
+		}
+		report.Results = append(report.Results, result)
+	}
+	return report, nil
+}
+
+func (s *BacktestService) runStrategy(cfg domain.BacktestConfig, scorer ports.Scorer, events []domain.Stock, series *priceCache) (domain.BacktestResult, error) {
+	result := domain.BacktestResult{Strategy: scorer.Name(), ScorerVersion: scorer.Version()}
+	params := domain.RecommendationParams{Limit: cfg.TopN, LookbackDays: cfg.LookbackDays, GroupBy: cfg.GroupBy}
+
+	var (
+		returnSum, turnoverSum float64
+		hits, turnoverPeriods  int
+		previous               map[string]bool
+		equity, peak           = 1.0, 1.0
+	)
+
+	for date := cfg.Start; !date.After(cfg.End); date = date.AddDate(0, 0, cfg.StepDays) {
+		window := eventsBetween(events, date.AddDate(0, 0, -cfg.LookbackDays), date)
+		picks := buildRecommendations(window, params, scorer, date, nil)
+		result.Periods++
+
+		current := map[string]bool{}
+		var periodSum float64
+		periodPicks := 0
+		for _, pick := range picks {
+			current[pick.Ticker] = true
+			ret, ok, err := series.forwardReturn(pick.Ticker, date, cfg.HorizonDays)
+			if err != nil {
+				return result, err
+			}
+			if !ok {
+				result.SkippedPicks++
+				continue
+			}
+			result.Picks++
+			periodPicks++
+			periodSum += ret
+			returnSum += ret
+			if ret > 0 {
+				hits++
+			}
+		}
+
+		if previous != nil && len(current) > 0 {
+			entered := 0
+			for ticker := range current {
+				if !previous[ticker] {
+					entered++
+				}
+			}
+			turnoverSum += float64(entered) / float64(len(current))
+			turnoverPeriods++
+		}
+		previous = current
+
+		// La curva de capital escala el retorno del período al paso entre rebalanceos
+		// para no componer varias veces horizontes que se solapan.
+		if periodPicks > 0 {
+			periodReturn := periodSum / float64(periodPicks)
+			equity *= math.Pow(1+periodReturn, float64(cfg.StepDays)/float64(cfg.HorizonDays))
+		}
+		if equity > peak {
+			peak = equity
+		}
+		if drawdown := (peak - equity) / peak; drawdown > result.MaxDrawdown {
+			result.MaxDrawdown = drawdown
+		}
+	}
+
+	if result.Picks > 0 {
+		result.HitRate = roundTo(float64(hits)/float64(result.Picks), 4)
+		result.AvgReturn = roundTo(returnSum/float64(result.Picks), 4)
+	}
+	if turnoverPeriods > 0 {
+		result.Turnover = roundTo(turnoverSum/float64(turnoverPeriods), 4)
+	}
+	result.MaxDrawdown = roundTo(result.MaxDrawdown, 4)
+	return result, nil
+}
+
+// eventsBetween devuelve los eventos publicados en (from, to].
+func eventsBetween(events []domain.Stock, from, to time.Time) []domain.Stock {
+	var window []domain.Stock
+	for _, event := range events {
+		if event.Time.After(from) && !event.Time.After(to) {
+			window = append(window, event)
+		}
+	}
+	return window
+}
+
+// priceCache pide a la fuente la serie completa de cada ticker una sola vez.
+type priceCache struct {
+	feed     ports.PriceFeed
+	from, to time.Time
+	bars     map[string][]domain.PriceBar
+}
+
+func (c *priceCache) series(ticker string) ([]domain.PriceBar, error) {
+	if bars, ok := c.bars[ticker]; ok {
+		return bars, nil
+	}
+	bars, err := c.feed.GetCloses(ticker, c.from, c.to)
+	if err != nil {
+		return nil, err
+	}
+	c.bars[ticker] = bars
+	return bars, nil
+}
+
+// forwardReturn compra al último cierre conocido en `date` y vende al último cierre
+// dentro del horizonte. ok es false si falta alguno de los dos precios.
+func (c *priceCache) forwardReturn(ticker string, date time.Time, horizonDays int) (float64, bool, error) {
+	bars, err := c.series(ticker)
+	if err != nil {
+		return 0, false, err
+	}
+	entry, ok := closeOnOrBefore(bars, date)
+	if !ok || date.Sub(entry.Date) > basePriceLookback {
+		return 0, false, nil
+	}
+	exit, ok := closeOnOrBefore(bars, date.AddDate(0, 0, horizonDays))
+	if !ok || !exit.Date.After(date) {
+		return 0, false, nil
+	}
+	return exit.Close/entry.Close - 1, true, nil
+}
+
+func roundTo(value float64, decimals int) float64 {
+	factor := math.Pow(10, float64(decimals))
+	return math.Round(value*factor) / factor
+}
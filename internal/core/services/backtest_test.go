@@ -0,0 +1,70 @@
+package services
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"recommender/internal/core/domain"
+)
+
+func TestBacktestService_Run(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	eventTime := start.Add(14 * time.Hour)
+
+	stocks := &mockStockRepository{stocks: []domain.Stock{
+		{Ticker: "UP", Brokerage: "Good Research", Action: "upgraded by", RatingFrom: "Hold", RatingTo: "Buy", TargetFrom: 100, TargetTo: 120, Time: eventTime},
+		{Ticker: "DOWN", Brokerage: "Good Research", Action: "downgraded by", RatingFrom: "Buy", RatingTo: "Sell", TargetFrom: 100, TargetTo: 80, Time: eventTime},
+	}}
+	var bars []domain.PriceBar
+	bars = append(bars, dailyBars("UP", start, 100, 104, 108, 111, 109)...)
+	bars = append(bars, dailyBars("DOWN", start, 100, 97, 95, 92, 93)...)
+
+	service := NewBacktestService(stocks, &mockPriceFeed{bars: bars}, NewDefaultScorerRegistry())
+	report, err := service.Run(domain.BacktestConfig{
+		Start:        start.AddDate(0, 0, 1),
+		End:          start.AddDate(0, 0, 3),
+		StepDays:     2,
+		HorizonDays:  2,
+		TopN:         1,
+		LookbackDays: 30,
+		GroupBy:      domain.GroupByTicker,
+		Strategies:   []string{"default", "upside"},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(report.Results) != 2 {
+		t.Fatalf("expected one result per strategy, got %+v", report.Results)
+	}
+
+	result := report.Results[0]
+	if result.Strategy != "default" || result.Periods != 2 || result.Picks != 2 {
+		t.Fatalf("unexpected result: %+v", result)
+	}
+	if result.HitRate != 0.5 {
+		t.Errorf("expected hit rate 0.5, got %v", result.HitRate)
+	}
+	if result.AvgReturn != 0.0246 {
+		t.Errorf("expected avg return 0.0246, got %v", result.AvgReturn)
+	}
+	if result.MaxDrawdown != 0.018 {
+		t.Errorf("expected max drawdown 0.018, got %v", result.MaxDrawdown)
+	}
+	if result.Turnover != 0 {
+		t.Errorf("expected no turnover when the same pick repeats, got %v", result.Turnover)
+	}
+}
+
+func TestBacktestService_Run_UnknownStrategy(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	service := NewBacktestService(&mockStockRepository{}, &mockPriceFeed{}, NewDefaultScorerRegistry())
+
+	_, err := service.Run(domain.BacktestConfig{
+		Start: start, End: start, StepDays: 1, HorizonDays: 1, TopN: 1, LookbackDays: 1,
+		Strategies: []string{"missing"},
+	})
+	if !errors.Is(err, ErrUnknownStrategy) {
+		t.Errorf("expected ErrUnknownStrategy, got %v", err)
+	}
+}
@@ -0,0 +1,266 @@
+package services
+
+import (
+	"context"
+	"log"
+	"sort"
+	"sync"
+	"time"
+
+	"recommender/internal/core/domain"
+	"recommender/internal/core/ports"
+)
+
+// basePriceLookback es cuántos días hacia atrás se busca el cierre de referencia
+// de un evento publicado en fin de semana o feriado.
+const basePriceLookback = 5 * 24 * time.Hour
+
+// BrokerageAccuracyService contrasta los eventos guardados con los precios históricos:
+// si el precio alcanzó target_to dentro de HorizonDays y si los upgrades/downgrades
+// acertaron la dirección del movimiento.
+type BrokerageAccuracyService struct {
+	stocks      ports.StockRepository
+	prices      ports.PriceFeed
+	horizonDays int
+	minEvents   int
+}
+
+func NewBrokerageAccuracyService(stocks ports.StockRepository, prices ports.PriceFeed, horizonDays, minEvents int) *BrokerageAccuracyService {
+	return &BrokerageAccuracyService{
+		stocks:      stocks,
+		prices:      prices,
+		horizonDays: horizonDays,
+		minEvents:   minEvents,
+	}
+}
+
+// Measure evalúa los eventos publicados desde `since` cuyo horizonte ya terminó en `asOf`.
+func (s *BrokerageAccuracyService) Measure(since, asOf time.Time) ([]domain.BrokerageAccuracy, error) {
+	events, err := s.stocks.GetRecentStocks(since, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	horizon := time.Duration(s.horizonDays) * 24 * time.Hour
+	byTicker := map[string][]domain.Stock{}
+	for _, event := range events {
+		if event.Time.Add(horizon).After(asOf) {
+			continue // El horizonte todavía no se cumple
+		}
+		byTicker[event.Ticker] = append(byTicker[event.Ticker], event)
+	}
+
+	stats := map[string]*domain.BrokerageAccuracy{}
+	for ticker, tickerEvents := range byTicker {
+		from, to := tickerEvents[0].Time, tickerEvents[0].Time
+		for _, event := range tickerEvents {
+			if event.Time.Before(from) {
+				from = event.Time
+			}
+			if event.Time.After(to) {
+				to = event.Time
+			}
+		}
+		bars, err := s.prices.GetCloses(ticker, from.Add(-basePriceLookback), to.Add(horizon))
+		if err != nil {
+			return nil, err
+		}
+		if len(bars) == 0 {
+			continue
+		}
+
+		for _, event := range tickerEvents {
+			accuracy, ok := stats[event.Brokerage]
+			if !ok {
+				accuracy = &domain.BrokerageAccuracy{Brokerage: event.Brokerage}
+				stats[event.Brokerage] = accuracy
+			}
+			evaluateEvent(accuracy, event, bars, horizon)
+		}
+	}
+
+	result := make([]domain.BrokerageAccuracy, 0, len(stats))
+	for _, accuracy := range stats {
+		if accuracy.TargetEvents > 0 {
+			accuracy.HitRate = round2(float64(accuracy.TargetHits) / float64(accuracy.TargetEvents))
+		}
+		if accuracy.DirectionalEvents > 0 {
+			accuracy.DirectionAccuracy = round2(float64(accuracy.DirectionHits) / float64(accuracy.DirectionalEvents))
+		}
+		accuracy.Weight = s.weightFor(*accuracy)
+		result = append(result, *accuracy)
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Brokerage < result[j].Brokerage })
+	return result, nil
+}
+
+// weightFor transforma la precisión medida (0..1) en un peso entre 0.5 y 1.5.
+// Con menos de minEvents evaluados se mantiene el peso estático.
+func (s *BrokerageAccuracyService) weightFor(accuracy domain.BrokerageAccuracy) float64 {
+	if accuracy.EvaluatedEvents < s.minEvents {
+		return getBrokerageWeight(accuracy.Brokerage)
+	}
+	score := accuracy.HitRate
+	if accuracy.DirectionalEvents > 0 && accuracy.TargetEvents > 0 {
+		score = (accuracy.HitRate + accuracy.DirectionAccuracy) / 2
+	} else if accuracy.DirectionalEvents > 0 {
+		score = accuracy.DirectionAccuracy
+	}
+	return round2(0.5 + score)
+}
+
+// evaluateEvent acumula en accuracy el resultado de un evento contra la serie de cierres.
+func evaluateEvent(accuracy *domain.BrokerageAccuracy, event domain.Stock, bars []domain.PriceBar, horizon time.Duration) {
+	base, ok := closeOnOrBefore(bars, event.Time)
+	if !ok || event.Time.Sub(base.Date) > basePriceLookback+24*time.Hour {
+		return
+	}
+	end := event.Time.Add(horizon)
+
+	var window []domain.PriceBar
+	for _, bar := range bars {
+		if bar.Date.After(event.Time) && !bar.Date.After(end) {
+			window = append(window, bar)
+		}
+	}
+	if len(window) == 0 {
+		return
+	}
+	accuracy.EvaluatedEvents++
+
+	if event.TargetTo > 0 && event.TargetTo != base.Close {
+		accuracy.TargetEvents++
+		for _, bar := range window {
+			if (event.TargetTo > base.Close && bar.Close >= event.TargetTo) ||
+				(event.TargetTo < base.Close && bar.Close <= event.TargetTo) {
+				accuracy.TargetHits++
+				break
+			}
+		}
+	}
+
+	final := window[len(window)-1].Close
+	switch actionTypeOf(event) {
+	case domain.ActionUpgrade:
+		accuracy.DirectionalEvents++
+		if final > base.Close {
+			accuracy.DirectionHits++
+		}
+	case domain.ActionDowngrade:
+		accuracy.DirectionalEvents++
+		if final < base.Close {
+			accuracy.DirectionHits++
+		}
+	}
+}
+
+// closeOnOrBefore devuelve el último cierre con fecha menor o igual a t.
+func closeOnOrBefore(bars []domain.PriceBar, t time.Time) (domain.PriceBar, bool) {
+	var found domain.PriceBar
+	ok := false
+	for _, bar := range bars {
+		if bar.Date.After(t) {
+			break
+		}
+		found, ok = bar, true
+	}
+	return found, ok
+}
+
+// StaticBrokerageWeights usa las constantes elegidas a mano de getBrokerageWeight.
+type StaticBrokerageWeights struct{}
+
+func (StaticBrokerageWeights) Name() string { return "static" }
+
+func (StaticBrokerageWeights) Weight(brokerage string) float64 {
+	return getBrokerageWeight(brokerage)
+}
+
+// MeasuredBrokerageWeights usa los pesos derivados de la precisión histórica y cae
+// en los pesos estáticos para corredoras sin medición.
+type MeasuredBrokerageWeights struct {
+	weights map[string]float64
+}
+
+func NewMeasuredBrokerageWeights(accuracies []domain.BrokerageAccuracy) MeasuredBrokerageWeights {
+	weights := make(map[string]float64, len(accuracies))
+	for _, accuracy := range accuracies {
+		weights[accuracy.Brokerage] = accuracy.Weight
+	}
+	return MeasuredBrokerageWeights{weights: weights}
+}
+
+func (MeasuredBrokerageWeights) Name() string { return "measured" }
+
+func (w MeasuredBrokerageWeights) Weight(brokerage string) float64 {
+	if weight, ok := w.weights[brokerage]; ok {
+		return weight
+	}
+	return getBrokerageWeight(brokerage)
+}
+
+// brokerageWeightsWindow es cuánta historia se mide al recalcular los pesos.
+const brokerageWeightsWindow = 365 * 24 * time.Hour
+
+// RefreshingBrokerageWeights mantiene los pesos medidos al día: Refresh vuelve a medir
+// la precisión y reemplaza los pesos sin cortar las puntuaciones en curso. A medida que
+// se cumplen horizontes y llegan eventos o precios nuevos, la medición cambia.
+// Hasta la primera medición usa los pesos estáticos.
+type RefreshingBrokerageWeights struct {
+	accuracy *BrokerageAccuracyService
+	now      func() time.Time
+
+	mu      sync.RWMutex
+	current MeasuredBrokerageWeights
+}
+
+func NewRefreshingBrokerageWeights(accuracy *BrokerageAccuracyService) *RefreshingBrokerageWeights {
+	return &RefreshingBrokerageWeights{accuracy: accuracy, now: time.Now}
+}
+
+// WithClock reemplaza el reloj con el que se fija la ventana medida.
+func (w *RefreshingBrokerageWeights) WithClock(now func() time.Time) *RefreshingBrokerageWeights {
+	w.now = now
+	return w
+}
+
+func (*RefreshingBrokerageWeights) Name() string { return "measured" }
+
+func (w *RefreshingBrokerageWeights) Weight(brokerage string) float64 {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+	return w.current.Weight(brokerage)
+}
+
+// Refresh mide el último año y devuelve cuántas corredoras tienen peso medido. Si la
+// medición falla se conservan los pesos anteriores.
+func (w *RefreshingBrokerageWeights) Refresh() (int, error) {
+	now := w.now()
+	accuracies, err := w.accuracy.Measure(now.Add(-brokerageWeightsWindow), now)
+	if err != nil {
+		return 0, err
+	}
+	measured := NewMeasuredBrokerageWeights(accuracies)
+	w.mu.Lock()
+	w.current = measured
+	w.mu.Unlock()
+	return len(accuracies), nil
+}
+
+// Run recalcula los pesos cada interval hasta que ctx se cancele.
+func (w *RefreshingBrokerageWeights) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+		if count, err := w.Refresh(); err != nil {
+			log.Println("⚠ No se pudo recalcular la precisión de las corredoras, se mantienen los pesos anteriores:", err)
+		} else {
+			log.Printf("✅ Pesos medidos recalculados para %d corredoras", count)
+		}
+	}
+}
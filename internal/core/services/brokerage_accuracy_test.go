@@ -0,0 +1,144 @@
+package services
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"recommender/internal/core/domain"
+)
+
+type mockPriceFeed struct {
+	bars []domain.PriceBar
+}
+
+func (m *mockPriceFeed) GetCloses(ticker string, from, to time.Time) ([]domain.PriceBar, error) {
+	var result []domain.PriceBar
+	for _, bar := range m.bars {
+		if bar.Ticker == ticker && !bar.Date.Before(from) && !bar.Date.After(to) {
+			result = append(result, bar)
+		}
+	}
+	return result, nil
+}
+
+func dailyBars(ticker string, start time.Time, closes ...float64) []domain.PriceBar {
+	bars := make([]domain.PriceBar, 0, len(closes))
+	for i, c := range closes {
+		bars = append(bars, domain.PriceBar{Ticker: ticker, Date: start.AddDate(0, 0, i), Close: c})
+	}
+	return bars
+}
+
+func TestBrokerageAccuracyService_Measure(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	eventTime := start.Add(14 * time.Hour) // Publicado el primer día, después del cierre de referencia
+
+	stocks := &mockStockRepository{stocks: []domain.Stock{
+		{Ticker: "UP", Brokerage: "Good Research", Action: "upgraded by", TargetTo: 110, Time: eventTime},
+		{Ticker: "DOWN", Brokerage: "Good Research", Action: "downgraded by", TargetTo: 90, Time: eventTime},
+		{Ticker: "UP", Brokerage: "Bad Research", Action: "downgraded by", TargetTo: 80, Time: eventTime},
+		{Ticker: "RECENT", Brokerage: "Good Research", Action: "upgraded by", TargetTo: 200, Time: start.AddDate(0, 0, 40)},
+	}}
+	var bars []domain.PriceBar
+	bars = append(bars, dailyBars("UP", start, 100, 104, 108, 111, 109)...)
+	bars = append(bars, dailyBars("DOWN", start, 100, 97, 95, 92, 93)...)
+
+	service := NewBrokerageAccuracyService(stocks, &mockPriceFeed{bars: bars}, 10, 2)
+	accuracies, err := service.Measure(start.AddDate(0, 0, -1), start.AddDate(0, 0, 30))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(accuracies) != 2 {
+		t.Fatalf("expected 2 brokerages, got %+v", accuracies)
+	}
+
+	bad, good := accuracies[0], accuracies[1]
+	if good.Brokerage != "Good Research" || good.TargetEvents != 2 || good.TargetHits != 1 {
+		t.Errorf("unexpected target stats for good brokerage: %+v", good)
+	}
+	if good.DirectionalEvents != 2 || good.DirectionHits != 2 || good.DirectionAccuracy != 1 {
+		t.Errorf("unexpected direction stats for good brokerage: %+v", good)
+	}
+	if good.Weight != 1.25 {
+		t.Errorf("expected weight 1.25, got %v", good.Weight)
+	}
+	if bad.DirectionHits != 0 || bad.TargetHits != 0 {
+		t.Errorf("unexpected stats for bad brokerage: %+v", bad)
+	}
+	if bad.Weight != getBrokerageWeight("Bad Research") {
+		t.Errorf("expected static weight below min events, got %v", bad.Weight)
+	}
+}
+
+func TestMeasuredBrokerageWeights(t *testing.T) {
+	weights := NewMeasuredBrokerageWeights([]domain.BrokerageAccuracy{{Brokerage: "JP Morgan", Weight: 0.7}})
+
+	if weights.Weight("JP Morgan") != 0.7 {
+		t.Errorf("expected measured weight")
+	}
+	if weights.Weight("The Goldman Sachs Group") != 1.5 {
+		t.Errorf("expected static fallback")
+	}
+
+	stock := domain.Stock{Brokerage: "JP Morgan", TargetFrom: 100, TargetTo: 110}
+	scorer := DefaultScorer{Weights: weights}
+	if b := scorer.Score(stock, stock.Time); b.BrokerageWeight != 0.7 {
+		t.Errorf("expected scorer to use measured weight, got %+v", b)
+	}
+	if !strings.HasSuffix(scorer.Version(), "/measured") {
+		t.Errorf("expected version to report measured weights, got %s", scorer.Version())
+	}
+}
+
+func TestRefreshingBrokerageWeights(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	eventTime := start.Add(14 * time.Hour)
+	stocks := &mockStockRepository{}
+	prices := &mockPriceFeed{bars: dailyBars("UP", start, 100, 104, 108, 111, 109)}
+	now := start.AddDate(0, 0, 30)
+	service := NewBrokerageAccuracyService(stocks, prices, 10, 1)
+	weights := NewRefreshingBrokerageWeights(service).WithClock(func() time.Time { return now })
+
+	scorer := DefaultScorer{Weights: weights}
+	stock := domain.Stock{Brokerage: "Good Research", TargetFrom: 100, TargetTo: 110}
+	if b := scorer.Score(stock, stock.Time); b.BrokerageWeight != getBrokerageWeight("Good Research") {
+		t.Errorf("expected static weights before the first refresh, got %v", b.BrokerageWeight)
+	}
+
+	if count, err := weights.Refresh(); err != nil || count != 0 {
+		t.Fatalf("expected no measured brokerages yet, got %d (err %v)", count, err)
+	}
+
+	// Llegan eventos cuyo horizonte ya se cumplió: el próximo refresh los mide
+	stocks.stocks = append(stocks.stocks, domain.Stock{Ticker: "UP", Brokerage: "Good Research", Action: "upgraded by", TargetTo: 110, Time: eventTime})
+	if count, err := weights.Refresh(); err != nil || count != 1 {
+		t.Fatalf("expected one measured brokerage, got %d (err %v)", count, err)
+	}
+	accuracies, _ := service.Measure(now.AddDate(-1, 0, 0), now)
+	if b := scorer.Score(stock, stock.Time); b.BrokerageWeight != accuracies[0].Weight || b.BrokerageWeight == getBrokerageWeight("Good Research") {
+		t.Errorf("expected the scorer to see the refreshed weight %v, got %v", accuracies[0].Weight, b.BrokerageWeight)
+	}
+}
+
+func TestParsePriceCSV(t *testing.T) {
+	data := "ticker,date,close\nAAPL,2024-01-02,185.64\nAAPL,2024-01-03T20:00:00Z,184.25\n"
+
+	bars, err := ParsePriceCSV(strings.NewReader(data))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(bars) != 2 || bars[1].Close != 184.25 {
+		t.Fatalf("unexpected bars: %+v", bars)
+	}
+	if !bars[1].Date.Equal(time.Date(2024, 1, 3, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("expected date normalized to start of day, got %v", bars[1].Date)
+	}
+
+	if _, err := ParsePriceCSV(strings.NewReader("ticker,close\nAAPL,1\n")); err == nil {
+		t.Errorf("expected error for missing date column")
+	}
+	if _, err := ParsePriceCSV(strings.NewReader("ticker,date,close\nAAPL,2024-01-02,abc\n")); err == nil {
+		t.Errorf("expected error for invalid close")
+	}
+}
@@ -0,0 +1,138 @@
+package services
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"recommender/internal/core/domain"
+	"recommender/internal/core/ports"
+
+	"gorm.io/gorm"
+)
+
+type CompanyService struct {
+	repository ports.CompanyRepository
+}
+
+func NewCompanyService(repo ports.CompanyRepository) *CompanyService {
+	return &CompanyService{repository: repo}
+}
+
+// SyncFromStock asegura que exista la compañía referenciada por un evento de rating.
+// Los datos ingeridos solo completan campos vacíos: el archivo de referencia manda.
+func (s *CompanyService) SyncFromStock(stock domain.Stock) error {
+	symbol := strings.TrimSpace(stock.Ticker)
+	if symbol == "" {
+		return nil
+	}
+
+	existing, err := s.repository.GetBySymbol(symbol)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return err
+	}
+
+	if existing == nil {
+		return s.repository.Save(&domain.Company{
+			Symbol: symbol,
+			Name:   strings.TrimSpace(stock.Company),
+			Active: true,
+		})
+	}
+
+	if existing.Name == "" && strings.TrimSpace(stock.Company) != "" {
+		existing.Name = strings.TrimSpace(stock.Company)
+		return s.repository.Save(existing)
+	}
+	return nil
+}
+
+// ImportReferenceCSV carga un archivo de referencia con cabecera
+// symbol,name,exchange,sector,industry,active (solo symbol es obligatorio).
+// Devuelve la cantidad de compañías guardadas.
+func (s *CompanyService) ImportReferenceCSV(r io.Reader) (int, error) {
+	reader := csv.NewReader(r)
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err != nil {
+		return 0, fmt.Errorf("ImportReferenceCSV: error leyendo cabecera: %w", err)
+	}
+	columns := make(map[string]int, len(header))
+	for i, name := range header {
+		columns[strings.ToLower(strings.TrimSpace(name))] = i
+	}
+	if _, ok := columns["symbol"]; !ok {
+		return 0, fmt.Errorf("ImportReferenceCSV: falta la columna 'symbol'")
+	}
+
+	field := func(record []string, name string) string {
+		i, ok := columns[name]
+		if !ok || i >= len(record) {
+			return ""
+		}
+		return strings.TrimSpace(record[i])
+	}
+
+	saved := 0
+	for line := 2; ; line++ {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return saved, fmt.Errorf("ImportReferenceCSV: línea %d: %w", line, err)
+		}
+
+		symbol := field(record, "symbol")
+		if symbol == "" {
+			return saved, fmt.Errorf("ImportReferenceCSV: línea %d: symbol vacío", line)
+		}
+
+		active := true
+		if raw := field(record, "active"); raw != "" {
+			active, err = strconv.ParseBool(raw)
+			if err != nil {
+				return saved, fmt.Errorf("ImportReferenceCSV: línea %d: active inválido '%s'", line, raw)
+			}
+		}
+
+		company := &domain.Company{Symbol: symbol}
+		if existing, err := s.repository.GetBySymbol(symbol); err == nil && existing != nil {
+			company = existing
+		} else if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+			return saved, err
+		}
+
+		if name := field(record, "name"); name != "" {
+			company.Name = name
+		}
+		if exchange := field(record, "exchange"); exchange != "" {
+			company.Exchange = exchange
+		}
+		if sector := field(record, "sector"); sector != "" {
+			company.Sector = sector
+		}
+		if industry := field(record, "industry"); industry != "" {
+			company.Industry = industry
+		}
+		company.Active = active
+
+		if err := s.repository.Save(company); err != nil {
+			return saved, fmt.Errorf("ImportReferenceCSV: línea %d: %w", line, err)
+		}
+		saved++
+	}
+	return saved, nil
+}
+
+func (s *CompanyService) SearchCompanies(prefix string, limit, offset int) ([]domain.Company, error) {
+	return s.repository.SearchByNamePrefix(strings.TrimSpace(prefix), limit, offset)
+}
+
+func (s *CompanyService) GetCompany(symbol string) (*domain.Company, error) {
+	return s.repository.GetBySymbol(symbol)
+}
@@ -0,0 +1,142 @@
+package services
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"gorm.io/gorm"
+	"recommender/internal/core/domain"
+)
+
+type mockCompanyRepository struct {
+	companies map[string]domain.Company
+}
+
+func newMockCompanyRepository() *mockCompanyRepository {
+	return &mockCompanyRepository{companies: map[string]domain.Company{}}
+}
+
+func (m *mockCompanyRepository) Save(company *domain.Company) error {
+	m.companies[company.Symbol] = *company
+	return nil
+}
+
+func (m *mockCompanyRepository) GetBySymbol(symbol string) (*domain.Company, error) {
+	c, ok := m.companies[symbol]
+	if !ok {
+		return nil, gorm.ErrRecordNotFound
+	}
+	return &c, nil
+}
+
+func (m *mockCompanyRepository) SearchByNamePrefix(prefix string, limit, offset int) ([]domain.Company, error) {
+	var result []domain.Company
+	for _, c := range m.companies {
+		if strings.HasPrefix(strings.ToLower(c.Name), strings.ToLower(prefix)) {
+			result = append(result, c)
+		}
+	}
+	return result, nil
+}
+
+func TestSyncFromStock_CreatesCompany(t *testing.T) {
+	repo := newMockCompanyRepository()
+	service := NewCompanyService(repo)
+
+	err := service.SyncFromStock(domain.Stock{Ticker: "AAPL", Company: "Apple Inc."})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	company := repo.companies["AAPL"]
+	if company.Name != "Apple Inc." || !company.Active {
+		t.Errorf("unexpected company: %+v", company)
+	}
+}
+
+func TestSyncFromStock_DoesNotOverwriteReferenceName(t *testing.T) {
+	repo := newMockCompanyRepository()
+	repo.companies["AAPL"] = domain.Company{Symbol: "AAPL", Name: "Apple Inc.", Sector: "Technology", Active: true}
+	service := NewCompanyService(repo)
+
+	if err := service.SyncFromStock(domain.Stock{Ticker: "AAPL", Company: "Apple"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if repo.companies["AAPL"].Name != "Apple Inc." {
+		t.Errorf("expected reference name to be kept, got %s", repo.companies["AAPL"].Name)
+	}
+}
+
+func TestImportReferenceCSV(t *testing.T) {
+	repo := newMockCompanyRepository()
+	repo.companies["MSFT"] = domain.Company{Symbol: "MSFT", Name: "Microsoft", Active: true}
+	service := NewCompanyService(repo)
+
+	data := "symbol,name,exchange,sector,industry,active\n" +
+		"AAPL,Apple Inc.,NASDAQ,Technology,Consumer Electronics,true\n" +
+		"MSFT,,NASDAQ,Technology,Software,\n" +
+		"OLD,Old Corp,NYSE,Energy,Oil,false\n"
+
+	count, err := service.ImportReferenceCSV(strings.NewReader(data))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if count != 3 {
+		t.Errorf("expected 3 companies, got %d", count)
+	}
+	if repo.companies["AAPL"].Sector != "Technology" {
+		t.Errorf("unexpected AAPL: %+v", repo.companies["AAPL"])
+	}
+	if repo.companies["MSFT"].Name != "Microsoft" || repo.companies["MSFT"].Industry != "Software" {
+		t.Errorf("unexpected MSFT: %+v", repo.companies["MSFT"])
+	}
+	if repo.companies["OLD"].Active {
+		t.Errorf("expected OLD to be inactive")
+	}
+}
+
+func TestImportReferenceCSV_MissingSymbolColumn(t *testing.T) {
+	service := NewCompanyService(newMockCompanyRepository())
+
+	_, err := service.ImportReferenceCSV(strings.NewReader("name,sector\nApple,Technology\n"))
+	if err == nil {
+		t.Fatal("expected error for missing symbol column")
+	}
+}
+
+func TestAddStockSyncsCompany(t *testing.T) {
+	companies := newMockCompanyRepository()
+	service := NewStockService(&mockStockRepository{}, nil).WithCompanyService(NewCompanyService(companies))
+
+	if err := service.AddStock(&domain.Stock{Ticker: "NVDA", Company: "NVIDIA Corporation"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, ok := companies.companies["NVDA"]; !ok {
+		t.Errorf("expected company NVDA to be created")
+	}
+}
+
+func TestGetTopRecommendedStocks_MaxPerSectorUsesCompanies(t *testing.T) {
+	now := time.Now()
+	companies := newMockCompanyRepository()
+	companies.Save(&domain.Company{Symbol: "A", Sector: "Technology"})
+	companies.Save(&domain.Company{Symbol: "B", Sector: "Technology"})
+	repo := &mockStockRepository{stocks: []domain.Stock{
+		{Ticker: "A", TargetFrom: 100, TargetTo: 130, Brokerage: "JP Morgan", Time: now},
+		{Ticker: "B", TargetFrom: 100, TargetTo: 120, Brokerage: "JP Morgan", Time: now},
+		{Ticker: "C", TargetFrom: 100, TargetTo: 110, Brokerage: "JP Morgan", Time: now},
+	}}
+	service := NewStockService(repo, nil).WithCompanyService(NewCompanyService(companies))
+
+	params := DefaultRecommendationParams()
+	params.MaxPerSector = 1
+	top, err := service.GetTopRecommendedStocks(params)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(top.Items) != 2 || top.Items[0].Ticker != "A" || top.Items[1].Ticker != "C" {
+		t.Errorf("expected A and C, got %+v", top.Items)
+	}
+}
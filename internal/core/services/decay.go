@@ -0,0 +1,120 @@
+package services
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Decay convierte la antigüedad de un evento de rating en un factor entre 0 y 1
+// que multiplica su puntuación. Los eventos con fecha futura no decaen.
+type Decay interface {
+	Name() string
+	Factor(age time.Duration) float64
+}
+
+// NoDecay mantiene el comportamiento original: todos los eventos pesan igual.
+type NoDecay struct{}
+
+func (NoDecay) Name() string { return "none" }
+
+func (NoDecay) Factor(age time.Duration) float64 { return 1 }
+
+// ExponentialDecay reduce el peso a la mitad cada HalfLife.
+type ExponentialDecay struct {
+	HalfLife time.Duration
+}
+
+func (d ExponentialDecay) Name() string {
+	return "exponential-" + formatDays(d.HalfLife)
+}
+
+func (d ExponentialDecay) Factor(age time.Duration) float64 {
+	if age <= 0 || d.HalfLife <= 0 {
+		return 1
+	}
+	return math.Pow(0.5, float64(age)/float64(d.HalfLife))
+}
+
+// LinearDecay baja el peso linealmente hasta 0 al cumplirse Window.
+type LinearDecay struct {
+	Window time.Duration
+}
+
+func (d LinearDecay) Name() string {
+	return "linear-" + formatDays(d.Window)
+}
+
+func (d LinearDecay) Factor(age time.Duration) float64 {
+	if age <= 0 || d.Window <= 0 {
+		return 1
+	}
+	return math.Max(0, 1-float64(age)/float64(d.Window))
+}
+
+// DecayStep aplica Factor a los eventos con antigüedad menor o igual a MaxAge.
+type DecayStep struct {
+	MaxAge time.Duration
+	Factor float64
+}
+
+// StepDecay usa el primer escalón que cubre la antigüedad; más allá del último el peso es 0.
+type StepDecay struct {
+	Steps []DecayStep
+}
+
+func (d StepDecay) Name() string {
+	parts := make([]string, 0, len(d.Steps))
+	for _, step := range d.Steps {
+		parts = append(parts, fmt.Sprintf("%s:%g", formatDays(step.MaxAge), step.Factor))
+	}
+	return "step-" + strings.Join(parts, ",")
+}
+
+func (d StepDecay) Factor(age time.Duration) float64 {
+	if age <= 0 {
+		return 1
+	}
+	for _, step := range d.Steps {
+		if age <= step.MaxAge {
+			return step.Factor
+		}
+	}
+	return 0
+}
+
+// ParseDecaySteps interpreta "7:1,14:0.5,30:0.25" (días:factor) ordenando por antigüedad.
+func ParseDecaySteps(spec string) ([]DecayStep, error) {
+	var steps []DecayStep
+	for _, part := range strings.Split(spec, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		days, factor, ok := strings.Cut(part, ":")
+		if !ok {
+			return nil, fmt.Errorf("ParseDecaySteps: escalón inválido '%s'", part)
+		}
+		d, err := strconv.ParseFloat(strings.TrimSpace(days), 64)
+		if err != nil || d <= 0 {
+			return nil, fmt.Errorf("ParseDecaySteps: días inválidos en '%s'", part)
+		}
+		f, err := strconv.ParseFloat(strings.TrimSpace(factor), 64)
+		if err != nil || f < 0 || f > 1 {
+			return nil, fmt.Errorf("ParseDecaySteps: factor inválido en '%s'", part)
+		}
+		steps = append(steps, DecayStep{MaxAge: time.Duration(d * float64(24*time.Hour)), Factor: f})
+	}
+	if len(steps) == 0 {
+		return nil, fmt.Errorf("ParseDecaySteps: no se definieron escalones")
+	}
+	sort.Slice(steps, func(i, j int) bool { return steps[i].MaxAge < steps[j].MaxAge })
+	return steps, nil
+}
+
+func formatDays(d time.Duration) string {
+	return strconv.FormatFloat(d.Hours()/24, 'f', -1, 64) + "d"
+}
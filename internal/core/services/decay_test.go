@@ -0,0 +1,110 @@
+package services
+
+import (
+	"math"
+	"strings"
+	"testing"
+	"time"
+
+	"recommender/internal/core/domain"
+)
+
+const day = 24 * time.Hour
+
+func TestExponentialDecay(t *testing.T) {
+	decay := ExponentialDecay{HalfLife: 7 * day}
+
+	if f := decay.Factor(0); f != 1 {
+		t.Errorf("expected factor 1 for a fresh event, got %v", f)
+	}
+	if f := decay.Factor(7 * day); math.Abs(f-0.5) > 1e-9 {
+		t.Errorf("expected factor 0.5 after one half-life, got %v", f)
+	}
+	if f := decay.Factor(-day); f != 1 {
+		t.Errorf("expected future events not to decay, got %v", f)
+	}
+	if decay.Name() != "exponential-7d" {
+		t.Errorf("unexpected name %s", decay.Name())
+	}
+}
+
+func TestLinearDecay(t *testing.T) {
+	decay := LinearDecay{Window: 10 * day}
+
+	if f := decay.Factor(5 * day); math.Abs(f-0.5) > 1e-9 {
+		t.Errorf("expected 0.5 halfway through the window, got %v", f)
+	}
+	if f := decay.Factor(20 * day); f != 0 {
+		t.Errorf("expected 0 after the window, got %v", f)
+	}
+}
+
+func TestStepDecay(t *testing.T) {
+	steps, err := ParseDecaySteps("14:0.5, 7:1 ,30:0.25")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	decay := StepDecay{Steps: steps}
+
+	testCases := map[time.Duration]float64{
+		2 * day:  1,
+		10 * day: 0.5,
+		29 * day: 0.25,
+		31 * day: 0,
+	}
+	for age, expected := range testCases {
+		if f := decay.Factor(age); f != expected {
+			t.Errorf("Factor(%v) = %v, expected %v", age, f, expected)
+		}
+	}
+}
+
+func TestParseDecaySteps_Invalid(t *testing.T) {
+	for _, spec := range []string{"", "7", "x:1", "7:2"} {
+		if _, err := ParseDecaySteps(spec); err == nil {
+			t.Errorf("expected error for %q", spec)
+		}
+	}
+}
+
+func TestDefaultScorer_AppliesDecay(t *testing.T) {
+	asOf := time.Date(2024, 6, 30, 0, 0, 0, 0, time.UTC)
+	stock := domain.Stock{TargetFrom: 100, TargetTo: 110, RatingFrom: "Hold", RatingTo: "Hold", Time: asOf.Add(-7 * day)}
+
+	scorer := DefaultScorer{Decay: ExponentialDecay{HalfLife: 7 * day}}
+	breakdown := scorer.Score(stock, asOf)
+
+	if math.Abs(breakdown.DecayFactor-0.5) > 1e-9 || math.Abs(breakdown.Total-5) > 1e-9 {
+		t.Errorf("unexpected decayed breakdown: %+v", breakdown)
+	}
+	if breakdown.AgeDays != 7 || !strings.Contains(breakdown.Rationale, "decay ×0.50 (7.0 days old)") {
+		t.Errorf("expected decay in rationale, got %q", breakdown.Rationale)
+	}
+	if scorer.Version() != "1.1/exponential-7d" {
+		t.Errorf("unexpected version %s", scorer.Version())
+	}
+}
+
+func TestGetTopRecommendedStocks_DecayUsesInjectedClock(t *testing.T) {
+	now := time.Date(2024, 6, 30, 12, 0, 0, 0, time.UTC)
+	repo := &mockStockRepository{
+		stocks: []domain.Stock{
+			{Ticker: "STALE", TargetFrom: 100, TargetTo: 130, Brokerage: "Others", Time: now.Add(-28 * day)},
+			{Ticker: "FRESH", TargetFrom: 100, TargetTo: 115, Brokerage: "Others", Time: now.Add(-time.Hour)},
+		},
+	}
+	service := NewStockService(repo, nil).
+		WithClock(func() time.Time { return now }).
+		WithDecay(ExponentialDecay{HalfLife: 7 * day})
+
+	top, err := service.GetTopRecommendedStocks(DefaultRecommendationParams())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if top.Items[0].Stock.Ticker != "FRESH" {
+		t.Errorf("expected FRESH to outrank a 4 week old event, got %s", top.Items[0].Stock.Ticker)
+	}
+	if !top.GeneratedAt.Equal(now) {
+		t.Errorf("expected GeneratedAt from the injected clock, got %v", top.GeneratedAt)
+	}
+}
@@ -0,0 +1,98 @@
+package services
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"recommender/internal/core/domain"
+	"recommender/internal/core/ports"
+)
+
+type PriceService struct {
+	repository ports.PriceRepository
+}
+
+func NewPriceService(repo ports.PriceRepository) *PriceService {
+	return &PriceService{repository: repo}
+}
+
+// ImportCSV guarda los cierres de un archivo con cabecera ticker,date,close.
+// Devuelve la cantidad de cierres importados.
+func (s *PriceService) ImportCSV(r io.Reader) (int, error) {
+	bars, err := ParsePriceCSV(r)
+	if err != nil {
+		return 0, err
+	}
+	if err := s.repository.SaveBars(bars); err != nil {
+		return 0, err
+	}
+	return len(bars), nil
+}
+
+// ParsePriceCSV lee cierres con cabecera ticker,date,close. La fecha acepta
+// 2006-01-02 o RFC3339 y se normaliza al inicio del día en UTC.
+func ParsePriceCSV(r io.Reader) ([]domain.PriceBar, error) {
+	reader := csv.NewReader(r)
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err != nil {
+		return nil, fmt.Errorf("ParsePriceCSV: error leyendo cabecera: %w", err)
+	}
+	columns := make(map[string]int, len(header))
+	for i, name := range header {
+		columns[strings.ToLower(strings.TrimSpace(name))] = i
+	}
+	for _, required := range []string{"ticker", "date", "close"} {
+		if _, ok := columns[required]; !ok {
+			return nil, fmt.Errorf("ParsePriceCSV: falta la columna '%s'", required)
+		}
+	}
+
+	var bars []domain.PriceBar
+	for line := 2; ; line++ {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("ParsePriceCSV: línea %d: %w", line, err)
+		}
+
+		date, err := parsePriceDate(strings.TrimSpace(record[columns["date"]]))
+		if err != nil {
+			return nil, fmt.Errorf("ParsePriceCSV: línea %d: fecha inválida: %w", line, err)
+		}
+		closePrice, err := strconv.ParseFloat(strings.TrimSpace(record[columns["close"]]), 64)
+		if err != nil || closePrice <= 0 {
+			return nil, fmt.Errorf("ParsePriceCSV: línea %d: cierre inválido '%s'", line, record[columns["close"]])
+		}
+
+		bars = append(bars, domain.PriceBar{
+			Ticker: strings.TrimSpace(record[columns["ticker"]]),
+			Date:   date,
+			Close:  closePrice,
+		})
+	}
+	return bars, nil
+}
+
+func parsePriceDate(raw string) (time.Time, error) {
+	if t, err := time.Parse("2006-01-02", raw); err == nil {
+		return t, nil
+	}
+	t, err := time.Parse(time.RFC3339, raw)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return startOfDay(t), nil
+}
+
+func startOfDay(t time.Time) time.Time {
+	t = t.UTC()
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
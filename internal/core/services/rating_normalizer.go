@@ -0,0 +1,177 @@
+package services
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"sort"
+	"strings"
+	"sync"
+
+	"recommender/internal/core/domain"
+)
+
+// RatingNormalizer traduce las etiquetas crudas de rating (RatingFrom/RatingTo)
+// a la escala domain.RatingScore y lleva la cuenta de las etiquetas desconocidas.
+type RatingNormalizer struct {
+	mapping map[string]domain.RatingScore
+
+	mu      sync.Mutex
+	unknown map[string]int
+}
+
+func NewRatingNormalizer(mapping map[string]domain.RatingScore) *RatingNormalizer {
+	normalized := make(map[string]domain.RatingScore, len(mapping))
+	for label, score := range mapping {
+		normalized[normalizeRatingLabel(label)] = score
+	}
+	return &RatingNormalizer{
+		mapping: normalized,
+		unknown: map[string]int{},
+	}
+}
+
+var defaultRatingMapping = MergeRatingMappings(DefaultRatingMapping())
+
+// DefaultRatingMapping cubre las etiquetas más comunes de las corredoras.
+func DefaultRatingMapping() map[string]domain.RatingScore {
+	return map[string]domain.RatingScore{
+		"strong sell":           domain.RatingStrongSell,
+		"sell":                  domain.RatingSell,
+		"underperform":          domain.RatingSell,
+		"underweight":           domain.RatingSell,
+		"reduce":                domain.RatingSell,
+		"negative":              domain.RatingSell,
+		"moderate sell":         domain.RatingSell,
+		"sector underperform":   domain.RatingSell,
+		"market underperform":   domain.RatingSell,
+		"underperformer":        domain.RatingSell,
+		"sector underperformer": domain.RatingSell,
+		"hold":                  domain.RatingHold,
+		"neutral":               domain.RatingHold,
+		"market perform":        domain.RatingHold,
+		"sector perform":        domain.RatingHold,
+		"peer perform":          domain.RatingHold,
+		"equal weight":          domain.RatingHold,
+		"sector weight":         domain.RatingHold,
+		"market weight":         domain.RatingHold,
+		"in line":               domain.RatingHold,
+		"inline":                domain.RatingHold,
+		"perform":               domain.RatingHold,
+		"mixed":                 domain.RatingHold,
+		"fair value":            domain.RatingHold,
+		"buy":                   domain.RatingBuy,
+		"outperform":            domain.RatingBuy,
+		"overweight":            domain.RatingBuy,
+		"accumulate":            domain.RatingBuy,
+		"add":                   domain.RatingBuy,
+		"positive":              domain.RatingBuy,
+		"moderate buy":          domain.RatingBuy,
+		"speculative buy":       domain.RatingBuy,
+		"sector outperform":     domain.RatingBuy,
+		"market outperform":     domain.RatingBuy,
+		"outperformer":          domain.RatingBuy,
+		"sector outperformer":   domain.RatingBuy,
+		"market outperformer":   domain.RatingBuy,
+		"strong buy":            domain.RatingStrongBuy,
+		"conviction buy":        domain.RatingStrongBuy,
+		"top pick":              domain.RatingStrongBuy,
+	}
+}
+
+// ParseRatingMapping lee un objeto JSON {"Etiqueta": puntaje} con puntajes de 1 a 5.
+func ParseRatingMapping(r io.Reader) (map[string]domain.RatingScore, error) {
+	var raw map[string]int
+	if err := json.NewDecoder(r).Decode(&raw); err != nil {
+		return nil, fmt.Errorf("ParseRatingMapping: JSON inválido: %w", err)
+	}
+
+	mapping := make(map[string]domain.RatingScore, len(raw))
+	for label, value := range raw {
+		score := domain.RatingScore(value)
+		if !score.Known() {
+			return nil, fmt.Errorf("ParseRatingMapping: puntaje %d fuera de rango para '%s'", value, label)
+		}
+		mapping[label] = score
+	}
+	return mapping, nil
+}
+
+// MergeRatingMappings combina mapeos; las entradas posteriores tienen prioridad.
+func MergeRatingMappings(mappings ...map[string]domain.RatingScore) map[string]domain.RatingScore {
+	merged := map[string]domain.RatingScore{}
+	for _, mapping := range mappings {
+		for label, score := range mapping {
+			merged[normalizeRatingLabel(label)] = score
+		}
+	}
+	return merged
+}
+
+// Normalize devuelve el puntaje de la etiqueta o RatingUnknown, registrándola como desconocida.
+func (n *RatingNormalizer) Normalize(label string) domain.RatingScore {
+	key := normalizeRatingLabel(label)
+	if key == "" {
+		return domain.RatingUnknown
+	}
+	if score, ok := n.mapping[key]; ok {
+		return score
+	}
+
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	if n.unknown[key] == 0 {
+		log.Printf("⚠ Etiqueta de rating desconocida: '%s'", label)
+	}
+	n.unknown[key]++
+	return domain.RatingUnknown
+}
+
+// Apply completa los puntajes normalizados del stock conservando las etiquetas crudas.
+func (n *RatingNormalizer) Apply(stock *domain.Stock) {
+	stock.RatingFromScore = n.Normalize(stock.RatingFrom)
+	stock.RatingToScore = n.Normalize(stock.RatingTo)
+}
+
+// UnknownLabels lista las etiquetas desconocidas vistas por este proceso, de la más a
+// la menos frecuente.
+func (n *RatingNormalizer) UnknownLabels() []domain.UnknownRatingLabel {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	return sortUnknownLabels(n.unknown)
+}
+
+// UnknownLabelsFrom agrupa conteos de etiquetas crudas (los guardados en la base) y
+// conserva solo las que el mapeo actual sigue sin reconocer.
+func (n *RatingNormalizer) UnknownLabelsFrom(raw map[string]int) []domain.UnknownRatingLabel {
+	counts := map[string]int{}
+	for label, count := range raw {
+		key := normalizeRatingLabel(label)
+		if _, known := n.mapping[key]; key != "" && !known {
+			counts[key] += count
+		}
+	}
+	return sortUnknownLabels(counts)
+}
+
+func sortUnknownLabels(counts map[string]int) []domain.UnknownRatingLabel {
+	labels := make([]domain.UnknownRatingLabel, 0, len(counts))
+	for label, count := range counts {
+		labels = append(labels, domain.UnknownRatingLabel{Label: label, Count: count})
+	}
+	sort.Slice(labels, func(i, j int) bool {
+		if labels[i].Count != labels[j].Count {
+			return labels[i].Count > labels[j].Count
+		}
+		return labels[i].Label < labels[j].Label
+	})
+	return labels
+}
+
+// normalizeRatingLabel unifica mayúsculas, guiones y espacios ("Strong-Buy" -> "strong buy").
+func normalizeRatingLabel(label string) string {
+	label = strings.ToLower(label)
+	label = strings.NewReplacer("-", " ", "_", " ").Replace(label)
+	return strings.Join(strings.Fields(label), " ")
+}
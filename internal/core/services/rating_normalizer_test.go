@@ -0,0 +1,152 @@
+package services
+
+import (
+	"strings"
+	"testing"
+
+	"recommender/internal/core/domain"
+)
+
+func TestRatingNormalizer_Normalize(t *testing.T) {
+	normalizer := NewRatingNormalizer(DefaultRatingMapping())
+
+	testCases := map[string]domain.RatingScore{
+		"Strong-Buy":     domain.RatingStrongBuy,
+		"Outperform":     domain.RatingBuy,
+		"Overweight":     domain.RatingBuy,
+		"Market Perform": domain.RatingHold,
+		"In-Line":        domain.RatingHold,
+		"Underweight":    domain.RatingSell,
+		"Strong Sell":    domain.RatingStrongSell,
+		"  neutral ":     domain.RatingHold,
+		"":               domain.RatingUnknown,
+	}
+
+	for label, expected := range testCases {
+		if got := normalizer.Normalize(label); got != expected {
+			t.Errorf("Normalize(%q) = %v, expected %v", label, got, expected)
+		}
+	}
+}
+
+func TestRatingNormalizer_ReportsUnknownLabels(t *testing.T) {
+	normalizer := NewRatingNormalizer(DefaultRatingMapping())
+
+	stock := domain.Stock{RatingFrom: "Speculative Hold", RatingTo: "Buy"}
+	normalizer.Apply(&stock)
+	normalizer.Normalize("Speculative Hold")
+	normalizer.Normalize("Sector Sell")
+
+	if stock.RatingFromScore != domain.RatingUnknown || stock.RatingToScore != domain.RatingBuy {
+		t.Errorf("unexpected scores: %+v", stock)
+	}
+	if stock.RatingFrom != "Speculative Hold" {
+		t.Errorf("expected raw label to be kept, got %s", stock.RatingFrom)
+	}
+
+	unknown := normalizer.UnknownLabels()
+	if len(unknown) != 2 {
+		t.Fatalf("expected 2 unknown labels, got %+v", unknown)
+	}
+	if unknown[0].Label != "speculative hold" || unknown[0].Count != 2 {
+		t.Errorf("unexpected first unknown label: %+v", unknown[0])
+	}
+}
+
+func TestParseRatingMapping(t *testing.T) {
+	mapping, err := ParseRatingMapping(strings.NewReader(`{"Speculative Hold": 3, "Sector Sell": 2}`))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	normalizer := NewRatingNormalizer(MergeRatingMappings(DefaultRatingMapping(), mapping))
+	if got := normalizer.Normalize("speculative-hold"); got != domain.RatingHold {
+		t.Errorf("expected Hold, got %v", got)
+	}
+	if got := normalizer.Normalize("Buy"); got != domain.RatingBuy {
+		t.Errorf("expected default mapping to be kept, got %v", got)
+	}
+}
+
+func TestParseRatingMapping_OutOfRange(t *testing.T) {
+	_, err := ParseRatingMapping(strings.NewReader(`{"Buy": 7}`))
+	if err == nil {
+		t.Fatal("expected error for out of range score")
+	}
+}
+
+func TestCalculateRatingImpact_UsesNormalizedScale(t *testing.T) {
+	stock := domain.Stock{RatingFrom: "Market Perform", RatingTo: "Outperform"}
+	if impact := calculateRatingImpact(stock, nil); impact != 2 {
+		t.Errorf("expected impact 2, got %v", impact)
+	}
+
+	stock = domain.Stock{RatingFromScore: domain.RatingSell, RatingToScore: domain.RatingStrongBuy}
+	if impact := calculateRatingImpact(stock, nil); impact != 6 {
+		t.Errorf("expected impact 6, got %v", impact)
+	}
+}
+
+func TestCalculateRatingImpact_LegacyRowsUseConfiguredMapping(t *testing.T) {
+	// Fila guardada antes de la normalización: sin puntajes, solo etiquetas crudas
+	stock := domain.Stock{RatingFrom: "Hold", RatingTo: "Speculative Hold"}
+	custom := NewRatingNormalizer(MergeRatingMappings(DefaultRatingMapping(), map[string]domain.RatingScore{
+		"Hold":             domain.RatingSell,
+		"Speculative Hold": domain.RatingBuy,
+	}))
+
+	if impact := calculateRatingImpact(stock, nil); impact != 0 {
+		t.Errorf("expected the default mapping to see Hold -> Hold, got %v", impact)
+	}
+	if impact := calculateRatingImpact(stock, custom); impact != 4 {
+		t.Errorf("expected the configured mapping to see Sell -> Buy, got %v", impact)
+	}
+
+	service := NewStockService(&mockStockRepository{}, nil).WithRatingNormalizer(custom)
+	scorer, err := service.ScorerRegistry().Get(DefaultStrategy)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if b := scorer.Score(stock, stock.Time); b.RatingImpact != 4 || !strings.Contains(b.Rationale, "rating Sell → Buy") {
+		t.Errorf("expected the service scorer to use the configured mapping, got %+v", b)
+	}
+}
+
+func TestRatingNormalizer_UnknownLabelsFromStoredCounts(t *testing.T) {
+	normalizer := NewRatingNormalizer(MergeRatingMappings(DefaultRatingMapping(), map[string]domain.RatingScore{"Sector Sell": domain.RatingSell}))
+
+	unknown := normalizer.UnknownLabelsFrom(map[string]int{
+		"Speculative Hold": 2,
+		"speculative-hold": 1,
+		"Sector Sell":      4, // Mapeada después de ingerirse
+		"Buy":              3, // Guardada antes de que se normalizaran los ratings
+	})
+	if len(unknown) != 1 || unknown[0] != (domain.UnknownRatingLabel{Label: "speculative hold", Count: 3}) {
+		t.Errorf("unexpected unknown labels: %+v", unknown)
+	}
+}
+
+// countingStockRepository cuenta las etiquetas desconocidas como lo hace la base.
+type countingStockRepository struct {
+	*mockStockRepository
+}
+
+func (c countingStockRepository) CountUnknownRatingLabels() (map[string]int, error) {
+	counts := map[string]int{}
+	for _, stock := range c.stocks {
+		if stock.RatingToScore == domain.RatingUnknown && stock.RatingTo != "" {
+			counts[stock.RatingTo]++
+		}
+	}
+	return counts, nil
+}
+
+func TestUnknownRatingLabels_ReadsStoredEvents(t *testing.T) {
+	// Eventos ingeridos por otro proceso: este normalizador nunca los vio
+	repo := countingStockRepository{&mockStockRepository{stocks: []domain.Stock{{Ticker: "A", RatingTo: "Speculative Hold"}}}}
+
+	unknown, err := NewStockService(repo, nil).UnknownRatingLabels()
+	if err != nil || len(unknown) != 1 || unknown[0].Label != "speculative hold" {
+		t.Errorf("expected the stored unknown label, got %+v (%v)", unknown, err)
+	}
+}
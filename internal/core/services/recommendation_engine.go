@@ -0,0 +1,247 @@
+package services
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	"recommender/internal/core/domain"
+	"recommender/internal/core/ports"
+)
+
+// SectorLookup devuelve el sector de un ticker, o "" si no se conoce.
+type SectorLookup func(ticker string) string
+
+// buildRecommendations puntúa los eventos al instante asOf, los agrupa según
+// params.GroupBy, aplica las restricciones de diversificación y devuelve el ranking
+// recortado a params.Limit. sectors puede ser nil si no hay datos de compañías.
+func buildRecommendations(events []domain.Stock, params domain.RecommendationParams, scorer ports.Scorer, asOf time.Time, sectors SectorLookup) []domain.Recommendation {
+	scored := scoreEvents(events, scorer, asOf)
+	if params.GroupBy != domain.GroupByEvent {
+		scored = aggregateByTicker(scored, ratingsOf(scorer))
+	}
+	if params.HasConstraints() {
+		scored = diversify(scored, params, sectors)
+	}
+	return rankRecommendations(scored, params.Limit)
+}
+
+// scoreEvents genera una recomendación por evento de rating.
+func scoreEvents(events []domain.Stock, scorer ports.Scorer, asOf time.Time) []domain.Recommendation {
+	recommendations := make([]domain.Recommendation, 0, len(events))
+	for _, stock := range events {
+		breakdown := scorer.Score(stock, asOf)
+		recommendations = append(recommendations, domain.Recommendation{
+			Ticker:    stock.Ticker,
+			Company:   stock.Company,
+			Stock:     stock,
+			Score:     breakdown.Total,
+			Breakdown: breakdown,
+		})
+	}
+	return recommendations
+}
+
+// aggregateByTicker combina los eventos de cada ticker en una sola recomendación.
+// El total es la suma de los totales que la estrategia elegida dio a cada evento (que ya
+// incluyen el peso de la corredora y el decaimiento), así cualquier Scorer decide el
+// ranking. Los componentes del desglose son informativos: precio y rating se promedian
+// ponderando por peso y decaimiento y los de acción se suman.
+func aggregateByTicker(scored []domain.Recommendation, ratings *RatingNormalizer) []domain.Recommendation {
+	var order []string
+	groups := map[string][]domain.Recommendation{}
+	for _, rec := range scored {
+		if _, seen := groups[rec.Ticker]; !seen {
+			order = append(order, rec.Ticker)
+		}
+		groups[rec.Ticker] = append(groups[rec.Ticker], rec)
+	}
+
+	aggregated := make([]domain.Recommendation, 0, len(order))
+	for _, ticker := range order {
+		aggregated = append(aggregated, combineTickerEvents(groups[ticker], ratings))
+	}
+	return aggregated
+}
+
+func combineTickerEvents(events []domain.Recommendation, ratings *RatingNormalizer) domain.Recommendation {
+	var (
+		weightSum, priceSum, ratingSum, shiftSum float64
+		actionSum, brokerageSum, decaySum        float64
+		totalSum                                 float64
+		latest                                   = events[0]
+		brokerages                               = map[string]bool{}
+		aggregate                                = domain.TickerAggregate{EventCount: len(events)}
+		stocks                                   = make([]domain.Stock, 0, len(events))
+	)
+
+	for _, event := range events {
+		b := event.Breakdown
+		weight := b.BrokerageWeight * b.DecayFactor
+		weightSum += weight
+		priceSum += weight * b.PriceImpact
+		ratingSum += weight * b.RatingImpact
+		shiftSum += weight * float64(effectiveRating(event.Stock.RatingToScore, event.Stock.RatingTo, ratings)-
+			effectiveRating(event.Stock.RatingFromScore, event.Stock.RatingFrom, ratings))
+		actionSum += b.ActionImpact
+		brokerageSum += b.BrokerageWeight
+		decaySum += b.DecayFactor
+		totalSum += b.Total
+
+		brokerages[event.Stock.Brokerage] = true
+		switch actionTypeOf(event.Stock) {
+		case domain.ActionUpgrade:
+			aggregate.Upgrades++
+		case domain.ActionDowngrade:
+			aggregate.Downgrades++
+		}
+		if event.Stock.Time.After(latest.Stock.Time) {
+			latest = event
+		}
+		stocks = append(stocks, event.Stock)
+	}
+
+	n := float64(len(events))
+	breakdown := domain.ScoreBreakdown{
+		ActionImpact:    actionSum,
+		BrokerageWeight: round2(brokerageSum / n),
+		DecayFactor:     round2(decaySum / n),
+		AgeDays:         latest.Breakdown.AgeDays,
+	}
+	if weightSum > 0 {
+		breakdown.PriceImpact = round2(priceSum / weightSum)
+		breakdown.RatingImpact = round2(ratingSum / weightSum)
+		aggregate.ConsensusShift = round2(shiftSum / weightSum)
+	}
+	aggregate.WeightedTargetChange = breakdown.PriceImpact
+	aggregate.Brokerages = len(brokerages)
+	breakdown.Total = totalSum
+
+	if len(events) == 1 {
+		breakdown = latest.Breakdown // Un único evento conserva su desglose exacto
+	} else {
+		breakdown.Rationale = explainAggregate(latest, aggregate, breakdown)
+	}
+
+	sort.SliceStable(stocks, func(i, j int) bool { return stocks[i].Time.After(stocks[j].Time) })
+	return domain.Recommendation{
+		Ticker:    latest.Ticker,
+		Company:   latest.Company,
+		Stock:     latest.Stock,
+		Score:     breakdown.Total,
+		Breakdown: breakdown,
+		Aggregate: &aggregate,
+		Events:    stocks,
+	}
+}
+
+// rankRecommendations ordena de mayor a menor puntuación, recorta y numera.
+func rankRecommendations(recommendations []domain.Recommendation, limit int) []domain.Recommendation {
+	sort.SliceStable(recommendations, func(i, j int) bool {
+		return recommendations[i].Score > recommendations[j].Score
+	})
+	if limit > 0 && len(recommendations) > limit {
+		recommendations = recommendations[:limit]
+	}
+	for i := range recommendations {
+		recommendations[i].Rank = i + 1
+	}
+	return recommendations
+}
+
+// diversify recorre los candidatos de mayor a menor puntuación y se queda con los que
+// no exceden los cupos por sector y por corredora ni tienen menos corredoras de las
+// exigidas. Un pick cuenta para el cupo de cada corredora que lo respalda; los tickers
+// sin sector conocido no consumen cupo de sector.
+func diversify(candidates []domain.Recommendation, params domain.RecommendationParams, sectors SectorLookup) []domain.Recommendation {
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return candidates[i].Score > candidates[j].Score
+	})
+
+	perSector := map[string]int{}
+	perBrokerage := map[string]int{}
+	picked := make([]domain.Recommendation, 0, params.Limit)
+	for _, rec := range candidates {
+		if len(picked) == params.Limit {
+			break
+		}
+
+		brokerages := recommendationBrokerages(rec)
+		if len(brokerages) < params.MinBrokerages {
+			continue
+		}
+		if params.MaxPerBrokerage > 0 && exceedsQuota(perBrokerage, brokerages, params.MaxPerBrokerage) {
+			continue
+		}
+		if sectors != nil {
+			rec.Sector = sectors(rec.Ticker)
+		}
+		if params.MaxPerSector > 0 && rec.Sector != "" && perSector[rec.Sector] >= params.MaxPerSector {
+			continue
+		}
+
+		for _, brokerage := range brokerages {
+			perBrokerage[brokerage]++
+		}
+		if rec.Sector != "" {
+			perSector[rec.Sector]++
+		}
+		picked = append(picked, rec)
+	}
+	return picked
+}
+
+// recommendationBrokerages lista las corredoras distintas que respaldan la recomendación.
+func recommendationBrokerages(rec domain.Recommendation) []string {
+	events := rec.Events
+	if len(events) == 0 {
+		events = []domain.Stock{rec.Stock}
+	}
+	seen := map[string]bool{}
+	var brokerages []string
+	for _, event := range events {
+		if event.Brokerage != "" && !seen[event.Brokerage] {
+			seen[event.Brokerage] = true
+			brokerages = append(brokerages, event.Brokerage)
+		}
+	}
+	return brokerages
+}
+
+func exceedsQuota(counts map[string]int, keys []string, max int) bool {
+	for _, key := range keys {
+		if counts[key] >= max {
+			return true
+		}
+	}
+	return false
+}
+
+func explainAggregate(latest domain.Recommendation, aggregate domain.TickerAggregate, breakdown domain.ScoreBreakdown) string {
+	parts := []string{
+		fmt.Sprintf("%d events from %d brokerages (%d upgrades, %d downgrades)",
+			aggregate.EventCount, aggregate.Brokerages, aggregate.Upgrades, aggregate.Downgrades),
+		fmt.Sprintf("weighted target change %+.2f%%", aggregate.WeightedTargetChange),
+		fmt.Sprintf("consensus shift %+.2f steps", aggregate.ConsensusShift),
+		fmt.Sprintf("action total %+.1f", breakdown.ActionImpact),
+		fmt.Sprintf("avg brokerage weight ×%.2f", breakdown.BrokerageWeight),
+	}
+	if breakdown.DecayFactor != 1 {
+		parts = append(parts, fmt.Sprintf("avg decay ×%.2f", breakdown.DecayFactor))
+	}
+	parts = append(parts, "latest: "+latest.Breakdown.Rationale)
+	return strings.Join(parts, "; ")
+}
+
+func actionTypeOf(stock domain.Stock) domain.ActionType {
+	if stock.ActionType.Valid() {
+		return stock.ActionType
+	}
+	return domain.ParseAction(stock.Action)
+}
+
+func round2(value float64) float64 {
+	return math.Round(value*100) / 100
+}
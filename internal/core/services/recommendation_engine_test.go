@@ -0,0 +1,181 @@
+package services
+
+import (
+	"math"
+	"strings"
+	"testing"
+	"time"
+
+	"recommender/internal/core/domain"
+)
+
+func tickerEvents(now time.Time) []domain.Stock {
+	return []domain.Stock{
+		{Ticker: "NVDA", Company: "NVIDIA", Brokerage: "JP Morgan", Action: "upgraded by", RatingFrom: "Hold", RatingTo: "Buy", TargetFrom: 100, TargetTo: 120, Time: now.Add(-2 * time.Hour)},
+		{Ticker: "NVDA", Company: "NVIDIA", Brokerage: "Morgan Stanley", Action: "target raised by", RatingFrom: "Buy", RatingTo: "Buy", TargetFrom: 110, TargetTo: 121, Time: now.Add(-time.Hour)},
+		{Ticker: "NVDA", Company: "NVIDIA", Brokerage: "Others", Action: "upgraded by", RatingFrom: "Sell", RatingTo: "Hold", TargetFrom: 90, TargetTo: 99, Time: now.Add(-3 * time.Hour)},
+		{Ticker: "AMD", Company: "AMD", Brokerage: "Others", Action: "downgraded by", RatingFrom: "Buy", RatingTo: "Hold", TargetFrom: 100, TargetTo: 95, Time: now.Add(-time.Hour)},
+	}
+}
+
+func TestBuildRecommendations_GroupsByTicker(t *testing.T) {
+	now := time.Date(2024, 6, 30, 12, 0, 0, 0, time.UTC)
+	params := DefaultRecommendationParams()
+
+	recommendations := buildRecommendations(tickerEvents(now), params, DefaultScorer{}, now, nil)
+
+	if len(recommendations) != 2 {
+		t.Fatalf("expected one recommendation per ticker, got %d", len(recommendations))
+	}
+	top := recommendations[0]
+	if top.Ticker != "NVDA" || top.Rank != 1 {
+		t.Fatalf("expected NVDA first, got %+v", top)
+	}
+
+	aggregate := top.Aggregate
+	if aggregate == nil || aggregate.EventCount != 3 || aggregate.Brokerages != 3 {
+		t.Fatalf("unexpected aggregate: %+v", aggregate)
+	}
+	if aggregate.Upgrades != 2 || aggregate.Downgrades != 0 {
+		t.Errorf("expected 2 upgrades and 0 downgrades, got %+v", aggregate)
+	}
+	if aggregate.ConsensusShift <= 0 || aggregate.WeightedTargetChange <= 0 {
+		t.Errorf("expected positive consensus shift and target change, got %+v", aggregate)
+	}
+	if top.Stock.Brokerage != "Morgan Stanley" || len(top.Events) != 3 {
+		t.Errorf("expected latest event as representative and all events attached, got %+v", top)
+	}
+	if !strings.Contains(top.Breakdown.Rationale, "3 events from 3 brokerages") {
+		t.Errorf("unexpected rationale: %s", top.Breakdown.Rationale)
+	}
+}
+
+func TestBuildRecommendations_GroupByEvent(t *testing.T) {
+	now := time.Date(2024, 6, 30, 12, 0, 0, 0, time.UTC)
+	params := DefaultRecommendationParams()
+	params.GroupBy = domain.GroupByEvent
+
+	recommendations := buildRecommendations(tickerEvents(now), params, DefaultScorer{}, now, nil)
+
+	if len(recommendations) != 4 {
+		t.Fatalf("expected one recommendation per event, got %d", len(recommendations))
+	}
+	for _, rec := range recommendations {
+		if rec.Aggregate != nil {
+			t.Errorf("expected no aggregate in event mode, got %+v", rec.Aggregate)
+		}
+	}
+}
+
+func TestBuildRecommendations_SingleEventKeepsBreakdown(t *testing.T) {
+	now := time.Date(2024, 6, 30, 12, 0, 0, 0, time.UTC)
+	event := tickerEvents(now)[3]
+
+	recommendations := buildRecommendations([]domain.Stock{event}, DefaultRecommendationParams(), DefaultScorer{}, now, nil)
+	expected := (DefaultScorer{}).Score(event, now)
+
+	if math.Abs(recommendations[0].Score-expected.Total) > 1e-9 {
+		t.Errorf("expected %v, got %v", expected.Total, recommendations[0].Score)
+	}
+	if recommendations[0].Breakdown.Rationale != expected.Rationale {
+		t.Errorf("expected event rationale, got %q", recommendations[0].Breakdown.Rationale)
+	}
+}
+
+func TestGetTopRecommendedStocks_DeduplicatesTickers(t *testing.T) {
+	now := time.Now()
+	service := NewStockService(&mockStockRepository{stocks: tickerEvents(now)}, nil)
+
+	top, err := service.GetTopRecommendedStocks(DefaultRecommendationParams())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	seen := map[string]bool{}
+	for _, item := range top.Items {
+		if seen[item.Ticker] {
+			t.Errorf("ticker %s appears more than once", item.Ticker)
+		}
+		seen[item.Ticker] = true
+	}
+
+	params := DefaultRecommendationParams()
+	params.GroupBy = "sector"
+	if _, err := service.GetTopRecommendedStocks(params); err == nil {
+		t.Errorf("expected invalid group_by to fail")
+	}
+}
+
+func TestBuildRecommendations_DiversifiesBySectorAndBrokerage(t *testing.T) {
+	now := time.Date(2024, 6, 30, 12, 0, 0, 0, time.UTC)
+	events := []domain.Stock{
+		{Ticker: "NVDA", Brokerage: "JP Morgan", TargetFrom: 100, TargetTo: 150, Time: now},
+		{Ticker: "AMD", Brokerage: "Morgan Stanley", TargetFrom: 100, TargetTo: 140, Time: now},
+		{Ticker: "XOM", Brokerage: "JP Morgan", TargetFrom: 100, TargetTo: 130, Time: now},
+		{Ticker: "CVX", Brokerage: "Others", TargetFrom: 100, TargetTo: 120, Time: now},
+		{Ticker: "KO", Brokerage: "Others", TargetFrom: 100, TargetTo: 110, Time: now},
+	}
+	sectors := map[string]string{"NVDA": "Technology", "AMD": "Technology", "XOM": "Energy", "CVX": "Energy"}
+	lookup := func(ticker string) string { return sectors[ticker] }
+
+	params := DefaultRecommendationParams()
+	params.Limit = 3
+	params.MaxPerSector = 1
+	params.MaxPerBrokerage = 1
+
+	recommendations := buildRecommendations(events, params, DefaultScorer{}, now, lookup)
+
+	var tickers []string
+	for _, rec := range recommendations {
+		tickers = append(tickers, rec.Ticker)
+	}
+	// AMD queda fuera por sector y XOM por corredora
+	if strings.Join(tickers, ",") != "NVDA,CVX" {
+		t.Fatalf("unexpected picks: %v", tickers)
+	}
+	if recommendations[0].Sector != "Technology" || recommendations[1].Rank != 2 {
+		t.Errorf("expected sector and consecutive ranks, got %+v", recommendations)
+	}
+}
+
+func TestBuildRecommendations_MinBrokerages(t *testing.T) {
+	now := time.Date(2024, 6, 30, 12, 0, 0, 0, time.UTC)
+	params := DefaultRecommendationParams()
+	params.MinBrokerages = 2
+
+	recommendations := buildRecommendations(tickerEvents(now), params, DefaultScorer{}, now, nil)
+
+	if len(recommendations) != 1 || recommendations[0].Ticker != "NVDA" {
+		t.Fatalf("expected only NVDA to be backed by two brokerages, got %+v", recommendations)
+	}
+}
+
+// contrarianScorer invierte la puntuación por defecto: premia los downgrades.
+type contrarianScorer struct{}
+
+func (contrarianScorer) Name() string    { return "contrarian" }
+func (contrarianScorer) Version() string { return "test" }
+func (contrarianScorer) Score(stock domain.Stock, asOf time.Time) domain.ScoreBreakdown {
+	breakdown := (DefaultScorer{}).Score(stock, asOf)
+	breakdown.Total = -breakdown.Total
+	return breakdown
+}
+
+func TestBuildRecommendations_GroupByTickerUsesScorerTotals(t *testing.T) {
+	now := time.Date(2024, 6, 30, 12, 0, 0, 0, time.UTC)
+	params := DefaultRecommendationParams()
+	events := tickerEvents(now)
+
+	recommendations := buildRecommendations(events, params, contrarianScorer{}, now, nil)
+
+	if len(recommendations) != 2 || recommendations[0].Ticker != "AMD" {
+		t.Fatalf("expected the scorer to put AMD first, got %+v", recommendations)
+	}
+	var expected float64
+	for _, event := range events[:3] {
+		expected += contrarianScorer{}.Score(event, now).Total
+	}
+	if math.Abs(recommendations[1].Score-expected) > 1e-9 {
+		t.Errorf("expected NVDA score %v (sum of event totals), got %v", expected, recommendations[1].Score)
+	}
+}
@@ -0,0 +1,212 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"sync"
+	"time"
+
+	"recommender/internal/core/domain"
+	"recommender/internal/core/ports"
+)
+
+const feedBufferSize = 8
+
+// FeedClient recibe las actualizaciones de su lista en C. El feed cierra C al
+// desuscribirse.
+type FeedClient struct {
+	C      <-chan domain.RecommendationUpdate
+	ch     chan domain.RecommendationUpdate
+	params domain.RecommendationParams
+	last   *domain.RecommendationList // Lo último encolado: base del siguiente diff
+}
+
+// RecommendationFeed mantiene la lista de recomendaciones de cada cliente conectado y,
+// cuando el outbox registra eventos nuevos, la recalcula y envía solo los cambios de
+// ranking. Los clientes con los mismos parámetros comparten el cálculo. Como se guía
+// por el outbox, detecta también lo importado por `recommender sync` u otra instancia.
+//
+// Contrapresión: si la cola de un cliente está llena se descartan sus diffs pendientes
+// y se encola la lista completa, de modo que un cliente lento salta al estado vigente
+// en lugar de acumular diffs o bloquear al resto.
+type RecommendationFeed struct {
+	stocks       *StockService
+	outbox       ports.OutboxReader
+	pollInterval time.Duration
+	wake         chan struct{}
+	last         uint // Último ID del outbox ya reflejado en las listas; solo lo usa Poll
+
+	mu      sync.Mutex
+	clients map[*FeedClient]struct{}
+}
+
+func NewRecommendationFeed(stocks *StockService, outbox ports.OutboxReader, pollInterval time.Duration) *RecommendationFeed {
+	return &RecommendationFeed{
+		stocks:       stocks,
+		outbox:       outbox,
+		pollInterval: pollInterval,
+		wake:         make(chan struct{}, 1),
+		clients:      map[*FeedClient]struct{}{},
+	}
+}
+
+// Start fija el último evento del outbox y recalcula cuando aparecen otros nuevos,
+// hasta que ctx se cancele.
+func (f *RecommendationFeed) Start(ctx context.Context) error {
+	latest, err := f.outbox.LatestEventID()
+	if err != nil {
+		return err
+	}
+	f.last = latest
+
+	go func() {
+		ticker := time.NewTicker(f.pollInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			case <-f.wake:
+			}
+			if err := f.Poll(); err != nil {
+				log.Printf("⚠ Error leyendo el outbox para el feed de recomendaciones: %v", err)
+			}
+		}
+	}()
+	return nil
+}
+
+// Poll recalcula las listas si el outbox tiene eventos posteriores al último visto.
+// Una importación larga se refleja a lo sumo una vez por sondeo.
+func (f *RecommendationFeed) Poll() error {
+	latest, err := f.outbox.LatestEventID()
+	if err != nil || latest <= f.last {
+		return err
+	}
+	f.last = latest
+	f.Refresh()
+	return nil
+}
+
+// Subscribe calcula la lista con los parámetros indicados y la encola como snapshot.
+// Los errores de validación son los de GetTopRecommendedStocks.
+func (f *RecommendationFeed) Subscribe(params domain.RecommendationParams) (*FeedClient, error) {
+	list, err := f.stocks.GetTopRecommendedStocks(params)
+	if err != nil {
+		return nil, err
+	}
+	ch := make(chan domain.RecommendationUpdate, feedBufferSize)
+	client := &FeedClient{C: ch, ch: ch, params: list.Params}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.clients[client] = struct{}{}
+	f.resync(client, list)
+	return client, nil
+}
+
+// Update cambia los parámetros del cliente y le envía la nueva lista completa. Si los
+// parámetros son inválidos el cliente conserva los anteriores.
+func (f *RecommendationFeed) Update(client *FeedClient, params domain.RecommendationParams) error {
+	list, err := f.stocks.GetTopRecommendedStocks(params)
+	if err != nil {
+		return err
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if _, ok := f.clients[client]; !ok {
+		return nil
+	}
+	client.params = list.Params
+	f.resync(client, list)
+	return nil
+}
+
+func (f *RecommendationFeed) Unsubscribe(client *FeedClient) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if _, ok := f.clients[client]; ok {
+		delete(f.clients, client)
+		close(client.ch)
+	}
+}
+
+// OnSyncCompleted implementa ports.SyncObserver: una importación de este proceso
+// adelanta el sondeo del outbox en lugar de esperar al siguiente tick.
+func (f *RecommendationFeed) OnSyncCompleted(inserted int) {
+	if inserted == 0 {
+		return
+	}
+	select {
+	case f.wake <- struct{}{}:
+	default: // Ya hay un sondeo pendiente
+	}
+}
+
+// Refresh recalcula una vez cada combinación de parámetros suscrita y envía a cada
+// cliente el diff contra su última lista, si el ranking cambió.
+func (f *RecommendationFeed) Refresh() {
+	f.mu.Lock()
+	pending := map[string]domain.RecommendationParams{}
+	for client := range f.clients {
+		pending[feedKey(client.params)] = client.params
+	}
+	f.mu.Unlock()
+
+	lists := make(map[string]*domain.RecommendationList, len(pending))
+	for key, params := range pending {
+		list, err := f.stocks.GetTopRecommendedStocks(params)
+		if err != nil {
+			log.Printf("⚠ Error recalculando recomendaciones para el feed: %v", err)
+			continue
+		}
+		lists[key] = list
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	for client := range f.clients {
+		list, ok := lists[feedKey(client.params)]
+		if !ok || list.GeneratedAt.Before(client.last.GeneratedAt) {
+			continue // Cambió de parámetros mientras tanto o ya tiene una lista más nueva
+		}
+		diff := diffSnapshots(domain.NewRecommendationSnapshot(*client.last), domain.NewRecommendationSnapshot(*list))
+		if len(diff.Entries) == 0 && len(diff.Exits) == 0 && len(diff.Moves) == 0 {
+			continue
+		}
+		f.send(client, list, domain.RecommendationUpdate{Type: domain.FeedDiff, Diff: &diff})
+	}
+}
+
+// resync reemplaza lo pendiente por la lista completa. Debe llamarse con f.mu tomado.
+func (f *RecommendationFeed) resync(client *FeedClient, list *domain.RecommendationList) {
+	for len(client.ch) > 0 {
+		select {
+		case <-client.ch:
+		default:
+		}
+	}
+	client.ch <- domain.RecommendationUpdate{Type: domain.FeedSnapshot, List: list}
+	client.last = list
+}
+
+// send encola la actualización o, si la cola está llena, resincroniza al cliente.
+// Debe llamarse con f.mu tomado.
+func (f *RecommendationFeed) send(client *FeedClient, list *domain.RecommendationList, update domain.RecommendationUpdate) {
+	select {
+	case client.ch <- update:
+		client.last = list
+	default:
+		log.Printf("⚠ Cliente lento del feed de recomendaciones: se reemplazan %d diffs por la lista completa", len(client.ch))
+		f.resync(client, list)
+	}
+}
+
+// feedKey agrupa a los clientes con parámetros idénticos.
+func feedKey(params domain.RecommendationParams) string {
+	key, _ := json.Marshal(params)
+	return string(key)
+}
@@ -0,0 +1,110 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"recommender/internal/core/domain"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func feedFixture() (*mockStockRepository, *mockOutboxReader, *RecommendationFeed) {
+	now := time.Now()
+	repo := &mockStockRepository{stocks: []domain.Stock{
+		{Ticker: "A", TargetFrom: 100, TargetTo: 120, RatingFrom: "Sell", RatingTo: "Buy", Brokerage: "JP Morgan", Time: now},
+		{Ticker: "B", TargetFrom: 100, TargetTo: 110, RatingFrom: "Neutral", RatingTo: "Buy", Brokerage: "Others", Time: now},
+	}}
+	outbox := &mockOutboxReader{}
+	return repo, outbox, NewRecommendationFeed(NewStockService(repo, nil), outbox, time.Hour)
+}
+
+func TestRecommendationFeed_SubscribeSendsSnapshotThenDiffs(t *testing.T) {
+	repo, outbox, feed := feedFixture()
+	params := DefaultRecommendationParams()
+	params.Limit = 2
+
+	client, err := feed.Subscribe(params)
+	assert.Nil(t, err)
+	first := <-client.C
+	assert.Equal(t, domain.FeedSnapshot, first.Type)
+	assert.Equal(t, "A", first.List.Items[0].Ticker)
+
+	// Un evento nuevo que entra primero desplaza a A y saca a B del top 2. Mientras el
+	// outbox no lo registre (otro proceso que todavía no confirmó) no se recalcula.
+	stock := domain.Stock{Ticker: "C", TargetFrom: 100, TargetTo: 200, RatingFrom: "Sell", RatingTo: "Buy", Brokerage: "JP Morgan", Time: time.Now()}
+	repo.stocks = append(repo.stocks, stock)
+	assert.Nil(t, feed.Poll())
+	assert.Len(t, client.C, 0)
+
+	outbox.add(stock)
+	assert.Nil(t, feed.Poll())
+
+	update := <-client.C
+	assert.Equal(t, domain.FeedDiff, update.Type)
+	assert.Nil(t, update.List)
+	assert.Equal(t, "C", update.Diff.Entries[0].Ticker)
+	assert.Equal(t, "B", update.Diff.Exits[0].Ticker)
+	assert.Equal(t, []domain.RankMove{{Ticker: "A", FromRank: 1, ToRank: 2, Change: -1}}, update.Diff.Moves)
+
+	// Sin eventos nuevos en el outbox no se recalcula, y si el ranking no cambia no se
+	// envía nada
+	assert.Nil(t, feed.Poll())
+	feed.Refresh()
+	assert.Len(t, client.C, 0)
+
+	feed.Unsubscribe(client)
+	_, open := <-client.C
+	assert.False(t, open)
+}
+
+func TestRecommendationFeed_SlowClientGetsFullList(t *testing.T) {
+	repo, _, feed := feedFixture()
+	client, err := feed.Subscribe(DefaultRecommendationParams())
+	assert.Nil(t, err)
+
+	// El cliente no consume: cada evento nuevo cambia el primer puesto
+	for i := 0; i < feedBufferSize+2; i++ {
+		repo.stocks = append(repo.stocks, domain.Stock{
+			Ticker: string(rune('D' + i)), TargetFrom: 100, TargetTo: float64(300 + 100*i),
+			RatingFrom: "Sell", RatingTo: "Buy", Brokerage: "JP Morgan", Time: time.Now(),
+		})
+		feed.Refresh()
+	}
+
+	assert.LessOrEqual(t, len(client.C), feedBufferSize)
+	var updates []domain.RecommendationUpdate
+	for len(client.C) > 0 {
+		updates = append(updates, <-client.C)
+	}
+	// La cola se llenó con el snapshot inicial y 7 diffs; el octavo los reemplazó por la
+	// lista completa y los dos siguientes llegaron como diffs sobre ella
+	assert.Len(t, updates, 3)
+	assert.Equal(t, domain.FeedSnapshot, updates[0].Type)
+	assert.Equal(t, string(rune('D'+feedBufferSize-1)), updates[0].List.Items[0].Ticker)
+	last := updates[len(updates)-1]
+	assert.Equal(t, domain.FeedDiff, last.Type)
+	assert.Equal(t, string(rune('D'+feedBufferSize+1)), last.Diff.Entries[0].Ticker)
+}
+
+func TestRecommendationFeed_UpdateValidatesParams(t *testing.T) {
+	_, _, feed := feedFixture()
+	client, err := feed.Subscribe(DefaultRecommendationParams())
+	assert.Nil(t, err)
+	<-client.C
+
+	invalid := DefaultRecommendationParams()
+	invalid.Limit = 0
+	assert.ErrorIs(t, feed.Update(client, invalid), ErrInvalidRecommendationParams)
+	assert.Len(t, client.C, 0)
+
+	params := DefaultRecommendationParams()
+	params.Limit = 1
+	assert.Nil(t, feed.Update(client, params))
+	update := <-client.C
+	assert.Equal(t, domain.FeedSnapshot, update.Type)
+	assert.Len(t, update.List.Items, 1)
+
+	_, err = feed.Subscribe(invalid)
+	assert.ErrorIs(t, err, ErrInvalidRecommendationParams)
+}
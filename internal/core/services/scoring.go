@@ -0,0 +1,252 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"recommender/internal/core/domain"
+	"recommender/internal/core/ports"
+)
+
+// ErrUnknownStrategy se devuelve cuando `?strategy=` no corresponde a ningún Scorer registrado.
+var ErrUnknownStrategy = errors.New("unknown scoring strategy")
+
+const DefaultStrategy = "default"
+
+// ScorerRegistry guarda las estrategias de puntuación disponibles por nombre.
+type ScorerRegistry struct {
+	mu          sync.RWMutex
+	scorers     map[string]ports.Scorer
+	defaultName string
+}
+
+// NewScorerRegistry crea un registro cuya estrategia por defecto es defaultScorer.
+func NewScorerRegistry(defaultScorer ports.Scorer) *ScorerRegistry {
+	return &ScorerRegistry{
+		scorers:     map[string]ports.Scorer{defaultScorer.Name(): defaultScorer},
+		defaultName: defaultScorer.Name(),
+	}
+}
+
+// NewDefaultScorerRegistry registra la fórmula original y las alternativas incluidas.
+func NewDefaultScorerRegistry() *ScorerRegistry {
+	registry := NewScorerRegistry(DefaultScorer{})
+	registry.Register(UpsideScorer{})
+	return registry
+}
+
+// Register agrega o reemplaza una estrategia.
+func (r *ScorerRegistry) Register(scorer ports.Scorer) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.scorers[scorer.Name()] = scorer
+}
+
+// Get devuelve la estrategia pedida; un nombre vacío selecciona la estrategia por defecto.
+func (r *ScorerRegistry) Get(name string) (ports.Scorer, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	if name == "" {
+		name = r.defaultName
+	}
+	scorer, ok := r.scorers[name]
+	if !ok {
+		return nil, ErrUnknownStrategy
+	}
+	return scorer, nil
+}
+
+// Names lista las estrategias registradas en orden alfabético.
+func (r *ScorerRegistry) Names() []string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	names := make([]string, 0, len(r.scorers))
+	for name := range r.scorers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// DefaultScorer es la fórmula original, (precio + rating + acción) * peso de la corredora,
+// multiplicada por el factor de decaimiento según la antigüedad del evento.
+type DefaultScorer struct {
+	Decay   Decay                  // nil equivale a NoDecay
+	Weights ports.BrokerageWeigher // nil equivale a StaticBrokerageWeights
+	Ratings *RatingNormalizer      // nil equivale a DefaultRatingMapping
+}
+
+func (DefaultScorer) Name() string { return DefaultStrategy }
+
+func (d DefaultScorer) Version() string {
+	version := "1.1/" + d.decay().Name()
+	if d.weights().Name() != (StaticBrokerageWeights{}).Name() {
+		version += "/" + d.weights().Name()
+	}
+	return version
+}
+
+func (d DefaultScorer) Score(stock domain.Stock, asOf time.Time) domain.ScoreBreakdown {
+	age := asOf.Sub(stock.Time)
+	breakdown := domain.ScoreBreakdown{
+		PriceImpact:     calculatePriceImpact(stock),
+		RatingImpact:    calculateRatingImpact(stock, d.Ratings),
+		ActionImpact:    calculateActionImpact(stock),
+		BrokerageWeight: d.weights().Weight(stock.Brokerage),
+		DecayFactor:     d.decay().Factor(age),
+		AgeDays:         ageInDays(age),
+	}
+	breakdown.Total = (breakdown.PriceImpact + breakdown.RatingImpact + breakdown.ActionImpact) *
+		breakdown.BrokerageWeight * breakdown.DecayFactor
+	breakdown.Rationale = explainScore(stock, breakdown, d.Ratings)
+	return breakdown
+}
+
+func (d DefaultScorer) decay() Decay {
+	if d.Decay == nil {
+		return NoDecay{}
+	}
+	return d.Decay
+}
+
+func (d DefaultScorer) weights() ports.BrokerageWeigher {
+	if d.Weights == nil {
+		return StaticBrokerageWeights{}
+	}
+	return d.Weights
+}
+
+// UpsideScorer ordena solo por el cambio porcentual del precio objetivo.
+type UpsideScorer struct{}
+
+func (UpsideScorer) Name() string { return "upside" }
+
+func (UpsideScorer) Version() string { return "1.0" }
+
+func (UpsideScorer) Score(stock domain.Stock, asOf time.Time) domain.ScoreBreakdown {
+	priceImpact := calculatePriceImpact(stock)
+	return domain.ScoreBreakdown{
+		Total:           priceImpact,
+		PriceImpact:     priceImpact,
+		BrokerageWeight: 1,
+		DecayFactor:     1,
+		AgeDays:         ageInDays(asOf.Sub(stock.Time)),
+		Rationale:       fmt.Sprintf("target %s (%+.2f%%)", describeTarget(stock), priceImpact),
+	}
+}
+
+// explainScore arma la frase legible que acompaña cada recomendación.
+func explainScore(stock domain.Stock, breakdown domain.ScoreBreakdown, ratings *RatingNormalizer) string {
+	brokerage := stock.Brokerage
+	if brokerage == "" {
+		brokerage = "Unknown brokerage"
+	}
+	from := effectiveRating(stock.RatingFromScore, stock.RatingFrom, ratings)
+	to := effectiveRating(stock.RatingToScore, stock.RatingTo, ratings)
+
+	parts := []string{
+		fmt.Sprintf("%s %s", brokerage, describeAction(stock)),
+		fmt.Sprintf("rating %s → %s (%+.1f)", from, to, breakdown.RatingImpact),
+		fmt.Sprintf("target %s (%+.2f%%)", describeTarget(stock), breakdown.PriceImpact),
+	}
+	if breakdown.ActionImpact != 0 {
+		parts = append(parts, fmt.Sprintf("action %+.1f", breakdown.ActionImpact))
+	}
+	parts = append(parts, fmt.Sprintf("brokerage weight ×%.2f", breakdown.BrokerageWeight))
+	if breakdown.DecayFactor != 1 {
+		parts = append(parts, fmt.Sprintf("decay ×%.2f (%.1f days old)", breakdown.DecayFactor, breakdown.AgeDays))
+	}
+	return strings.Join(parts, "; ")
+}
+
+// ageInDays redondea a un decimal; los eventos futuros cuentan como recién publicados.
+func ageInDays(age time.Duration) float64 {
+	if age <= 0 {
+		return 0
+	}
+	return math.Round(age.Hours()/24*10) / 10
+}
+
+func describeAction(stock domain.Stock) string {
+	return strings.ReplaceAll(string(actionTypeOf(stock)), "_", " ")
+}
+
+func describeTarget(stock domain.Stock) string {
+	return fmt.Sprintf("$%.2f → $%.2f", stock.TargetFrom, stock.TargetTo)
+}
+
+// calculatePriceImpact calcula el impacto por cambio de precio
+func calculatePriceImpact(stock domain.Stock) float64 {
+	if stock.TargetFrom == 0 {
+		return 0 // Sin precio previo no hay cambio porcentual que medir
+	}
+	percentageChange := (stock.TargetTo - stock.TargetFrom) / stock.TargetFrom * 100
+	return math.Round(percentageChange*100) / 100 // Redondeo a dos decimales
+}
+
+// calculateRatingImpact evalúa el impacto por cambio de rating en la escala normalizada.
+// Cada escalón vale 2 puntos, de modo que Sell -> Buy sigue sumando 4.
+func calculateRatingImpact(stock domain.Stock, ratings *RatingNormalizer) float64 {
+	fromScore := effectiveRating(stock.RatingFromScore, stock.RatingFrom, ratings)
+	toScore := effectiveRating(stock.RatingToScore, stock.RatingTo, ratings)
+	return float64(toScore-fromScore) * 2
+}
+
+// effectiveRating usa el puntaje almacenado y, para filas anteriores a la normalización,
+// el mapeo configurado en ratings (o el por defecto si es nil). Las etiquetas
+// desconocidas cuentan como Hold.
+func effectiveRating(score domain.RatingScore, label string, ratings *RatingNormalizer) domain.RatingScore {
+	if score.Known() {
+		return score
+	}
+	mapping := defaultRatingMapping
+	if ratings != nil {
+		mapping = ratings.mapping
+	}
+	if mapped, ok := mapping[normalizeRatingLabel(label)]; ok {
+		return mapped
+	}
+	return domain.RatingHold
+}
+
+// ratingsOf devuelve el mapeo de ratings de la estrategia, o nil si usa el por defecto.
+func ratingsOf(scorer ports.Scorer) *RatingNormalizer {
+	if d, ok := scorer.(DefaultScorer); ok {
+		return d.Ratings
+	}
+	return nil
+}
+
+// calculateActionImpact puntúa el tipo de acción; las filas sin clasificar se clasifican al vuelo.
+func calculateActionImpact(stock domain.Stock) float64 {
+	actionScores := map[domain.ActionType]float64{
+		domain.ActionUpgrade:       2,
+		domain.ActionDowngrade:     -2,
+		domain.ActionInitiate:      1,
+		domain.ActionTargetRaised:  1,
+		domain.ActionTargetLowered: -1,
+		domain.ActionReiterate:     0,
+		domain.ActionOther:         0,
+	}
+	return actionScores[actionTypeOf(stock)]
+}
+
+// getBrokerageWeight devuelve el peso asociado a la corredora
+func getBrokerageWeight(brokerage string) float64 {
+	weights := map[string]float64{
+		"The Goldman Sachs Group": 1.5,
+		"JP Morgan":               1.4,
+		"Morgan Stanley":          1.3,
+		"Others":                  1.0,
+	}
+	weight, exists := weights[brokerage]
+	if !exists {
+		return weights["Others"]
+	}
+	return weight
+}
@@ -0,0 +1,111 @@
+package services
+
+import (
+	"errors"
+	"math"
+	"strings"
+	"testing"
+	"time"
+
+	"recommender/internal/core/domain"
+)
+
+type tickerScorer struct{}
+
+func (tickerScorer) Name() string { return "alphabetical" }
+
+func (tickerScorer) Version() string { return "test" }
+
+func (tickerScorer) Score(stock domain.Stock, asOf time.Time) domain.ScoreBreakdown {
+	return domain.ScoreBreakdown{Total: -float64(stock.Ticker[0]), Rationale: "alphabetical"}
+}
+
+func TestScorerRegistry_Get(t *testing.T) {
+	registry := NewDefaultScorerRegistry()
+
+	scorer, err := registry.Get("")
+	if err != nil || scorer.Name() != DefaultStrategy {
+		t.Fatalf("expected default scorer, got %v, %v", scorer, err)
+	}
+
+	if _, err := registry.Get("missing"); !errors.Is(err, ErrUnknownStrategy) {
+		t.Errorf("expected ErrUnknownStrategy, got %v", err)
+	}
+
+	registry.Register(tickerScorer{})
+	names := registry.Names()
+	if len(names) != 3 || names[0] != "alphabetical" {
+		t.Errorf("unexpected names: %v", names)
+	}
+}
+
+func TestDefaultAndUpsideScorers(t *testing.T) {
+	stock := domain.Stock{TargetFrom: 100, TargetTo: 125, RatingFrom: "Hold", RatingTo: "Buy", Brokerage: "JP Morgan"}
+	breakdown := (DefaultScorer{}).Score(stock, stock.Time)
+	want := (breakdown.PriceImpact + breakdown.RatingImpact + breakdown.ActionImpact) * breakdown.BrokerageWeight
+	if breakdown.DecayFactor != 1 || breakdown.Total != want {
+		t.Errorf("expected the undecayed formula %v, got %+v", want, breakdown)
+	}
+	if upside := (UpsideScorer{}).Score(stock, stock.Time).Total; upside != 25 {
+		t.Errorf("expected upside 25, got %v", upside)
+	}
+}
+
+func TestGetTopRecommendedStocks_WithStrategy(t *testing.T) {
+	now := time.Now()
+	repo := &mockStockRepository{
+		stocks: []domain.Stock{
+			{Ticker: "Z", TargetFrom: 100, TargetTo: 200, RatingFrom: "Sell", RatingTo: "Buy", Time: now},
+			{Ticker: "A", TargetFrom: 100, TargetTo: 90, RatingFrom: "Buy", RatingTo: "Sell", Time: now},
+		},
+	}
+	service := NewStockService(repo, nil)
+	service.RegisterScorer(tickerScorer{})
+
+	params := DefaultRecommendationParams()
+	params.Limit = 1
+	params.Strategy = "alphabetical"
+	top, err := service.GetTopRecommendedStocks(params)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if top.Items[0].Stock.Ticker != "A" || top.Strategy != "alphabetical" || top.ScorerVersion != "test" {
+		t.Errorf("unexpected alphabetical recommendations: %+v", top)
+	}
+
+	params.Strategy = "missing"
+	if _, err := service.GetTopRecommendedStocks(params); !errors.Is(err, ErrUnknownStrategy) {
+		t.Errorf("expected ErrUnknownStrategy, got %v", err)
+	}
+}
+
+func TestDefaultScorer_Breakdown(t *testing.T) {
+	stock := domain.Stock{
+		Brokerage:  "JP Morgan",
+		Action:     "upgraded by",
+		RatingFrom: "Neutral",
+		RatingTo:   "Buy",
+		TargetFrom: 100,
+		TargetTo:   110,
+	}
+
+	breakdown := (DefaultScorer{}).Score(stock, stock.Time)
+
+	if breakdown.PriceImpact != 10 || breakdown.RatingImpact != 2 || breakdown.ActionImpact != 2 {
+		t.Errorf("unexpected components: %+v", breakdown)
+	}
+	if breakdown.BrokerageWeight != 1.4 || math.Abs(breakdown.Total-19.6) > 1e-9 {
+		t.Errorf("unexpected total: %+v", breakdown)
+	}
+	for _, fragment := range []string{"JP Morgan upgrade", "Hold → Buy", "+10.00%", "×1.40"} {
+		if !strings.Contains(breakdown.Rationale, fragment) {
+			t.Errorf("expected rationale to contain %q, got %q", fragment, breakdown.Rationale)
+		}
+	}
+}
+
+func TestCalculatePriceImpact_ZeroTargetFrom(t *testing.T) {
+	if impact := calculatePriceImpact(domain.Stock{TargetFrom: 0, TargetTo: 50}); impact != 0 {
+		t.Errorf("expected 0 impact without previous target, got %v", impact)
+	}
+}
@@ -0,0 +1,194 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"recommender/internal/core/domain"
+	"recommender/internal/core/ports"
+
+	"gorm.io/gorm"
+)
+
+var (
+	// ErrInvalidSnapshotRef indica que `from`/`to` no es un ID ni una fecha válida.
+	ErrInvalidSnapshotRef = errors.New("invalid snapshot reference")
+	ErrSnapshotNotFound   = errors.New("snapshot not found")
+)
+
+// SnapshotService guarda las listas de recomendaciones calculadas cuando cambia el
+// ranking y permite compararlas.
+type SnapshotService struct {
+	repository ports.SnapshotRepository
+	defaults   domain.RecommendationParams // Parámetros de los diffs por fecha
+	now        func() time.Time
+
+	mu   sync.Mutex
+	last map[string]domain.RecommendationSnapshot // Último guardado por clave de parámetros
+}
+
+func NewSnapshotService(repo ports.SnapshotRepository) *SnapshotService {
+	return &SnapshotService{
+		repository: repo,
+		defaults:   DefaultRecommendationParams(),
+		now:        time.Now,
+		last:       map[string]domain.RecommendationSnapshot{},
+	}
+}
+
+// WithDefaults fija los parámetros de recomendación configurados: una referencia por
+// fecha busca el último snapshot calculado con ellos.
+func (s *SnapshotService) WithDefaults(params domain.RecommendationParams) *SnapshotService {
+	s.defaults = params
+	return s
+}
+
+// Record guarda la lista tal como se devolvió al cliente, salvo que tenga el mismo
+// ranking que el último snapshot con iguales parámetros y versión del scorer: cada
+// consulta recalcula la lista, pero solo hace falta guardarla cuando algo cambió. Las
+// puntuaciones no cuentan, porque el decaimiento las mueve en cada consulta.
+func (s *SnapshotService) Record(list domain.RecommendationList) error {
+	snapshot := domain.NewRecommendationSnapshot(list)
+	key := snapshot.ParamsKey
+
+	s.mu.Lock()
+	previous, ok := s.last[key]
+	s.mu.Unlock()
+	if !ok {
+		// Tras reiniciar, el último guardado sale de la base
+		if latest, err := s.repository.GetLatestAt(key, s.now()); err == nil {
+			previous, ok = *latest, true
+		}
+	}
+	if ok && sameRanking(previous, snapshot) {
+		s.remember(previous)
+		return nil
+	}
+	if err := s.repository.Save(&snapshot); err != nil {
+		return err
+	}
+	s.remember(snapshot)
+	return nil
+}
+
+// remember guarda el último snapshot de sus parámetros, salvo que ya haya uno más nuevo.
+func (s *SnapshotService) remember(snapshot domain.RecommendationSnapshot) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if current, ok := s.last[snapshot.ParamsKey]; ok && current.GeneratedAt.After(snapshot.GeneratedAt) {
+		return
+	}
+	s.last[snapshot.ParamsKey] = snapshot
+}
+
+// sameRanking compara la versión del scorer y los tickers en orden.
+func sameRanking(a, b domain.RecommendationSnapshot) bool {
+	if a.ScorerVersion != b.ScorerVersion || len(a.Items) != len(b.Items) {
+		return false
+	}
+	for i := range a.Items {
+		if a.Items[i].Ticker != b.Items[i].Ticker {
+			return false
+		}
+	}
+	return true
+}
+
+func (s *SnapshotService) List(strategy string, limit, offset int) ([]domain.RecommendationSnapshot, error) {
+	return s.repository.List(strategy, limit, offset)
+}
+
+// Diff compara dos snapshots. Cada referencia es un ID o una fecha (RFC3339 o
+// YYYY-MM-DD, que toma el último snapshot de ese día); `to` vacío usa el último snapshot.
+// Las fechas solo buscan entre los snapshots calculados con los parámetros configurados
+// y la estrategia indicada (vacía: la configurada), para no comparar listas distintas.
+func (s *SnapshotService) Diff(fromRef, toRef, strategy string) (*domain.RecommendationDiff, error) {
+	if strings.TrimSpace(toRef) == "" {
+		toRef = s.now().Format(time.RFC3339Nano)
+	}
+	from, err := s.resolve(fromRef, strategy)
+	if err != nil {
+		return nil, err
+	}
+	to, err := s.resolve(toRef, strategy)
+	if err != nil {
+		return nil, err
+	}
+	diff := diffSnapshots(*from, *to)
+	return &diff, nil
+}
+
+func (s *SnapshotService) resolve(ref, strategy string) (*domain.RecommendationSnapshot, error) {
+	ref = strings.TrimSpace(ref)
+	if strategy = strings.TrimSpace(strategy); strategy == "" {
+		strategy = s.defaults.Strategy
+	}
+	if strategy == "" {
+		strategy = DefaultStrategy
+	}
+	key := domain.SnapshotParamsKey(s.defaults, strategy)
+	var (
+		snapshot *domain.RecommendationSnapshot
+		err      error
+	)
+	if id, parseErr := strconv.ParseUint(ref, 10, 64); parseErr == nil {
+		snapshot, err = s.repository.GetByID(uint(id))
+	} else if at, parseErr := time.Parse(time.RFC3339Nano, ref); parseErr == nil {
+		snapshot, err = s.repository.GetLatestAt(key, at)
+	} else if day, parseErr := time.Parse("2006-01-02", ref); parseErr == nil {
+		snapshot, err = s.repository.GetLatestAt(key, day.AddDate(0, 0, 1).Add(-time.Nanosecond))
+	} else {
+		return nil, fmt.Errorf("%w: '%s'", ErrInvalidSnapshotRef, ref)
+	}
+
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, fmt.Errorf("%w: '%s'", ErrSnapshotNotFound, ref)
+	}
+	return snapshot, err
+}
+
+// diffSnapshots lista las entradas y los movimientos en el orden del ranking de `to`
+// y las salidas en el orden que tenían en `from`.
+func diffSnapshots(from, to domain.RecommendationSnapshot) domain.RecommendationDiff {
+	diff := domain.RecommendationDiff{
+		From:    domain.SnapshotRef{ID: from.ID, Strategy: from.Strategy, GeneratedAt: from.GeneratedAt},
+		To:      domain.SnapshotRef{ID: to.ID, Strategy: to.Strategy, GeneratedAt: to.GeneratedAt},
+		Entries: []domain.SnapshotItem{},
+		Exits:   []domain.SnapshotItem{},
+		Moves:   []domain.RankMove{},
+	}
+
+	previous := make(map[string]domain.SnapshotItem, len(from.Items))
+	for _, item := range from.Items {
+		previous[item.Ticker] = item
+	}
+	current := make(map[string]bool, len(to.Items))
+
+	for _, item := range to.Items {
+		current[item.Ticker] = true
+		old, ok := previous[item.Ticker]
+		switch {
+		case !ok:
+			diff.Entries = append(diff.Entries, item)
+		case old.Rank == item.Rank:
+			diff.Unchanged++
+		default:
+			diff.Moves = append(diff.Moves, domain.RankMove{
+				Ticker:   item.Ticker,
+				FromRank: old.Rank,
+				ToRank:   item.Rank,
+				Change:   old.Rank - item.Rank,
+			})
+		}
+	}
+	for _, item := range from.Items {
+		if !current[item.Ticker] {
+			diff.Exits = append(diff.Exits, item)
+		}
+	}
+	return diff
+}
@@ -0,0 +1,165 @@
+package services
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"recommender/internal/core/domain"
+
+	"gorm.io/gorm"
+)
+
+type mockSnapshotRepository struct {
+	snapshots []domain.RecommendationSnapshot
+}
+
+func (m *mockSnapshotRepository) Save(snapshot *domain.RecommendationSnapshot) error {
+	snapshot.ID = uint(len(m.snapshots) + 1)
+	m.snapshots = append(m.snapshots, *snapshot)
+	return nil
+}
+
+func (m *mockSnapshotRepository) List(strategy string, limit, offset int) ([]domain.RecommendationSnapshot, error) {
+	var result []domain.RecommendationSnapshot
+	for i := len(m.snapshots) - 1; i >= 0; i-- {
+		if strategy == "" || m.snapshots[i].Strategy == strategy {
+			result = append(result, m.snapshots[i])
+		}
+	}
+	return result, nil
+}
+
+func (m *mockSnapshotRepository) GetByID(id uint) (*domain.RecommendationSnapshot, error) {
+	for _, snapshot := range m.snapshots {
+		if snapshot.ID == id {
+			return &snapshot, nil
+		}
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+func (m *mockSnapshotRepository) GetLatestAt(paramsKey string, at time.Time) (*domain.RecommendationSnapshot, error) {
+	var latest *domain.RecommendationSnapshot
+	for i, snapshot := range m.snapshots {
+		if snapshot.GeneratedAt.After(at) || snapshot.ParamsKey != paramsKey {
+			continue
+		}
+		if latest == nil || !snapshot.GeneratedAt.Before(latest.GeneratedAt) {
+			latest = &m.snapshots[i]
+		}
+	}
+	if latest == nil {
+		return nil, gorm.ErrRecordNotFound
+	}
+	return latest, nil
+}
+
+func snapshotList(at time.Time, tickers ...string) domain.RecommendationList {
+	list := domain.RecommendationList{Strategy: "default", GeneratedAt: at, Params: DefaultRecommendationParams()}
+	for i, ticker := range tickers {
+		list.Items = append(list.Items, domain.Recommendation{Rank: i + 1, Ticker: ticker, Score: float64(10 - i)})
+	}
+	return list
+}
+
+func TestSnapshotService_Diff(t *testing.T) {
+	repo := &mockSnapshotRepository{}
+	service := NewSnapshotService(repo)
+	monday := time.Date(2024, 3, 4, 9, 0, 0, 0, time.UTC)
+
+	if err := service.Record(snapshotList(monday, "AAPL", "MSFT", "TSLA", "NVDA")); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	service.Record(snapshotList(monday.AddDate(0, 0, 1), "MSFT", "AAPL", "TSLA", "AMZN"))
+	// Una lista con otros parámetros (feed, otra ventana) del mismo día no entra en el diff
+	narrow := snapshotList(monday.AddDate(0, 0, 1).Add(time.Hour), "GOOG")
+	narrow.Params.Limit = 1
+	service.Record(narrow)
+
+	diff, err := service.Diff("1", "2024-03-05", "")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if diff.From.ID != 1 || diff.To.ID != 2 {
+		t.Fatalf("unexpected snapshots: %+v -> %+v", diff.From, diff.To)
+	}
+	if len(diff.Entries) != 1 || diff.Entries[0].Ticker != "AMZN" {
+		t.Errorf("expected AMZN to enter, got %+v", diff.Entries)
+	}
+	if len(diff.Exits) != 1 || diff.Exits[0].Ticker != "NVDA" {
+		t.Errorf("expected NVDA to exit, got %+v", diff.Exits)
+	}
+	if len(diff.Moves) != 2 || diff.Moves[0].Ticker != "MSFT" || diff.Moves[0].Change != 1 || diff.Moves[1].Change != -1 {
+		t.Errorf("unexpected moves: %+v", diff.Moves)
+	}
+	if diff.Unchanged != 1 {
+		t.Errorf("expected TSLA unchanged, got %d", diff.Unchanged)
+	}
+}
+
+func TestSnapshotService_Diff_InvalidRefs(t *testing.T) {
+	service := NewSnapshotService(&mockSnapshotRepository{})
+
+	if _, err := service.Diff("last tuesday", "", ""); !errors.Is(err, ErrInvalidSnapshotRef) {
+		t.Errorf("expected ErrInvalidSnapshotRef, got %v", err)
+	}
+	if _, err := service.Diff("7", "", ""); !errors.Is(err, ErrSnapshotNotFound) {
+		t.Errorf("expected ErrSnapshotNotFound, got %v", err)
+	}
+}
+
+func TestGetTopRecommendedStocks_RecordsSnapshot(t *testing.T) {
+	now := time.Now()
+	repo := &mockStockRepository{stocks: []domain.Stock{
+		{Ticker: "A", TargetFrom: 100, TargetTo: 120, Brokerage: "JP Morgan", Time: now},
+	}}
+	snapshots := &mockSnapshotRepository{}
+	service := NewStockService(repo, nil).WithSnapshots(NewSnapshotService(snapshots))
+
+	if _, err := service.GetTopRecommendedStocks(DefaultRecommendationParams()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(snapshots.snapshots) != 1 {
+		t.Fatalf("expected one snapshot, got %d", len(snapshots.snapshots))
+	}
+	stored := snapshots.snapshots[0]
+	if stored.Strategy != DefaultStrategy || len(stored.Items) != 1 || stored.Items[0].Ticker != "A" {
+		t.Errorf("unexpected snapshot: %+v", stored)
+	}
+}
+
+func TestSnapshotService_RecordSkipsUnchangedRanking(t *testing.T) {
+	repo := &mockSnapshotRepository{}
+	service := NewSnapshotService(repo)
+	monday := time.Date(2024, 3, 4, 9, 0, 0, 0, time.UTC)
+	service.now = func() time.Time { return monday.AddDate(0, 0, 7) }
+
+	service.Record(snapshotList(monday, "AAPL", "MSFT"))
+	same := snapshotList(monday.Add(time.Hour), "AAPL", "MSFT")
+	same.Items[0].Score = 9.5 // El decaimiento cambia las puntuaciones, no el ranking
+	service.Record(same)
+	if len(repo.snapshots) != 1 {
+		t.Fatalf("expected the unchanged ranking to be skipped, got %d snapshots", len(repo.snapshots))
+	}
+
+	service.Record(snapshotList(monday.Add(2*time.Hour), "MSFT", "AAPL"))
+	if len(repo.snapshots) != 2 {
+		t.Fatalf("expected a new snapshot when the ranking changes, got %d", len(repo.snapshots))
+	}
+
+	// Un proceso nuevo compara contra el último snapshot guardado
+	restarted := NewSnapshotService(repo)
+	restarted.now = service.now
+	restarted.Record(snapshotList(monday.Add(3*time.Hour), "MSFT", "AAPL"))
+	if len(repo.snapshots) != 2 {
+		t.Errorf("expected no snapshot after restarting with the same ranking, got %d", len(repo.snapshots))
+	}
+
+	other := snapshotList(monday.Add(3*time.Hour), "MSFT", "AAPL")
+	other.Params.Limit = 1
+	restarted.Record(other)
+	if len(repo.snapshots) != 3 {
+		t.Errorf("expected a separate snapshot for other params, got %d", len(repo.snapshots))
+	}
+}
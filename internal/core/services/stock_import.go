@@ -0,0 +1,197 @@
+package services
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"recommender/internal/core/domain"
+)
+
+// Formatos aceptados por la importación masiva.
+const (
+	ImportFormatCSV    = "csv"
+	ImportFormatNDJSON = "ndjson"
+)
+
+var ErrUnknownImportFormat = errors.New("unknown import format")
+
+// ImportFormatFromPath deduce el formato por la extensión: .csv, .ndjson o .jsonl.
+func ImportFormatFromPath(path string) string {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".csv":
+		return ImportFormatCSV
+	case ".ndjson", ".jsonl":
+		return ImportFormatNDJSON
+	default:
+		return ""
+	}
+}
+
+// ImportStocks importa eventos desde un archivo CSV (cabecera con los nombres de
+// StockDTO) o NDJSON (un StockDTO por línea). Cada fila pasa por la misma conversión
+// que los datos de la API; las inválidas y las repetidas (en el archivo o en la base)
+// se reportan con su número de línea sin cortar la importación. Con dryRun no se
+// escribe nada. Un error de lectura o de la base corta la importación y se devuelve
+// junto con el reporte parcial.
+func (s *StockService) ImportStocks(r io.Reader, format string, dryRun bool) (*domain.ImportReport, error) {
+	report := &domain.ImportReport{
+		DryRun:     dryRun,
+		Duplicates: []domain.ImportLineError{},
+		Invalid:    []domain.ImportLineError{},
+	}
+	seen := map[string]int{}
+
+	err := ReadStockRecords(r, format, func(line int, dto domain.StockDTO, rowErr error) error {
+		var stock domain.Stock
+		if rowErr == nil {
+			stock, rowErr = dto.ToStock()
+		}
+		if rowErr != nil {
+			report.Invalid = append(report.Invalid, domain.ImportLineError{Line: line, Error: rowErr.Error()})
+			return nil
+		}
+
+		if stock.Source == "" {
+			stock.Source = domain.SourceImport
+		}
+		key := stock.Ticker + "|" + stock.Time.UTC().Format(time.RFC3339Nano)
+		if stock.ExternalID != "" {
+			key = stock.Source + "|" + stock.ExternalID
+		}
+		if first, ok := seen[key]; ok {
+			report.Duplicates = append(report.Duplicates, domain.ImportLineError{Line: line, Error: fmt.Sprintf("repetido en el archivo (línea %d)", first)})
+			return nil
+		}
+		seen[key] = line
+
+		existing, err := s.findExisting(stock)
+		if err != nil {
+			return fmt.Errorf("línea %d: %w", line, err)
+		}
+		if existing != nil {
+			report.Duplicates = append(report.Duplicates, domain.ImportLineError{Line: line, Error: "ya existe en la base de datos"})
+			return nil
+		}
+
+		if !dryRun {
+			s.classify(&stock)
+			if err := s.repository.Create(&stock); err != nil {
+				return fmt.Errorf("línea %d: %w", line, err)
+			}
+			s.syncCompany(stock)
+			s.notifyCreated(stock)
+		}
+		report.Inserted++
+		return nil
+	})
+
+	if !dryRun {
+		s.notifySynced(report.Inserted)
+	}
+	return report, err
+}
+
+// ReadStockRecords recorre el archivo y entrega cada fila con su número de línea. Los
+// errores propios de una fila (JSON o CSV mal formado) llegan en rowErr y no cortan la
+// lectura; los del archivo (formato, cabecera, lectura) y los de fn se devuelven.
+func ReadStockRecords(r io.Reader, format string, fn func(line int, dto domain.StockDTO, rowErr error) error) error {
+	switch format {
+	case ImportFormatCSV:
+		return readStockCSV(r, fn)
+	case ImportFormatNDJSON:
+		return readStockNDJSON(r, fn)
+	default:
+		return fmt.Errorf("%w '%s'", ErrUnknownImportFormat, format)
+	}
+}
+
+func readStockCSV(r io.Reader, fn func(line int, dto domain.StockDTO, rowErr error) error) error {
+	reader := csv.NewReader(r)
+	reader.TrimLeadingSpace = true
+	reader.FieldsPerRecord = -1 // El largo de cada fila se valida abajo para reportarla
+
+	header, err := reader.Read()
+	if err != nil {
+		return fmt.Errorf("ReadStockRecords: error leyendo cabecera: %w", err)
+	}
+	columns := make(map[string]int, len(header))
+	for i, name := range header {
+		columns[strings.ToLower(strings.TrimSpace(name))] = i
+	}
+	for _, required := range []string{"ticker", "target_from", "target_to", "time"} {
+		if _, ok := columns[required]; !ok {
+			return fmt.Errorf("ReadStockRecords: falta la columna '%s'", required)
+		}
+	}
+	field := func(record []string, name string) string {
+		if i, ok := columns[name]; ok {
+			return strings.TrimSpace(record[i])
+		}
+		return ""
+	}
+
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			return nil
+		}
+		var parseErr *csv.ParseError
+		if errors.As(err, &parseErr) {
+			if err := fn(parseErr.StartLine, domain.StockDTO{}, parseErr.Err); err != nil {
+				return err
+			}
+			continue
+		}
+		if err != nil {
+			return err
+		}
+
+		line, _ := reader.FieldPos(0)
+		if len(record) != len(header) {
+			if err := fn(line, domain.StockDTO{}, fmt.Errorf("se esperaban %d columnas y hay %d", len(header), len(record))); err != nil {
+				return err
+			}
+			continue
+		}
+		dto := domain.StockDTO{
+			Ticker:     field(record, "ticker"),
+			TargetFrom: field(record, "target_from"),
+			TargetTo:   field(record, "target_to"),
+			Company:    field(record, "company"),
+			Brokerage:  field(record, "brokerage"),
+			Action:     field(record, "action"),
+			RatingFrom: field(record, "rating_from"),
+			RatingTo:   field(record, "rating_to"),
+			Time:       field(record, "time"),
+			Source:     field(record, "source"),
+			ExternalID: field(record, "external_id"),
+		}
+		if err := fn(line, dto, nil); err != nil {
+			return err
+		}
+	}
+}
+
+func readStockNDJSON(r io.Reader, fn func(line int, dto domain.StockDTO, rowErr error) error) error {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for line := 1; scanner.Scan(); line++ {
+		raw := strings.TrimSpace(scanner.Text())
+		if raw == "" {
+			continue
+		}
+		var dto domain.StockDTO
+		rowErr := json.Unmarshal([]byte(raw), &dto)
+		if err := fn(line, dto, rowErr); err != nil {
+			return err
+		}
+	}
+	return scanner.Err()
+}
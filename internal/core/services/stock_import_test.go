@@ -0,0 +1,93 @@
+package services
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"recommender/internal/core/domain"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const importCSV = `ticker,target_from,target_to,company,brokerage,action,rating_from,rating_to,time
+AAPL,$150.00,$180.00,Apple Inc.,Goldman Sachs,upgraded by,Neutral,Buy,2024-06-01T15:04:05Z
+MSFT,abc,$400.00,Microsoft,JP Morgan,target raised by,Buy,Buy,2024-06-01T15:04:05Z
+AAPL,$150.00,$180.00,Apple Inc.,Goldman Sachs,upgraded by,Neutral,Buy,2024-06-01T15:04:05Z
+TSLA,$200.00,$250.00,Tesla,Morgan Stanley,upgraded by,Hold,Buy,2024-05-01T00:00:00Z
+NVDA,$1.00
+`
+
+func TestImportStocks_CSV(t *testing.T) {
+	existing := time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)
+	repo := &mockStockRepository{stocks: []domain.Stock{{Ticker: "TSLA", Time: existing}}}
+	syncs := &recordingSyncObserver{}
+	service := NewStockService(repo, nil).WithSyncObserver(syncs)
+
+	report, err := service.ImportStocks(strings.NewReader(importCSV), ImportFormatCSV, false)
+	assert.Nil(t, err)
+	assert.Equal(t, 1, report.Inserted)
+	assert.Equal(t, []domain.ImportLineError{
+		{Line: 4, Error: "repetido en el archivo (línea 2)"},
+		{Line: 5, Error: "ya existe en la base de datos"},
+	}, report.Duplicates)
+	assert.Len(t, report.Invalid, 2)
+	assert.Equal(t, 3, report.Invalid[0].Line)
+	assert.Contains(t, report.Invalid[0].Error, "TargetFrom")
+	assert.Equal(t, 6, report.Invalid[1].Line)
+
+	assert.Len(t, repo.stocks, 2)
+	assert.Equal(t, domain.ActionUpgrade, repo.stocks[1].ActionType) // Se clasifica igual que lo ingerido
+	assert.Equal(t, domain.RatingBuy, repo.stocks[1].RatingToScore)
+	assert.Equal(t, domain.SourceImport, repo.stocks[1].Source)
+	assert.Equal(t, []int{1}, syncs.runs)
+}
+
+func TestImportStocks_DryRunNDJSON(t *testing.T) {
+	repo := &mockStockRepository{}
+	service := NewStockService(repo, nil)
+	input := `{"ticker":"AAPL","target_from":"150","target_to":"180","time":"2024-06-01T15:04:05Z"}
+
+{"ticker":"MSFT","target_from":"300","target_to":"320","time":"2024-06-01T15:04:05Z"}
+{not json}
+`
+
+	report, err := service.ImportStocks(strings.NewReader(input), ImportFormatNDJSON, true)
+	assert.Nil(t, err)
+	assert.True(t, report.DryRun)
+	assert.Equal(t, 2, report.Inserted)
+	assert.Equal(t, 4, report.Invalid[0].Line)
+	assert.Empty(t, repo.stocks)
+}
+
+func TestImportStocks_FileErrors(t *testing.T) {
+	service := NewStockService(&mockStockRepository{}, nil)
+
+	_, err := service.ImportStocks(strings.NewReader("ticker,time\n"), ImportFormatCSV, false)
+	assert.ErrorContains(t, err, "target_from")
+
+	_, err = service.ImportStocks(strings.NewReader(""), "xml", false)
+	assert.ErrorIs(t, err, ErrUnknownImportFormat)
+
+	assert.Equal(t, ImportFormatNDJSON, ImportFormatFromPath("dump.JSONL"))
+	assert.Equal(t, ImportFormatCSV, ImportFormatFromPath("/tmp/ratings.csv"))
+	assert.Equal(t, "", ImportFormatFromPath("ratings.txt"))
+}
+
+func TestImportStocks_DedupesByExternalID(t *testing.T) {
+	repo := &mockStockRepository{stocks: []domain.Stock{
+		{Ticker: "AAPL", Time: time.Date(2024, 6, 1, 15, 4, 5, 0, time.UTC), Source: "benzinga", ExternalID: "e1"},
+	}}
+	input := `{"ticker":"AAPL","target_from":"150","target_to":"180","time":"2024-06-01T15:04:05Z","source":"benzinga","external_id":"e2"}
+{"ticker":"AAPL","target_from":"150","target_to":"190","time":"2024-06-01T16:00:00Z","source":"benzinga","external_id":"e1"}
+{"ticker":"MSFT","target_from":"300","target_to":"320","time":"2024-06-01T15:04:05Z","source":"benzinga","external_id":"e2"}
+`
+
+	report, err := NewStockService(repo, nil).ImportStocks(strings.NewReader(input), ImportFormatNDJSON, false)
+	assert.Nil(t, err)
+	assert.Equal(t, 1, report.Inserted)
+	assert.Equal(t, []domain.ImportLineError{
+		{Line: 2, Error: "ya existe en la base de datos"},
+		{Line: 3, Error: "repetido en el archivo (línea 1)"},
+	}, report.Duplicates)
+}
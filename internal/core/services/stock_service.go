@@ -1,168 +1,386 @@
 package services
 
 import (
+	"errors"
+	"fmt"
 	"log"
-	"math"
 	"sort"
 
 	"recommender/internal/core/domain"
 	"recommender/internal/core/ports"
 
 	"time"
-
-	"gorm.io/gorm"
 )
 
 type StockService struct {
 	repository ports.StockRepository
-	apiClient  ports.StockAPIClient // Usa la interfaz en lugar de una implementación concreta
+	streamer   ports.StockStreamer        // Nil si el repositorio no permite recorrer con cursor
+	unknowns   ports.UnknownRatingCounter // Nil si el repositorio no cuenta etiquetas desconocidas
+	apiClient  ports.StockAPIClient       // Usa la interfaz en lugar de una implementación concreta
+	companies  *CompanyService            // Opcional: mantiene la tabla de referencia de compañías
+	snapshots  *SnapshotService           // Opcional: guarda las listas de recomendaciones cuyo ranking cambió
+	observers  []ports.StockObserver      // Reciben cada evento de rating recién guardado
+	syncs      []ports.SyncObserver       // Reciben el fin de cada importación
+	onFailure  string                     // Política ante una página de la API que falla
+	ratings    *RatingNormalizer
+	scorers    *ScorerRegistry
+	scorer     DefaultScorer // Configuración de la estrategia por defecto (decaimiento y pesos)
+	defaults   domain.RecommendationParams
+	now        func() time.Time // Reloj inyectable para el decaimiento y la ventana de eventos
+}
+
+// stockPageSize es el tamaño de página al recorrer un repositorio sin cursor.
+const stockPageSize = 500
+
+// ErrInvalidRecommendationParams envuelve los errores de validación de los parámetros.
+var ErrInvalidRecommendationParams = errors.New("invalid recommendation parameters")
+
+// DefaultRecommendationParams son los valores usados si no hay configuración.
+func DefaultRecommendationParams() domain.RecommendationParams {
+	return domain.RecommendationParams{
+		Limit:         5,
+		LookbackDays:  30,
+		CandidatePool: 0,
+		GroupBy:       domain.GroupByTicker,
+	}
 }
 
 func NewStockService(repo ports.StockRepository, apiClient ports.StockAPIClient) *StockService {
+	streamer, _ := repo.(ports.StockStreamer)
+	unknowns, _ := repo.(ports.UnknownRatingCounter)
 	return &StockService{
+		streamer:   streamer,
+		unknowns:   unknowns,
 		repository: repo,
 		apiClient:  apiClient,
+		ratings:    NewRatingNormalizer(DefaultRatingMapping()),
+		scorers:    NewDefaultScorerRegistry(),
+		defaults:   DefaultRecommendationParams(),
+		onFailure:  domain.PageFailurePartial,
+		now:        time.Now,
 	}
 }
 
-func (s *StockService) FetchAndStoreStocks() error {
-	log.Println("📥 Iniciando importación de datos desde la API externa...")
+// WithClock reemplaza el reloj usado para calcular ventanas y antigüedades.
+func (s *StockService) WithClock(now func() time.Time) *StockService {
+	s.now = now
+	return s
+}
 
-	nextPage := ""
-	for {
-		var err error
-		var apiResponse *domain.APIResponse
+// WithDecay aplica el decaimiento temporal a la estrategia por defecto.
+func (s *StockService) WithDecay(decay Decay) *StockService {
+	s.scorer.Decay = decay
+	s.scorers.Register(s.scorer)
+	return s
+}
 
-		// Reintentar hasta 3 veces en caso de fallo
-		for attempts := 1; attempts <= 3; attempts++ {
-			apiResponse, err = s.apiClient.FetchStocks(nextPage)
-			if err == nil {
-				break
-			}
+// WithBrokerageWeights reemplaza los pesos de corredora de la estrategia por defecto,
+// por ejemplo con los medidos por BrokerageAccuracyService.
+func (s *StockService) WithBrokerageWeights(weights ports.BrokerageWeigher) *StockService {
+	s.scorer.Weights = weights
+	s.scorers.Register(s.scorer)
+	return s
+}
 
-			log.Printf("⚠ Error en FetchStocks (Intento %d/3): %v", attempts, err)
-			time.Sleep(time.Duration(attempts) * time.Second)
-		}
+// WithRecommendationDefaults fija los parámetros usados cuando la petición no los indica.
+func (s *StockService) WithRecommendationDefaults(params domain.RecommendationParams) *StockService {
+	s.defaults = params
+	return s
+}
 
-		if err != nil {
-			log.Printf("❌ Falló la importación de stocks en la página '%s' después de 3 intentos. Continuando con la siguiente...", nextPage)
-			nextPage = "" // Forzar fin del bucle si hay fallo total
-			continue
+// RecommendationDefaults devuelve los parámetros por defecto configurados.
+func (s *StockService) RecommendationDefaults() domain.RecommendationParams {
+	return s.defaults
+}
+
+// RegisterScorer agrega una estrategia seleccionable con `?strategy=`.
+func (s *StockService) RegisterScorer(scorer ports.Scorer) {
+	s.scorers.Register(scorer)
+}
+
+// ScorerRegistry expone las estrategias configuradas, por ejemplo para el backtest.
+func (s *StockService) ScorerRegistry() *ScorerRegistry {
+	return s.scorers
+}
+
+// ScoringStrategies lista las estrategias disponibles.
+func (s *StockService) ScoringStrategies() []string {
+	return s.scorers.Names()
+}
+
+// WithRatingNormalizer reemplaza el mapeo de ratings por defecto, tanto al ingerir como
+// al puntuar filas guardadas antes de la normalización.
+func (s *StockService) WithRatingNormalizer(ratings *RatingNormalizer) *StockService {
+	s.ratings = ratings
+	s.scorer.Ratings = ratings
+	s.scorers.Register(s.scorer)
+	return s
+}
+
+// WithCompanyService habilita el poblado de la tabla de compañías con los datos ingeridos.
+func (s *StockService) WithCompanyService(companies *CompanyService) *StockService {
+	s.companies = companies
+	return s
+}
+
+// WithSnapshots guarda cada lista de recomendaciones calculada.
+func (s *StockService) WithSnapshots(snapshots *SnapshotService) *StockService {
+	s.snapshots = snapshots
+	return s
+}
+
+// WithObserver suscribe un observador a los eventos de rating nuevos (alertas, streams...).
+func (s *StockService) WithObserver(observer ports.StockObserver) *StockService {
+	s.observers = append(s.observers, observer)
+	return s
+}
+
+// WithPageFailurePolicy fija qué hace FetchAndStoreStocks si una página falla
+// (domain.PageFailureAbort, PageFailureSkip o PageFailurePartial, el valor por defecto).
+func (s *StockService) WithPageFailurePolicy(policy string) *StockService {
+	s.onFailure = policy
+	return s
+}
+
+// WithSyncObserver suscribe un observador al fin de cada importación (feed en vivo...).
+func (s *StockService) WithSyncObserver(observer ports.SyncObserver) *StockService {
+	s.syncs = append(s.syncs, observer)
+	return s
+}
+
+func (s *StockService) FetchStocks(limit, offset int) ([]domain.Stock, error) {
+	return s.repository.GetAll(limit, offset)
+}
+
+// SearchStocks lista eventos aplicando los filtros opcionales.
+func (s *StockService) SearchStocks(filter domain.StockFilter, limit, offset int) ([]domain.Stock, error) {
+	if filter.IsEmpty() {
+		return s.repository.GetAll(limit, offset)
+	}
+	return s.repository.GetFiltered(filter, limit, offset)
+}
+
+// StreamStocks recorre los eventos filtrados para exportarlos; un limit <= 0 recorre todos.
+func (s *StockService) StreamStocks(filter domain.StockFilter, limit, offset int, fn func(domain.Stock) error) error {
+	if s.streamer != nil {
+		return s.streamer.StreamFiltered(filter, limit, offset, fn)
+	}
+	return s.pageStocks(limit, offset, fn, func(limit, offset int) ([]domain.Stock, error) {
+		return s.SearchStocks(filter, limit, offset)
+	})
+}
+
+// StreamStockHistory recorre los eventos de un ticker del más antiguo al más reciente.
+func (s *StockService) StreamStockHistory(ticker string, limit, offset int, fn func(domain.Stock) error) error {
+	if s.streamer != nil {
+		return s.streamer.StreamHistory(ticker, limit, offset, fn)
+	}
+	// Sin cursor hay que ordenar en memoria
+	var history []domain.Stock
+	err := s.pageStocks(0, 0, func(stock domain.Stock) error {
+		history = append(history, stock)
+		return nil
+	}, func(limit, offset int) ([]domain.Stock, error) {
+		return s.repository.GetFiltered(domain.StockFilter{Tickers: []string{ticker}}, limit, offset)
+	})
+	if err != nil {
+		return err
+	}
+	sort.SliceStable(history, func(i, j int) bool { return history[i].Time.Before(history[j].Time) })
+	if offset >= len(history) {
+		return nil
+	}
+	history = history[offset:]
+	if limit > 0 && limit < len(history) {
+		history = history[:limit]
+	}
+	for _, stock := range history {
+		if err := fn(stock); err != nil {
+			return err
 		}
+	}
+	return nil
+}
 
-		// Procesar los datos obtenidos
-		for _, stock := range apiResponse.Items {
-			existingStock, err := s.repository.GetStockByTickerAndTime(stock.Ticker, stock.Time)
-			if err != nil && err != gorm.ErrRecordNotFound {
-				log.Printf("⚠ Error verificando existencia de %s: %v", stock.Ticker, err)
-				continue
-			}
+// StockHistory devuelve una página del historial de un ticker.
+func (s *StockService) StockHistory(ticker string, limit, offset int) ([]domain.Stock, error) {
+	history := []domain.Stock{}
+	err := s.StreamStockHistory(ticker, limit, offset, func(stock domain.Stock) error {
+		history = append(history, stock)
+		return nil
+	})
+	return history, err
+}
 
-			if existingStock == nil {
-				err = s.repository.Create(&stock)
-				if err != nil {
-					log.Printf("⚠ Error insertando stock %s: %v", stock.Ticker, err)
-				} else {
-					log.Printf("✅ Stock insertado: %s", stock.Ticker)
-				}
-			} else {
-				log.Printf("ℹ Stock %s ya existe en la base de datos, ignorando...", stock.Ticker)
+// pageStocks emula el recorrido con cursor pidiendo páginas de stockPageSize.
+func (s *StockService) pageStocks(limit, offset int, fn func(domain.Stock) error, page func(limit, offset int) ([]domain.Stock, error)) error {
+	for sent := 0; limit <= 0 || sent < limit; {
+		size := stockPageSize
+		if limit > 0 && limit-sent < size {
+			size = limit - sent
+		}
+		stocks, err := page(size, offset+sent)
+		if err != nil {
+			return err
+		}
+		for _, stock := range stocks {
+			if err := fn(stock); err != nil {
+				return err
 			}
 		}
-
-		// Si no hay más páginas, terminamos
-		if apiResponse.NextPage == "" {
+		sent += len(stocks)
+		if len(stocks) < size {
 			break
 		}
-		nextPage = apiResponse.NextPage
 	}
+	return nil
+}
 
-	log.Println("✅ Importación completada.")
+func (s *StockService) AddStock(stock *domain.Stock) error {
+	if stock.Source == "" {
+		stock.Source = domain.SourceManual
+	}
+	s.classify(stock)
+	if err := s.repository.Create(stock); err != nil {
+		return err
+	}
+	s.syncCompany(*stock)
+	s.notifyCreated(*stock)
 	return nil
 }
 
-func (s *StockService) FetchStocks(limit, offset int) ([]domain.Stock, error) {
-	return s.repository.GetAll(limit, offset)
+// classify normaliza ratings y acción antes de persistir, conservando los valores crudos.
+func (s *StockService) classify(stock *domain.Stock) {
+	s.ratings.Apply(stock)
+	stock.ActionType = domain.ParseAction(stock.Action)
 }
 
-func (s *StockService) AddStock(stock *domain.Stock) error {
-	return s.repository.Create(stock)
+// syncCompany registra la compañía del evento; un fallo aquí no invalida el stock ya guardado.
+func (s *StockService) syncCompany(stock domain.Stock) {
+	if s.companies == nil {
+		return
+	}
+	if err := s.companies.SyncFromStock(stock); err != nil {
+		log.Printf("⚠ Error sincronizando compañía %s: %v", stock.Ticker, err)
+	}
 }
 
-func (s *StockService) GetTopRecommendedStocks(limit int) ([]domain.Stock, error) {
-	stocks, err := s.repository.GetRecentStocks(100) // Tomamos un grupo grande para filtrar mejor
-	if err != nil {
-		return nil, err
+func (s *StockService) notifyCreated(stock domain.Stock) {
+	for _, observer := range s.observers {
+		observer.OnStockCreated(stock)
 	}
+}
 
-	// Calcular la puntuación de cada stock
-	type ScoredStock struct {
-		Stock domain.Stock
-		Score float64
+func (s *StockService) notifySynced(inserted int) {
+	for _, observer := range s.syncs {
+		observer.OnSyncCompleted(inserted)
 	}
-	var scoredStocks []ScoredStock
+}
 
-	for _, stock := range stocks {
-		score := calculateScore(stock)
-		scoredStocks = append(scoredStocks, ScoredStock{Stock: stock, Score: score})
+// GetTopRecommendedStocks puntúa los eventos de la ventana con la estrategia indicada;
+// una estrategia vacía usa la estrategia por defecto. Por defecto combina los eventos de
+// cada ticker; GroupBy "event" devuelve un elemento por evento. Cada elemento incluye el
+// desglose de su puntuación.
+func (s *StockService) GetTopRecommendedStocks(params domain.RecommendationParams) (*domain.RecommendationList, error) {
+	if err := params.Validate(); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidRecommendationParams, err)
 	}
 
-	// Ordenar por puntuación de mayor a menor
-	sort.Slice(scoredStocks, func(i, j int) bool {
-		return scoredStocks[i].Score > scoredStocks[j].Score
-	})
+	scorer, err := s.scorers.Get(params.Strategy)
+	if err != nil {
+		return nil, err
+	}
 
-	// Tomar los primeros "limit" elementos
-	var topStocks []domain.Stock
-	for i := 0; i < limit && i < len(scoredStocks); i++ {
-		topStocks = append(topStocks, scoredStocks[i].Stock)
+	asOf := s.now()
+	since := asOf.AddDate(0, 0, -params.LookbackDays)
+	pool := params.CandidatePool
+	if len(params.Tickers) > 0 {
+		pool = 0 // El pool se aplica después de filtrar por tickers
+	}
+	stocks, err := s.repository.GetRecentStocks(since, pool)
+	if err != nil {
+		return nil, err
+	}
+	if len(params.Tickers) > 0 {
+		stocks = filterByTickers(stocks, params.Tickers, params.CandidatePool)
 	}
 
-	return topStocks, nil
-}
+	recommendations := buildRecommendations(stocks, params, scorer, asOf, s.sectorLookup())
 
-func (s *StockService) GetStockByTicker(ticker string) (*domain.Stock, error) {
-	return s.repository.GetStockByTicker(ticker)
+	list := &domain.RecommendationList{
+		Strategy:      scorer.Name(),
+		ScorerVersion: scorer.Version(),
+		GeneratedAt:   asOf,
+		Params:        params,
+		Items:         recommendations,
+	}
+	s.recordSnapshot(*list)
+	return list, nil
 }
 
-// calculateScore ahora delega responsabilidades a subfunciones.
-func calculateScore(stock domain.Stock) float64 {
-	priceImpact := calculatePriceImpact(stock)
-	ratingImpact := calculateRatingImpact(stock)
-	brokerageWeight := getBrokerageWeight(stock.Brokerage)
-
-	return (priceImpact + ratingImpact) * brokerageWeight
+// filterByTickers conserva los eventos de los tickers indicados, hasta limit si es positivo.
+func filterByTickers(stocks []domain.Stock, tickers []string, limit int) []domain.Stock {
+	wanted := make(map[string]bool, len(tickers))
+	for _, ticker := range tickers {
+		wanted[ticker] = true
+	}
+	filtered := make([]domain.Stock, 0, len(stocks))
+	for _, stock := range stocks {
+		if limit > 0 && len(filtered) == limit {
+			break
+		}
+		if wanted[stock.Ticker] {
+			filtered = append(filtered, stock)
+		}
+	}
+	return filtered
 }
 
-// calculatePriceImpact calcula el impacto por cambio de precio
-func calculatePriceImpact(stock domain.Stock) float64 {
-	percentageChange := (stock.TargetTo - stock.TargetFrom) / stock.TargetFrom * 100
-	return math.Round(percentageChange*100) / 100 // Redondeo a dos decimales
+// sectorLookup consulta el sector en la tabla de compañías, una vez por ticker y petición.
+func (s *StockService) sectorLookup() SectorLookup {
+	if s.companies == nil {
+		return nil
+	}
+	cache := map[string]string{}
+	return func(ticker string) string {
+		if sector, ok := cache[ticker]; ok {
+			return sector
+		}
+		sector := ""
+		if company, err := s.companies.GetCompany(ticker); err == nil && company != nil {
+			sector = company.Sector
+		}
+		cache[ticker] = sector
+		return sector
+	}
 }
 
-// calculateRatingImpact evalúa el impacto por cambio de rating
-func calculateRatingImpact(stock domain.Stock) float64 {
-	ratingScores := map[string]float64{
-		"Sell":    -2,
-		"Neutral": 0,
-		"Buy":     2,
+// recordSnapshot guarda la lista; un fallo no impide responder la petición. Las listas
+// restringidas a tickers (las de una watchlist) no se guardan: los snapshots son públicos
+// y sus parámetros revelarían los tickers del usuario.
+func (s *StockService) recordSnapshot(list domain.RecommendationList) {
+	if s.snapshots == nil || len(list.Params.Tickers) > 0 {
+		return
+	}
+	if err := s.snapshots.Record(list); err != nil {
+		log.Printf("⚠ Error guardando snapshot de recomendaciones: %v", err)
 	}
-	fromScore := ratingScores[stock.RatingFrom]
-	toScore := ratingScores[stock.RatingTo]
-	return toScore - fromScore
 }
 
-// getBrokerageWeight devuelve el peso asociado a la corredora
-func getBrokerageWeight(brokerage string) float64 {
-	weights := map[string]float64{
-		"The Goldman Sachs Group": 1.5,
-		"JP Morgan":               1.4,
-		"Morgan Stanley":          1.3,
-		"Others":                  1.0,
+func (s *StockService) GetStockByTicker(ticker string) (*domain.Stock, error) {
+	return s.repository.GetStockByTicker(ticker)
+}
+
+// UnknownRatingLabels reporta las etiquetas de rating que el mapeo no reconoce. Si el
+// repositorio las cuenta, salen de los eventos guardados (de cualquier proceso); si no,
+// de lo que normalizó este proceso.
+func (s *StockService) UnknownRatingLabels() ([]domain.UnknownRatingLabel, error) {
+	if s.unknowns == nil {
+		return s.ratings.UnknownLabels(), nil
 	}
-	weight, exists := weights[brokerage]
-	if !exists {
-		return weights["Others"]
+	counts, err := s.unknowns.CountUnknownRatingLabels()
+	if err != nil {
+		return nil, err
 	}
-	return weight
+	return s.ratings.UnknownLabelsFrom(counts), nil
 }
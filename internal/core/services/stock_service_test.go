@@ -31,6 +31,28 @@ func (m *mockStockRepository) GetAll(limit, offset int) ([]domain.Stock, error)
 	}
 	return m.stocks[offset:end], nil
 }
+func (m *mockStockRepository) GetFiltered(filter domain.StockFilter, limit, offset int) ([]domain.Stock, error) {
+	var stocks []domain.Stock
+	for _, s := range m.stocks {
+		if matchesAny(string(s.ActionType), filter.ActionTypes) && matchesAny(s.Ticker, filter.Tickers) {
+			stocks = append(stocks, s)
+		}
+	}
+	return stocks, nil
+}
+
+// matchesAny es verdadero si values está vacío o contiene value.
+func matchesAny[T ~string](value string, values []T) bool {
+	if len(values) == 0 {
+		return true
+	}
+	for _, v := range values {
+		if string(v) == value {
+			return true
+		}
+	}
+	return false
+}
 func (m *mockStockRepository) Create(stock *domain.Stock) error {
 	m.stocks = append(m.stocks, *stock)
 	return nil
@@ -43,11 +65,25 @@ func (m *mockStockRepository) GetStockByTickerAndTime(ticker string, t time.Time
 	}
 	return nil, gorm.ErrRecordNotFound
 }
-func (m *mockStockRepository) GetRecentStocks(limit int) ([]domain.Stock, error) {
-	if limit > len(m.stocks) {
-		limit = len(m.stocks)
+func (m *mockStockRepository) GetStockByExternalID(source, externalID string) (*domain.Stock, error) {
+	for _, s := range m.stocks {
+		if s.Source == source && s.ExternalID == externalID {
+			return &s, nil
+		}
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+func (m *mockStockRepository) GetRecentStocks(since time.Time, limit int) ([]domain.Stock, error) {
+	var stocks []domain.Stock
+	for _, s := range m.stocks {
+		if !s.Time.Before(since) {
+			stocks = append(stocks, s)
+		}
 	}
-	return m.stocks[:limit], nil
+	if limit > 0 && limit < len(stocks) {
+		stocks = stocks[:limit]
+	}
+	return stocks, nil
 }
 func (m *mockStockRepository) GetStockByTicker(ticker string) (*domain.Stock, error) {
 	for _, s := range m.stocks {
@@ -72,6 +108,14 @@ func (m *mockStockAPIClient) FetchStocks(nextPage string) (*domain.APIResponse,
 	return nil, errors.New("no more pages")
 }
 
+type recordingSyncObserver struct {
+	runs []int
+}
+
+func (r *recordingSyncObserver) OnSyncCompleted(inserted int) {
+	r.runs = append(r.runs, inserted)
+}
+
 // --- Tests ---
 
 func TestFetchAndStoreStocks(t *testing.T) {
@@ -97,12 +141,19 @@ func TestFetchAndStoreStocks(t *testing.T) {
 	}
 
 	repo := &mockStockRepository{}
-	service := NewStockService(repo, mockAPI)
+	syncs := &recordingSyncObserver{}
+	service := NewStockService(repo, mockAPI).WithSyncObserver(syncs)
 
-	err := service.FetchAndStoreStocks()
+	result, err := service.FetchAndStoreStocks()
 	if err != nil {
 		t.Fatalf("FetchAndStoreStocks failed: %v", err)
 	}
+	if result.Status != domain.SyncCompleted || result.Pages != 1 || result.Inserted != 1 {
+		t.Errorf("unexpected sync result %+v", result)
+	}
+	if len(syncs.runs) != 1 || syncs.runs[0] != 1 {
+		t.Errorf("expected one sync notification with 1 insert, got %v", syncs.runs)
+	}
 
 	if len(repo.stocks) != 1 {
 		t.Errorf("expected 1 stock stored, got %d", len(repo.stocks))
@@ -143,24 +194,30 @@ func TestAddStockAndFetchStocks(t *testing.T) {
 }
 
 func TestGetTopRecommendedStocks(t *testing.T) {
+	now := time.Now()
 	repo := &mockStockRepository{
 		stocks: []domain.Stock{
-			{Ticker: "A", TargetFrom: 100, TargetTo: 120, RatingFrom: "Sell", RatingTo: "Buy", Brokerage: "JP Morgan"},
-			{Ticker: "B", TargetFrom: 100, TargetTo: 110, RatingFrom: "Neutral", RatingTo: "Buy", Brokerage: "Others"},
-			{Ticker: "C", TargetFrom: 100, TargetTo: 90, RatingFrom: "Buy", RatingTo: "Sell", Brokerage: "Morgan Stanley"},
+			{Ticker: "A", TargetFrom: 100, TargetTo: 120, RatingFrom: "Sell", RatingTo: "Buy", Brokerage: "JP Morgan", Time: now},
+			{Ticker: "B", TargetFrom: 100, TargetTo: 110, RatingFrom: "Neutral", RatingTo: "Buy", Brokerage: "Others", Time: now},
+			{Ticker: "C", TargetFrom: 100, TargetTo: 90, RatingFrom: "Buy", RatingTo: "Sell", Brokerage: "Morgan Stanley", Time: now},
 		},
 	}
 	service := NewStockService(repo, nil)
 
-	top, err := service.GetTopRecommendedStocks(2)
+	params := DefaultRecommendationParams()
+	params.Limit = 2
+	top, err := service.GetTopRecommendedStocks(params)
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
-	if len(top) != 2 {
-		t.Errorf("expected 2 top stocks, got %d", len(top))
+	if len(top.Items) != 2 {
+		t.Errorf("expected 2 top stocks, got %d", len(top.Items))
+	}
+	if top.Items[0].Stock.Ticker != "A" {
+		t.Errorf("expected stock 'A' to be top, got %s", top.Items[0].Stock.Ticker)
 	}
-	if top[0].Ticker != "A" {
-		t.Errorf("expected stock 'A' to be top, got %s", top[0].Ticker)
+	if top.Items[0].Rank != 1 || top.Items[0].Breakdown.Rationale == "" {
+		t.Errorf("expected ranked item with rationale, got %+v", top.Items[0])
 	}
 }
 
@@ -179,4 +236,128 @@ func TestGetStockByTicker(t *testing.T) {
 	if stock.Ticker != "MSFT" {
 		t.Errorf("expected ticker MSFT, got %s", stock.Ticker)
 	}
-}
\ No newline at end of file
+}
+func TestAddStockStoresNormalizedRatings(t *testing.T) {
+	repo := &mockStockRepository{}
+	service := NewStockService(repo, nil)
+
+	err := service.AddStock(&domain.Stock{Ticker: "AMD", RatingFrom: "Underweight", RatingTo: "Overweight"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	stored := repo.stocks[0]
+	if stored.RatingFromScore != domain.RatingSell || stored.RatingToScore != domain.RatingBuy {
+		t.Errorf("unexpected normalized ratings: %+v", stored)
+	}
+	if stored.RatingFrom != "Underweight" || stored.RatingTo != "Overweight" {
+		t.Errorf("expected raw ratings to be kept: %+v", stored)
+	}
+}
+
+func TestAddStockClassifiesAction(t *testing.T) {
+	repo := &mockStockRepository{}
+	service := NewStockService(repo, nil)
+
+	if err := service.AddStock(&domain.Stock{Ticker: "AMD", Action: "upgraded by"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := service.AddStock(&domain.Stock{Ticker: "INTC", Action: "target lowered by"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	upgrades, err := service.SearchStocks(domain.StockFilter{ActionTypes: []domain.ActionType{domain.ActionUpgrade}}, 10, 0)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(upgrades) != 1 || upgrades[0].Ticker != "AMD" {
+		t.Errorf("unexpected upgrades: %+v", upgrades)
+	}
+	if repo.stocks[1].ActionType != domain.ActionTargetLowered {
+		t.Errorf("expected target_lowered, got %s", repo.stocks[1].ActionType)
+	}
+}
+
+func TestDefaultScorer_ActionImpact(t *testing.T) {
+	base := domain.Stock{TargetFrom: 100, TargetTo: 100, RatingFrom: "Hold", RatingTo: "Hold", Brokerage: "Others"}
+	score := func(stock domain.Stock) float64 { return DefaultScorer{}.Score(stock, stock.Time).Total }
+
+	upgrade := base
+	upgrade.ActionType = domain.ActionUpgrade
+	downgrade := base
+	downgrade.Action = "downgraded by"
+
+	if score(upgrade) <= score(base) {
+		t.Errorf("expected upgrade to score above a neutral event")
+	}
+	if score(downgrade) >= score(base) {
+		t.Errorf("expected downgrade to score below a neutral event")
+	}
+}
+
+func TestGetTopRecommendedStocks_LookbackAndValidation(t *testing.T) {
+	now := time.Now()
+	repo := &mockStockRepository{
+		stocks: []domain.Stock{
+			{Ticker: "OLD", TargetFrom: 100, TargetTo: 300, Brokerage: "JP Morgan", Time: now.AddDate(0, 0, -20)},
+			{Ticker: "NEW", TargetFrom: 10, TargetTo: 11, Brokerage: "Others", Time: now.Add(-time.Hour)},
+		},
+	}
+	service := NewStockService(repo, nil)
+
+	params := DefaultRecommendationParams()
+	params.LookbackDays = 7
+	top, err := service.GetTopRecommendedStocks(params)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(top.Items) != 1 || top.Items[0].Stock.Ticker != "NEW" {
+		t.Errorf("expected only NEW inside a 7 day window, got %+v", top.Items)
+	}
+
+	params.LookbackDays = 30
+	top, err = service.GetTopRecommendedStocks(params)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if top.Items[0].Stock.Ticker != "OLD" {
+		t.Errorf("expected OLD to rank first with a 30 day window, got %s", top.Items[0].Stock.Ticker)
+	}
+
+	params.Limit = 0
+	if _, err := service.GetTopRecommendedStocks(params); !errors.Is(err, ErrInvalidRecommendationParams) {
+		t.Errorf("expected ErrInvalidRecommendationParams, got %v", err)
+	}
+}
+
+func TestStockHistory_WithoutStreamerSortsByTime(t *testing.T) {
+	now := time.Now()
+	repo := &mockStockRepository{stocks: []domain.Stock{
+		{Ticker: "AAPL", RatingTo: "Buy", Time: now},
+		{Ticker: "MSFT", Time: now.Add(-time.Hour)},
+		{Ticker: "AAPL", RatingTo: "Hold", Time: now.Add(-48 * time.Hour)},
+	}}
+	service := NewStockService(repo, nil)
+
+	history, err := service.StockHistory("AAPL", 0, 0)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(history) != 2 || history[0].RatingTo != "Hold" || history[1].RatingTo != "Buy" {
+		t.Errorf("expected AAPL history oldest first, got %+v", history)
+	}
+
+	page, _ := service.StockHistory("AAPL", 1, 1)
+	if len(page) != 1 || page[0].RatingTo != "Buy" {
+		t.Errorf("expected second event only, got %+v", page)
+	}
+
+	var streamed []string
+	err = service.StreamStocks(domain.StockFilter{}, 0, 0, func(s domain.Stock) error {
+		streamed = append(streamed, s.Ticker)
+		return nil
+	})
+	if err != nil || len(streamed) != 3 {
+		t.Errorf("expected all 3 stocks streamed, got %v (%v)", streamed, err)
+	}
+}
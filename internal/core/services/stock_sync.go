@@ -0,0 +1,173 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"log"
+
+	"recommender/internal/core/domain"
+	"recommender/internal/core/ports"
+
+	"gorm.io/gorm"
+)
+
+// maxConsecutiveSkips es cuántas páginas seguidas se pueden saltear con
+// domain.PageFailureSkip antes de dar la corrida por fallida: si el proveedor está
+// caído (o con el circuito abierto) seguir salteando no termina nunca.
+const maxConsecutiveSkips = 5
+
+// ErrSyncFailed envuelve el error de una importación que terminó como domain.SyncFailed.
+var ErrSyncFailed = errors.New("stock sync failed")
+
+// FetchAndStoreStocks importa todas las páginas de la API externa. Los reintentos son
+// del cliente; si una página falla igual, se aplica la política configurada con
+// WithPageFailurePolicy. Devuelve siempre el resultado de la corrida y, solo si quedó
+// como domain.SyncFailed, un error que envuelve ErrSyncFailed. Una corrida parcial no
+// es un error: el llamador decide con result.Status.
+func (s *StockService) FetchAndStoreStocks() (*domain.SyncResult, error) {
+	log.Println("📥 Iniciando importación de datos desde la API externa...")
+	result := &domain.SyncResult{
+		Status:      domain.SyncCompleted,
+		Policy:      s.onFailure,
+		FailedPages: []domain.PageFailure{},
+		StartedAt:   s.now(),
+	}
+	if !domain.ValidPageFailurePolicy(s.onFailure) {
+		return s.finishSync(result, fmt.Errorf("%w: política desconocida '%s'", ErrSyncFailed, s.onFailure))
+	}
+
+	nextPage := ""
+	seen := map[string]bool{}
+	skipped := 0 // Páginas salteadas desde la última que se leyó bien
+	for {
+		seen[nextPage] = true
+		apiResponse, err := s.apiClient.FetchStocks(nextPage)
+		if err == nil && apiResponse.NextPage != "" && seen[apiResponse.NextPage] {
+			// Un cursor repetido haría que la importación no termine nunca
+			err = fmt.Errorf("la API devolvió otra vez el cursor '%s'", apiResponse.NextPage)
+		}
+		if err != nil {
+			log.Printf("❌ Falló la importación de stocks en la página '%s': %v", nextPage, err)
+			result.FailedPages = append(result.FailedPages, domain.PageFailure{Page: nextPage, Error: err.Error()})
+
+			if s.onFailure == domain.PageFailureAbort {
+				result.ResumeFrom = nextPage
+				return s.finishSync(result, fmt.Errorf("%w: página '%s': %v", ErrSyncFailed, nextPage, err))
+			}
+			if s.onFailure == domain.PageFailureSkip {
+				if skipped >= maxConsecutiveSkips {
+					result.ResumeFrom = nextPage
+					return s.finishSync(result, fmt.Errorf("%w: %d páginas seguidas fallaron, última '%s': %v",
+						ErrSyncFailed, skipped+1, nextPage, err))
+				}
+				if skipper, ok := s.apiClient.(ports.PageSkipper); ok {
+					next, known := skipper.NextPageAfter(nextPage)
+					if known && next == "" {
+						break
+					}
+					if known && !seen[next] {
+						log.Printf("⏭ Se saltea la página '%s', se sigue con '%s'", nextPage, next)
+						nextPage = next
+						skipped++
+						continue
+					}
+				}
+				log.Printf("⚠ No se conoce la página que sigue a '%s', se detiene la importación", nextPage)
+			}
+			result.ResumeFrom = nextPage
+			break
+		}
+
+		result.Pages++
+		skipped = 0
+		s.storeFetched(apiResponse.Items, result)
+
+		// Si no hay más páginas, terminamos
+		if apiResponse.NextPage == "" {
+			break
+		}
+		nextPage = apiResponse.NextPage
+	}
+
+	if len(result.FailedPages) > 0 {
+		result.Status = domain.SyncPartial
+		if result.Pages == 0 {
+			return s.finishSync(result, fmt.Errorf("%w: no se pudo leer ninguna página", ErrSyncFailed))
+		}
+	}
+	return s.finishSync(result, nil)
+}
+
+// storeFetched guarda los eventos nuevos de una página y cuenta duplicados y errores.
+func (s *StockService) storeFetched(stocks []domain.Stock, result *domain.SyncResult) {
+	for _, stock := range stocks {
+		existingStock, err := s.findExisting(stock)
+		if err != nil {
+			log.Printf("⚠ Error verificando existencia de %s: %v", stock.Ticker, err)
+			result.ItemErrors++
+			continue
+		}
+
+		if existingStock != nil {
+			log.Printf("ℹ Stock %s ya existe en la base de datos, ignorando...", stock.Ticker)
+			result.Duplicates++
+			continue
+		}
+
+		s.classify(&stock)
+		if err := s.repository.Create(&stock); err != nil {
+			log.Printf("⚠ Error insertando stock %s: %v", stock.Ticker, err)
+			result.ItemErrors++
+			continue
+		}
+		log.Printf("✅ Stock insertado: %s", stock.Ticker)
+		result.Inserted++
+		s.syncCompany(stock)
+		s.notifyCreated(stock)
+	}
+}
+
+// findExisting busca un evento ya guardado igual a stock: por (Source, ExternalID) si el
+// proveedor le dio un ID, y si no por ticker y fecha. Las filas anteriores a que se
+// guardara el ExternalID solo se comparan por ticker y fecha, así que también se
+// consideran duplicado. Devuelve nil sin error si no existe.
+func (s *StockService) findExisting(stock domain.Stock) (*domain.Stock, error) {
+	if stock.ExternalID != "" {
+		existing, err := s.repository.GetStockByExternalID(stock.Source, stock.ExternalID)
+		if err == nil || !errors.Is(err, gorm.ErrRecordNotFound) {
+			return existing, err
+		}
+	}
+	existing, err := s.repository.GetStockByTickerAndTime(stock.Ticker, stock.Time)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if stock.ExternalID != "" && existing.ExternalID != "" {
+		return nil, nil // Otro evento del mismo ticker y fecha, con su propio ID
+	}
+	return existing, nil
+}
+
+// finishSync cierra el resultado y avisa a los observadores con lo que se haya insertado,
+// aunque la corrida no haya terminado bien.
+func (s *StockService) finishSync(result *domain.SyncResult, err error) (*domain.SyncResult, error) {
+	if err != nil {
+		result.Status = domain.SyncFailed
+	}
+	result.FinishedAt = s.now()
+
+	switch result.Status {
+	case domain.SyncCompleted:
+		log.Printf("✅ Importación completada: %d páginas, %d eventos nuevos.", result.Pages, result.Inserted)
+	case domain.SyncPartial:
+		log.Printf("⚠ Importación parcial: %d páginas, %d eventos nuevos, %d páginas fallidas.",
+			result.Pages, result.Inserted, len(result.FailedPages))
+	default:
+		log.Printf("❌ Importación fallida: %v", err)
+	}
+	s.notifySynced(result.Inserted)
+	return result, err
+}
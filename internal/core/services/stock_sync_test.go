@@ -0,0 +1,188 @@
+package services
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"recommender/internal/core/domain"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// pagedAPIClient responde por cursor; los cursores en failing devuelven error.
+type pagedAPIClient struct {
+	pages   map[string]*domain.APIResponse
+	failing map[string]bool
+	calls   []string
+}
+
+func (p *pagedAPIClient) FetchStocks(nextPage string) (*domain.APIResponse, error) {
+	p.calls = append(p.calls, nextPage)
+	if p.failing[nextPage] {
+		return nil, errors.New("API returned status: 503")
+	}
+	if page, ok := p.pages[nextPage]; ok {
+		return page, nil
+	}
+	return nil, errors.New("unknown page")
+}
+
+// skippingAPIClient además sabe qué página sigue, como PagedStockAPI.
+type skippingAPIClient struct {
+	*pagedAPIClient
+	after map[string]string
+}
+
+func (s *skippingAPIClient) NextPageAfter(failed string) (string, bool) {
+	next, ok := s.after[failed]
+	return next, ok
+}
+
+func syncPage(ticker, next string) *domain.APIResponse {
+	return &domain.APIResponse{
+		Items:    []domain.Stock{{Ticker: ticker, Time: time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)}},
+		NextPage: next,
+	}
+}
+
+// threePages tiene las páginas "", "p2" y "p3"; la del medio falla.
+func threePages() *pagedAPIClient {
+	return &pagedAPIClient{
+		pages:   map[string]*domain.APIResponse{"": syncPage("AAPL", "p2"), "p3": syncPage("TSLA", "")},
+		failing: map[string]bool{"p2": true},
+	}
+}
+
+func TestFetchAndStoreStocks_AbortPolicy(t *testing.T) {
+	repo := &mockStockRepository{}
+	syncs := &recordingSyncObserver{}
+	service := NewStockService(repo, threePages()).
+		WithPageFailurePolicy(domain.PageFailureAbort).
+		WithSyncObserver(syncs)
+
+	result, err := service.FetchAndStoreStocks()
+	assert.ErrorIs(t, err, ErrSyncFailed)
+	assert.ErrorContains(t, err, "p2")
+	assert.Equal(t, domain.SyncFailed, result.Status)
+	assert.Equal(t, 1, result.Inserted)
+	assert.Equal(t, "p2", result.ResumeFrom)
+	assert.Equal(t, []domain.PageFailure{{Page: "p2", Error: "API returned status: 503"}}, result.FailedPages)
+	assert.Equal(t, []int{1}, syncs.runs) // Lo insertado antes de abortar se notifica igual
+}
+
+func TestFetchAndStoreStocks_PartialPolicyStopsWithoutLooping(t *testing.T) {
+	api := threePages()
+	repo := &mockStockRepository{}
+	service := NewStockService(repo, api) // Parcial es la política por defecto
+
+	result, err := service.FetchAndStoreStocks()
+	require.NoError(t, err)
+	assert.Equal(t, domain.SyncPartial, result.Status)
+	assert.Equal(t, domain.PageFailurePartial, result.Policy)
+	assert.Equal(t, 1, result.Pages)
+	assert.Equal(t, "p2", result.ResumeFrom)
+	assert.Equal(t, []string{"", "p2"}, api.calls) // No vuelve a empezar desde la primera página
+	assert.Len(t, repo.stocks, 1)
+}
+
+func TestFetchAndStoreStocks_SkipPolicy(t *testing.T) {
+	api := &skippingAPIClient{pagedAPIClient: threePages(), after: map[string]string{"p2": "p3"}}
+	repo := &mockStockRepository{}
+	service := NewStockService(repo, api).WithPageFailurePolicy(domain.PageFailureSkip)
+
+	result, err := service.FetchAndStoreStocks()
+	require.NoError(t, err)
+	assert.Equal(t, domain.SyncPartial, result.Status)
+	assert.Equal(t, 2, result.Pages)
+	assert.Equal(t, 2, result.Inserted)
+	assert.Empty(t, result.ResumeFrom)
+	assert.Equal(t, []string{"", "p2", "p3"}, api.calls)
+
+	// Sin cursor conocido para la siguiente página, se detiene como parcial
+	plain := threePages()
+	result, err = NewStockService(&mockStockRepository{}, plain).
+		WithPageFailurePolicy(domain.PageFailureSkip).
+		FetchAndStoreStocks()
+	require.NoError(t, err)
+	assert.Equal(t, domain.SyncPartial, result.Status)
+	assert.Equal(t, "p2", result.ResumeFrom)
+}
+
+func TestFetchAndStoreStocks_FailsWhenNothingCouldBeRead(t *testing.T) {
+	api := &pagedAPIClient{failing: map[string]bool{"": true}}
+	result, err := NewStockService(&mockStockRepository{}, api).FetchAndStoreStocks()
+	assert.ErrorIs(t, err, ErrSyncFailed)
+	assert.Equal(t, domain.SyncFailed, result.Status)
+	assert.Equal(t, []string{""}, api.calls)
+}
+
+func TestFetchAndStoreStocks_RepeatedCursorAndDuplicates(t *testing.T) {
+	api := &pagedAPIClient{pages: map[string]*domain.APIResponse{
+		"":   syncPage("AAPL", "p2"),
+		"p2": syncPage("AAPL", "p2"), // Un proveedor roto que repite el cursor
+	}}
+	result, err := NewStockService(&mockStockRepository{}, api).FetchAndStoreStocks()
+	require.NoError(t, err)
+	assert.Equal(t, domain.SyncPartial, result.Status)
+	assert.Equal(t, 1, result.Inserted)
+	assert.Equal(t, []string{"", "p2"}, api.calls)
+	assert.Contains(t, result.FailedPages[0].Error, "cursor 'p2'")
+
+	_, err = NewStockService(&mockStockRepository{}, api).WithPageFailurePolicy("retry").FetchAndStoreStocks()
+	assert.ErrorIs(t, err, ErrSyncFailed)
+}
+
+// endlessSkipper siempre dice conocer la página siguiente, como un proveedor paginado
+// que no sabe cuántas páginas tiene.
+type endlessSkipper struct {
+	calls int
+}
+
+func (e *endlessSkipper) FetchStocks(nextPage string) (*domain.APIResponse, error) {
+	e.calls++
+	if e.calls > 1000 {
+		panic("la importación no se detuvo")
+	}
+	return nil, errors.New("circuit breaker abierto")
+}
+
+func (e *endlessSkipper) NextPageAfter(failed string) (string, bool) {
+	return failed + "+", true
+}
+
+func TestFetchAndStoreStocks_SkipPolicyStopsOnAlwaysFailingProvider(t *testing.T) {
+	api := &endlessSkipper{}
+	syncs := &recordingSyncObserver{}
+	result, err := NewStockService(&mockStockRepository{}, api).
+		WithPageFailurePolicy(domain.PageFailureSkip).
+		WithSyncObserver(syncs).
+		FetchAndStoreStocks()
+
+	assert.ErrorIs(t, err, ErrSyncFailed)
+	assert.Equal(t, domain.SyncFailed, result.Status)
+	assert.Equal(t, maxConsecutiveSkips+1, api.calls)
+	assert.Len(t, result.FailedPages, maxConsecutiveSkips+1)
+	assert.Equal(t, "+++++", result.ResumeFrom)
+	assert.Equal(t, []int{0}, syncs.runs)
+}
+
+func TestFetchAndStoreStocks_DedupesByExternalID(t *testing.T) {
+	at := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+	repo := &mockStockRepository{stocks: []domain.Stock{
+		{Ticker: "AAPL", Time: at, Source: "benzinga", ExternalID: "e1"},
+		{Ticker: "TSLA", Time: at}, // Fila anterior a que se guardara el ID del proveedor
+	}}
+	api := &pagedAPIClient{pages: map[string]*domain.APIResponse{"": {Items: []domain.Stock{
+		{Ticker: "AAPL", Time: at.Add(time.Hour), Source: "benzinga", ExternalID: "e1"}, // Corrigió la fecha
+		{Ticker: "AAPL", Time: at, Source: "benzinga", ExternalID: "e2"},                // Otro evento a la misma hora
+		{Ticker: "TSLA", Time: at, Source: "benzinga", ExternalID: "e3"},
+	}}}}
+
+	result, err := NewStockService(repo, api).FetchAndStoreStocks()
+	require.NoError(t, err)
+	assert.Equal(t, 1, result.Inserted)
+	assert.Equal(t, 2, result.Duplicates)
+	assert.Equal(t, "e2", repo.stocks[2].ExternalID)
+}
@@ -0,0 +1,234 @@
+package services
+
+import (
+	"context"
+	"log"
+	"strings"
+	"sync"
+	"time"
+
+	"recommender/internal/core/domain"
+	"recommender/internal/core/ports"
+)
+
+const (
+	streamBatchSize  = 500
+	streamBufferSize = 64
+
+	// defaultStreamSafetyLag es cuánto se espera antes de publicar un evento del outbox.
+	defaultStreamSafetyLag = 2 * time.Second
+)
+
+// StreamFilter restringe los eventos que recibe un suscriptor. Tickers exige
+// coincidencia exacta y Brokerage coincidencia parcial sin distinguir mayúsculas.
+type StreamFilter struct {
+	Tickers   []string
+	Brokerage string
+}
+
+func (f StreamFilter) matches(stock domain.Stock) bool {
+	if len(f.Tickers) > 0 && !containsString(f.Tickers, strings.ToUpper(stock.Ticker)) {
+		return false
+	}
+	return f.Brokerage == "" || strings.Contains(strings.ToLower(stock.Brokerage), strings.ToLower(f.Brokerage))
+}
+
+// StreamSubscription recibe los eventos en C. Si el suscriptor no consume a tiempo
+// el hub cierra C; el cliente puede reconectar con el último ID recibido.
+type StreamSubscription struct {
+	C      <-chan domain.StockEvent
+	ch     chan domain.StockEvent
+	filter StreamFilter
+
+	// Backlog pendiente al reanudar: los eventos en (after, cutoff] ya publicados
+	resume bool
+	after  uint
+	cutoff uint
+}
+
+// StreamHub es el pub/sub en proceso del stream de stocks. Lee el outbox en orden
+// de secuencia, de modo que publica tanto lo ingerido como lo creado por POST /stocks
+// en este o en otro proceso.
+//
+// Los IDs del outbox se asignan al insertar, no al confirmar: una transacción lenta
+// puede confirmar un ID menor después de que otra ya confirmó uno mayor. Por eso el
+// cursor solo avanza sobre eventos con más de safetyLag de antigüedad; lo más reciente
+// se vuelve a leer en el siguiente sondeo y la secuencia publicada nunca retrocede,
+// ni en vivo ni al reanudar con Last-Event-ID. Un evento se publica entonces entre
+// safetyLag y safetyLag + pollInterval después de guardarse.
+type StreamHub struct {
+	outbox       ports.OutboxReader
+	pollInterval time.Duration
+	safetyLag    time.Duration
+	now          func() time.Time
+
+	mu          sync.Mutex
+	last        uint
+	subscribers map[*StreamSubscription]struct{}
+}
+
+func NewStreamHub(outbox ports.OutboxReader, pollInterval time.Duration) *StreamHub {
+	return &StreamHub{
+		outbox:       outbox,
+		pollInterval: pollInterval,
+		safetyLag:    defaultStreamSafetyLag,
+		now:          time.Now,
+		subscribers:  map[*StreamSubscription]struct{}{},
+	}
+}
+
+// WithSafetyLag cambia la espera antes de publicar un evento; debe superar lo que tarda
+// en confirmarse la transacción que lo escribe.
+func (h *StreamHub) WithSafetyLag(lag time.Duration) *StreamHub {
+	h.safetyLag = lag
+	return h
+}
+
+// WithClock reemplaza el reloj con el que se mide la antigüedad de los eventos.
+func (h *StreamHub) WithClock(now func() time.Time) *StreamHub {
+	h.now = now
+	return h
+}
+
+// Start fija la secuencia inicial y publica los eventos nuevos hasta que ctx se cancele.
+func (h *StreamHub) Start(ctx context.Context) error {
+	latest, err := h.outbox.LatestEventID()
+	if err != nil {
+		return err
+	}
+	h.mu.Lock()
+	h.last = latest
+	h.mu.Unlock()
+
+	go func() {
+		ticker := time.NewTicker(h.pollInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+			if err := h.Poll(); err != nil {
+				log.Printf("⚠ Error leyendo el outbox para el stream: %v", err)
+			}
+		}
+	}()
+	return nil
+}
+
+// Poll publica, en orden, los eventos del outbox posteriores al último publicado que
+// ya tienen más de safetyLag. Se detiene en el primero más reciente para no saltear
+// un ID menor que todavía no se confirmó.
+func (h *StreamHub) Poll() error {
+	for {
+		h.mu.Lock()
+		last := h.last
+		h.mu.Unlock()
+
+		events, err := h.outbox.EventsAfter(last, streamBatchSize)
+		if err != nil || len(events) == 0 {
+			return err
+		}
+
+		settled := h.now().Add(-h.safetyLag)
+		h.mu.Lock()
+		for _, event := range events {
+			if event.ID <= h.last {
+				continue
+			}
+			if event.CreatedAt.After(settled) {
+				h.mu.Unlock()
+				return nil // Se publica en un sondeo posterior
+			}
+			h.last = event.ID
+			if stockEvent, ok := toStockEvent(event); ok {
+				h.broadcast(stockEvent)
+			}
+		}
+		h.mu.Unlock()
+
+		if len(events) < streamBatchSize {
+			return nil
+		}
+	}
+}
+
+// broadcast debe llamarse con h.mu tomado.
+func (h *StreamHub) broadcast(event domain.StockEvent) {
+	for sub := range h.subscribers {
+		if !sub.filter.matches(event.Stock) {
+			continue
+		}
+		select {
+		case sub.ch <- event:
+		default:
+			log.Printf("⚠ Suscriptor lento del stream desconectado en la secuencia %d", event.Sequence)
+			delete(h.subscribers, sub)
+			close(sub.ch)
+		}
+	}
+}
+
+// Subscribe registra un suscriptor. Si resume es true, Backlog entrega luego los eventos
+// persistidos posteriores a lastEventID que ya se publicaron, para reanudar sin huecos
+// ni duplicados: lo que llegue por C es siempre posterior al backlog.
+func (h *StreamHub) Subscribe(filter StreamFilter, lastEventID uint, resume bool) *StreamSubscription {
+	filter.Tickers = normalizeTickers(filter.Tickers)
+	ch := make(chan domain.StockEvent, streamBufferSize)
+	sub := &StreamSubscription{C: ch, ch: ch, filter: filter, resume: resume, after: lastEventID}
+
+	h.mu.Lock()
+	h.subscribers[sub] = struct{}{}
+	sub.cutoff = h.last
+	h.mu.Unlock()
+	return sub
+}
+
+// Backlog entrega a fn, en orden, los eventos que el suscriptor se perdió desde
+// lastEventID. Lee el outbox por páginas, sin cargar todo el backlog en memoria; un
+// error de fn corta la lectura.
+func (h *StreamHub) Backlog(sub *StreamSubscription, fn func(domain.StockEvent) error) error {
+	if !sub.resume {
+		return nil
+	}
+	for after := sub.after; after < sub.cutoff; {
+		events, err := h.outbox.EventsAfter(after, streamBatchSize)
+		if err != nil || len(events) == 0 {
+			return err
+		}
+		for _, event := range events {
+			if event.ID > sub.cutoff {
+				return nil
+			}
+			if stockEvent, ok := toStockEvent(event); ok && sub.filter.matches(stockEvent.Stock) {
+				if err := fn(stockEvent); err != nil {
+					return err
+				}
+			}
+		}
+		after = events[len(events)-1].ID
+	}
+	return nil
+}
+
+func (h *StreamHub) Unsubscribe(sub *StreamSubscription) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if _, ok := h.subscribers[sub]; ok {
+		delete(h.subscribers, sub)
+		close(sub.ch)
+	}
+}
+
+func toStockEvent(event domain.OutboxEvent) (domain.StockEvent, bool) {
+	if event.Type != domain.EventStockCreated {
+		return domain.StockEvent{}, false
+	}
+	stock, err := event.Stock()
+	if err != nil {
+		log.Printf("⚠ Evento de outbox %d con payload inválido: %v", event.ID, err)
+		return domain.StockEvent{}, false
+	}
+	return domain.StockEvent{Sequence: event.ID, Stock: stock}, true
+}
@@ -0,0 +1,216 @@
+package services
+
+import (
+	"errors"
+	"sort"
+	"testing"
+	"time"
+
+	"recommender/internal/core/domain"
+)
+
+type mockOutboxReader struct {
+	events []domain.OutboxEvent
+	reads  int
+}
+
+func (m *mockOutboxReader) EventsAfter(afterID uint, limit int) ([]domain.OutboxEvent, error) {
+	m.reads++
+	var events []domain.OutboxEvent
+	for _, event := range m.events {
+		if event.ID > afterID && len(events) < limit {
+			events = append(events, event)
+		}
+	}
+	return events, nil
+}
+
+func (m *mockOutboxReader) LatestEventID() (uint, error) {
+	if len(m.events) == 0 {
+		return 0, nil
+	}
+	return m.events[len(m.events)-1].ID, nil
+}
+
+func (m *mockOutboxReader) add(stock domain.Stock) {
+	event, _ := domain.NewStockCreatedEvent(stock)
+	event.ID = uint(len(m.events) + 1)
+	m.events = append(m.events, event)
+}
+
+// commit agrega un evento con ID y fecha dados, como una transacción que confirma
+// fuera de orden; el outbox se sigue leyendo ordenado por ID.
+func (m *mockOutboxReader) commit(id uint, createdAt time.Time, ticker string) {
+	event, _ := domain.NewStockCreatedEvent(domain.Stock{Ticker: ticker})
+	event.ID = id
+	event.CreatedAt = createdAt
+	m.events = append(m.events, event)
+	sort.Slice(m.events, func(i, j int) bool { return m.events[i].ID < m.events[j].ID })
+}
+
+func collectBacklog(hub *StreamHub, sub *StreamSubscription) ([]domain.StockEvent, error) {
+	var backlog []domain.StockEvent
+	err := hub.Backlog(sub, func(event domain.StockEvent) error {
+		backlog = append(backlog, event)
+		return nil
+	})
+	return backlog, err
+}
+
+func receive(t *testing.T, sub *StreamSubscription) domain.StockEvent {
+	t.Helper()
+	select {
+	case event := <-sub.C:
+		return event
+	case <-time.After(time.Second):
+		t.Fatal("timed out waiting for stream event")
+		return domain.StockEvent{}
+	}
+}
+
+func TestStreamHub_PublishesFilteredEvents(t *testing.T) {
+	outbox := &mockOutboxReader{}
+	outbox.add(domain.Stock{Ticker: "OLD"})
+	hub := NewStreamHub(outbox, time.Hour)
+	hub.last, _ = outbox.LatestEventID()
+
+	all := hub.Subscribe(StreamFilter{}, 0, false)
+	goldman := hub.Subscribe(StreamFilter{Tickers: []string{"aapl"}, Brokerage: "goldman"}, 0, false)
+
+	outbox.add(domain.Stock{Ticker: "AAPL", Brokerage: "Morgan Stanley"})
+	outbox.add(domain.Stock{Ticker: "AAPL", Brokerage: "The Goldman Sachs Group"})
+	if err := hub.Poll(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if first, second := receive(t, all), receive(t, all); first.Sequence != 2 || second.Sequence != 3 {
+		t.Errorf("expected sequences 2 and 3, got %d and %d", first.Sequence, second.Sequence)
+	}
+	if event := receive(t, goldman); event.Sequence != 3 {
+		t.Errorf("expected only the Goldman event, got %+v", event)
+	}
+	select {
+	case event := <-goldman.C:
+		t.Errorf("unexpected event %+v", event)
+	default:
+	}
+}
+
+func TestStreamHub_ResumeFromLastEventID(t *testing.T) {
+	outbox := &mockOutboxReader{}
+	for _, ticker := range []string{"A", "B", "C"} {
+		outbox.add(domain.Stock{Ticker: ticker})
+	}
+	hub := NewStreamHub(outbox, time.Hour)
+	hub.last, _ = outbox.LatestEventID()
+
+	sub := hub.Subscribe(StreamFilter{}, 1, true)
+	backlog, err := collectBacklog(hub, sub)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(backlog) != 2 || backlog[0].Stock.Ticker != "B" || backlog[1].Sequence != 3 {
+		t.Fatalf("unexpected backlog: %+v", backlog)
+	}
+
+	outbox.add(domain.Stock{Ticker: "D"})
+	hub.Poll()
+	if event := receive(t, sub); event.Sequence != 4 {
+		t.Errorf("expected live event 4 after the backlog, got %+v", event)
+	}
+
+	hub.Unsubscribe(sub)
+	if _, open := <-sub.C; open {
+		t.Errorf("expected channel to be closed after unsubscribe")
+	}
+}
+
+func TestStreamHub_BacklogIsReadInPages(t *testing.T) {
+	outbox := &mockOutboxReader{}
+	for i := 0; i < 2*streamBatchSize+1; i++ {
+		outbox.add(domain.Stock{Ticker: "A"})
+	}
+	hub := NewStreamHub(outbox, time.Hour)
+	hub.last, _ = outbox.LatestEventID()
+
+	sub := hub.Subscribe(StreamFilter{}, 0, true)
+	outbox.reads = 0
+	next := uint(1)
+	err := hub.Backlog(sub, func(event domain.StockEvent) error {
+		if event.Sequence != next {
+			t.Fatalf("expected sequence %d, got %d", next, event.Sequence)
+		}
+		next++
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if next != 2*streamBatchSize+2 || outbox.reads != 3 {
+		t.Errorf("expected the whole backlog in 3 pages, got %d events in %d reads", next-1, outbox.reads)
+	}
+
+	// Un error del consumidor corta la lectura sin pedir más páginas
+	outbox.reads = 0
+	stop := errors.New("client gone")
+	if err := hub.Backlog(sub, func(domain.StockEvent) error { return stop }); err != stop || outbox.reads != 1 {
+		t.Errorf("expected the backlog to stop on the first error, got %v after %d reads", err, outbox.reads)
+	}
+}
+
+func TestStreamHub_DropsSlowSubscribers(t *testing.T) {
+	outbox := &mockOutboxReader{}
+	hub := NewStreamHub(outbox, time.Hour)
+	sub := hub.Subscribe(StreamFilter{}, 0, false)
+
+	for i := 0; i <= streamBufferSize; i++ {
+		outbox.add(domain.Stock{Ticker: "SPAM"})
+	}
+	hub.Poll()
+
+	count := 0
+	for range sub.C {
+		count++
+	}
+	if count != streamBufferSize {
+		t.Errorf("expected the buffer to be delivered before closing, got %d", count)
+	}
+}
+
+func TestStreamHub_WaitsForLateCommitsBeforeAdvancing(t *testing.T) {
+	now := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+	outbox := &mockOutboxReader{}
+	hub := NewStreamHub(outbox, time.Hour).
+		WithSafetyLag(2 * time.Second).
+		WithClock(func() time.Time { return now })
+	sub := hub.Subscribe(StreamFilter{}, 0, false)
+
+	// El 3 confirma antes que el 2, que sigue en una transacción abierta
+	outbox.commit(1, now.Add(-time.Minute), "A")
+	outbox.commit(3, now.Add(-time.Second), "C")
+	hub.Poll()
+	if event := receive(t, sub); event.Sequence != 1 {
+		t.Fatalf("expected event 1, got %+v", event)
+	}
+	select {
+	case event := <-sub.C:
+		t.Fatalf("event %d published before the safety lag", event.Sequence)
+	default:
+	}
+
+	outbox.commit(2, now.Add(-1500*time.Millisecond), "B")
+	now = now.Add(2 * time.Second)
+	hub.Poll()
+	if first, second := receive(t, sub), receive(t, sub); first.Sequence != 2 || second.Sequence != 3 {
+		t.Errorf("expected the late event 2 before 3, got %d and %d", first.Sequence, second.Sequence)
+	}
+
+	// Reanudar desde el 1 incluye al 2 aunque se haya confirmado después del 3
+	backlog, err := collectBacklog(hub, hub.Subscribe(StreamFilter{}, 1, true))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(backlog) != 2 || backlog[0].Sequence != 2 || backlog[1].Sequence != 3 {
+		t.Errorf("unexpected backlog: %+v", backlog)
+	}
+}
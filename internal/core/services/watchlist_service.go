@@ -0,0 +1,135 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"recommender/internal/core/domain"
+	"recommender/internal/core/ports"
+
+	"gorm.io/gorm"
+)
+
+var (
+	ErrWatchlistNotFound  = errors.New("watchlist not found")
+	ErrInvalidWatchlist   = errors.New("invalid watchlist")
+	ErrDuplicateWatchlist = errors.New("watchlist name already exists")
+)
+
+// WatchlistService administra las watchlists de cada usuario y acota a ellas
+// el feed de ratings y las recomendaciones de StockService.
+type WatchlistService struct {
+	repository ports.WatchlistRepository
+	stocks     *StockService
+}
+
+func NewWatchlistService(repo ports.WatchlistRepository, stocks *StockService) *WatchlistService {
+	return &WatchlistService{repository: repo, stocks: stocks}
+}
+
+func (s *WatchlistService) List(userID string) ([]domain.Watchlist, error) {
+	return s.repository.ListByUser(userID)
+}
+
+func (s *WatchlistService) Get(userID string, id uint) (*domain.Watchlist, error) {
+	watchlist, err := s.repository.GetByID(userID, id)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrWatchlistNotFound
+	}
+	return watchlist, err
+}
+
+func (s *WatchlistService) Create(userID, name string, tickers []string) (*domain.Watchlist, error) {
+	watchlist := &domain.Watchlist{UserID: userID}
+	if err := s.apply(watchlist, name, tickers); err != nil {
+		return nil, err
+	}
+	if err := s.repository.Create(watchlist); err != nil {
+		return nil, err
+	}
+	return watchlist, nil
+}
+
+func (s *WatchlistService) Update(userID string, id uint, name string, tickers []string) (*domain.Watchlist, error) {
+	watchlist, err := s.Get(userID, id)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.apply(watchlist, name, tickers); err != nil {
+		return nil, err
+	}
+	if err := s.repository.Update(watchlist); err != nil {
+		return nil, err
+	}
+	return watchlist, nil
+}
+
+func (s *WatchlistService) Delete(userID string, id uint) error {
+	err := s.repository.Delete(userID, id)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return ErrWatchlistNotFound
+	}
+	return err
+}
+
+// Recommendations calcula el ranking considerando solo los tickers de la watchlist.
+func (s *WatchlistService) Recommendations(userID string, id uint, params domain.RecommendationParams) (*domain.RecommendationList, error) {
+	watchlist, err := s.Get(userID, id)
+	if err != nil {
+		return nil, err
+	}
+	params.Tickers = watchlist.Tickers
+	if len(params.Tickers) == 0 {
+		return &domain.RecommendationList{Params: params, Items: []domain.Recommendation{}}, nil
+	}
+	return s.stocks.GetTopRecommendedStocks(params)
+}
+
+// Feed lista los eventos de rating de los tickers de la watchlist.
+func (s *WatchlistService) Feed(userID string, id uint, filter domain.StockFilter, limit, offset int) ([]domain.Stock, error) {
+	watchlist, err := s.Get(userID, id)
+	if err != nil {
+		return nil, err
+	}
+	if len(watchlist.Tickers) == 0 {
+		return []domain.Stock{}, nil
+	}
+	filter.Tickers = watchlist.Tickers
+	return s.stocks.SearchStocks(filter, limit, offset)
+}
+
+// apply valida y asigna nombre y tickers; el nombre debe ser único para el usuario.
+func (s *WatchlistService) apply(watchlist *domain.Watchlist, name string, tickers []string) error {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return fmt.Errorf("%w: name is required", ErrInvalidWatchlist)
+	}
+	if name != watchlist.Name {
+		existing, err := s.repository.GetByName(watchlist.UserID, name)
+		if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+			return err
+		}
+		if existing != nil {
+			return ErrDuplicateWatchlist
+		}
+	}
+	watchlist.Name = name
+	watchlist.Tickers = normalizeTickers(tickers)
+	return nil
+}
+
+// normalizeTickers pasa a mayúsculas, descarta vacíos y quita duplicados conservando el orden.
+func normalizeTickers(tickers []string) []string {
+	seen := map[string]bool{}
+	normalized := make([]string, 0, len(tickers))
+	for _, ticker := range tickers {
+		ticker = strings.ToUpper(strings.TrimSpace(ticker))
+		if ticker == "" || seen[ticker] {
+			continue
+		}
+		seen[ticker] = true
+		normalized = append(normalized, ticker)
+	}
+	return normalized
+}
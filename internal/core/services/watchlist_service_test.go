@@ -0,0 +1,150 @@
+package services
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"recommender/internal/core/domain"
+
+	"gorm.io/gorm"
+)
+
+type mockWatchlistRepository struct {
+	watchlists map[uint]domain.Watchlist
+	nextID     uint
+}
+
+func newMockWatchlistRepository() *mockWatchlistRepository {
+	return &mockWatchlistRepository{watchlists: map[uint]domain.Watchlist{}}
+}
+
+func (m *mockWatchlistRepository) Create(watchlist *domain.Watchlist) error {
+	m.nextID++
+	watchlist.ID = m.nextID
+	m.watchlists[watchlist.ID] = *watchlist
+	return nil
+}
+
+func (m *mockWatchlistRepository) Update(watchlist *domain.Watchlist) error {
+	m.watchlists[watchlist.ID] = *watchlist
+	return nil
+}
+
+func (m *mockWatchlistRepository) Delete(userID string, id uint) error {
+	if _, err := m.GetByID(userID, id); err != nil {
+		return err
+	}
+	delete(m.watchlists, id)
+	return nil
+}
+
+func (m *mockWatchlistRepository) GetByID(userID string, id uint) (*domain.Watchlist, error) {
+	watchlist, ok := m.watchlists[id]
+	if !ok || watchlist.UserID != userID {
+		return nil, gorm.ErrRecordNotFound
+	}
+	return &watchlist, nil
+}
+
+func (m *mockWatchlistRepository) GetByName(userID, name string) (*domain.Watchlist, error) {
+	for _, watchlist := range m.watchlists {
+		if watchlist.UserID == userID && watchlist.Name == name {
+			return &watchlist, nil
+		}
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+func (m *mockWatchlistRepository) ListByUser(userID string) ([]domain.Watchlist, error) {
+	var watchlists []domain.Watchlist
+	for _, watchlist := range m.watchlists {
+		if watchlist.UserID == userID {
+			watchlists = append(watchlists, watchlist)
+		}
+	}
+	return watchlists, nil
+}
+
+func TestWatchlistService_CreateValidatesAndNormalizes(t *testing.T) {
+	service := NewWatchlistService(newMockWatchlistRepository(), nil)
+
+	watchlist, err := service.Create("alice", " chips ", []string{"nvda", "AMD", " NVDA", ""})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if watchlist.Name != "chips" || len(watchlist.Tickers) != 2 || watchlist.Tickers[0] != "NVDA" {
+		t.Errorf("unexpected watchlist: %+v", watchlist)
+	}
+
+	if _, err := service.Create("alice", "chips", nil); !errors.Is(err, ErrDuplicateWatchlist) {
+		t.Errorf("expected ErrDuplicateWatchlist, got %v", err)
+	}
+	if _, err := service.Create("alice", " ", nil); !errors.Is(err, ErrInvalidWatchlist) {
+		t.Errorf("expected ErrInvalidWatchlist, got %v", err)
+	}
+	if _, err := service.Create("bob", "chips", nil); err != nil {
+		t.Errorf("names are unique per user, got %v", err)
+	}
+}
+
+func TestWatchlistService_OtherUsersWatchlistIsNotFound(t *testing.T) {
+	service := NewWatchlistService(newMockWatchlistRepository(), nil)
+	watchlist, _ := service.Create("alice", "chips", []string{"NVDA"})
+
+	if _, err := service.Get("bob", watchlist.ID); !errors.Is(err, ErrWatchlistNotFound) {
+		t.Errorf("expected ErrWatchlistNotFound, got %v", err)
+	}
+	if err := service.Delete("bob", watchlist.ID); !errors.Is(err, ErrWatchlistNotFound) {
+		t.Errorf("expected ErrWatchlistNotFound, got %v", err)
+	}
+}
+
+func TestWatchlistService_ScopesFeedAndRecommendations(t *testing.T) {
+	now := time.Now()
+	repo := &mockStockRepository{stocks: []domain.Stock{
+		{Ticker: "NVDA", TargetFrom: 100, TargetTo: 110, Brokerage: "Others", Time: now},
+		{Ticker: "AAPL", TargetFrom: 100, TargetTo: 150, Brokerage: "JP Morgan", Time: now},
+	}}
+	service := NewWatchlistService(newMockWatchlistRepository(), NewStockService(repo, nil))
+	watchlist, _ := service.Create("alice", "chips", []string{"NVDA"})
+
+	top, err := service.Recommendations("alice", watchlist.ID, DefaultRecommendationParams())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(top.Items) != 1 || top.Items[0].Ticker != "NVDA" {
+		t.Errorf("expected only NVDA, got %+v", top.Items)
+	}
+
+	feed, err := service.Feed("alice", watchlist.ID, domain.StockFilter{}, 10, 0)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(feed) != 1 || feed[0].Ticker != "NVDA" {
+		t.Errorf("expected only NVDA events, got %+v", feed)
+	}
+
+	empty, _ := service.Create("alice", "empty", nil)
+	feed, _ = service.Feed("alice", empty.ID, domain.StockFilter{}, 10, 0)
+	if len(feed) != 0 {
+		t.Errorf("expected an empty watchlist to return no events, got %+v", feed)
+	}
+}
+
+func TestWatchlistService_RecommendationsAreNotSnapshotted(t *testing.T) {
+	repo := &mockStockRepository{stocks: []domain.Stock{
+		{Ticker: "NVDA", TargetFrom: 100, TargetTo: 110, Brokerage: "Others", Time: time.Now()},
+	}}
+	snapshots := &mockSnapshotRepository{}
+	stocks := NewStockService(repo, nil).WithSnapshots(NewSnapshotService(snapshots))
+	service := NewWatchlistService(newMockWatchlistRepository(), stocks)
+	watchlist, _ := service.Create("alice", "chips", []string{"NVDA"})
+
+	if _, err := service.Recommendations("alice", watchlist.ID, DefaultRecommendationParams()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(snapshots.snapshots) != 0 {
+		t.Errorf("expected no global snapshot of a watchlist list, got %+v", snapshots.snapshots)
+	}
+}
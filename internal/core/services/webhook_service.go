@@ -0,0 +1,299 @@
+package services
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"recommender/internal/core/domain"
+	"recommender/internal/core/ports"
+
+	"gorm.io/gorm"
+)
+
+// Encabezados de las entregas de webhook.
+const (
+	WebhookSignatureHeader = "X-Webhook-Signature"
+	WebhookTimestampHeader = "X-Webhook-Timestamp"
+	WebhookEventHeader     = "X-Webhook-Event"
+	WebhookDeliveryHeader  = "X-Webhook-Delivery"
+)
+
+var (
+	ErrWebhookNotFound         = errors.New("webhook subscription not found")
+	ErrWebhookDeliveryNotFound = errors.New("webhook delivery not found")
+	ErrInvalidWebhook          = errors.New("invalid webhook subscription")
+)
+
+// WebhookRetryPolicy controla los reintentos: la espera se duplica en cada intento
+// fallido desde Backoff hasta MaxBackoff, y tras MaxAttempts la entrega queda fallida.
+type WebhookRetryPolicy struct {
+	MaxAttempts int
+	Backoff     time.Duration
+	MaxBackoff  time.Duration
+}
+
+func DefaultWebhookRetryPolicy() WebhookRetryPolicy {
+	return WebhookRetryPolicy{MaxAttempts: 8, Backoff: 10 * time.Second, MaxBackoff: time.Hour}
+}
+
+// delay devuelve la espera antes del intento siguiente a `attempts` intentos fallidos.
+func (p WebhookRetryPolicy) delay(attempts int) time.Duration {
+	delay := p.Backoff
+	for i := 1; i < attempts && delay < p.MaxBackoff; i++ {
+		delay *= 2
+	}
+	if delay > p.MaxBackoff {
+		delay = p.MaxBackoff
+	}
+	return delay
+}
+
+// WebhookService administra las suscripciones y entrega los eventos del outbox:
+// DispatchOutbox crea una entrega por suscripción interesada y DeliverDue envía las
+// entregas pendientes firmadas, reprogramando las fallidas.
+type WebhookService struct {
+	repository ports.WebhookRepository
+	sender     ports.WebhookSender
+	policy     WebhookRetryPolicy
+	batchSize  int
+	now        func() time.Time
+}
+
+func NewWebhookService(repo ports.WebhookRepository, sender ports.WebhookSender, policy WebhookRetryPolicy) *WebhookService {
+	return &WebhookService{repository: repo, sender: sender, policy: policy, batchSize: 100, now: time.Now}
+}
+
+// CreateSubscription valida la URL y genera un secreto si no se indicó uno.
+func (s *WebhookService) CreateSubscription(subscription *domain.WebhookSubscription) error {
+	parsed, err := url.Parse(strings.TrimSpace(subscription.URL))
+	if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+		return fmt.Errorf("%w: url must be an absolute http(s) URL", ErrInvalidWebhook)
+	}
+	subscription.URL = parsed.String()
+	for _, action := range subscription.ActionTypes {
+		if !action.Valid() {
+			return fmt.Errorf("%w: unknown action type '%s'", ErrInvalidWebhook, action)
+		}
+	}
+	subscription.Tickers = normalizeTickers(subscription.Tickers)
+	if subscription.Secret == "" {
+		secret := make([]byte, 32)
+		if _, err := rand.Read(secret); err != nil {
+			return err
+		}
+		subscription.Secret = hex.EncodeToString(secret)
+	}
+	return s.repository.CreateSubscription(subscription)
+}
+
+// ListSubscriptions oculta los secretos.
+func (s *WebhookService) ListSubscriptions(userID string) ([]domain.WebhookSubscription, error) {
+	subscriptions, err := s.repository.ListSubscriptions(userID)
+	for i := range subscriptions {
+		subscriptions[i].Secret = ""
+	}
+	return subscriptions, err
+}
+
+func (s *WebhookService) DeleteSubscription(userID string, id uint) error {
+	err := s.repository.DeleteSubscription(userID, id)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return ErrWebhookNotFound
+	}
+	return err
+}
+
+// ListDeliveries es el registro de entregas de una suscripción del usuario.
+func (s *WebhookService) ListDeliveries(userID string, subscriptionID uint, limit, offset int) ([]domain.WebhookDelivery, error) {
+	if _, err := s.repository.GetSubscription(userID, subscriptionID); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrWebhookNotFound
+		}
+		return nil, err
+	}
+	return s.repository.ListDeliveries(subscriptionID, limit, offset)
+}
+
+// Replay vuelve a encolar una entrega (exitosa o fallida) para enviarla de inmediato.
+func (s *WebhookService) Replay(userID string, deliveryID uint) (*domain.WebhookDelivery, error) {
+	delivery, err := s.repository.GetDelivery(deliveryID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrWebhookDeliveryNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	if _, err := s.repository.GetSubscription(userID, delivery.SubscriptionID); err != nil {
+		return nil, ErrWebhookDeliveryNotFound // No se revela la existencia de entregas ajenas
+	}
+
+	now := s.now()
+	delivery.Status = domain.DeliveryPending
+	delivery.Attempts = 0
+	delivery.LastError = ""
+	delivery.NextAttemptAt = &now
+	if err := s.repository.SaveDelivery(delivery); err != nil {
+		return nil, err
+	}
+	return delivery, nil
+}
+
+// DispatchOutbox reparte los eventos pendientes del outbox entre las suscripciones activas.
+func (s *WebhookService) DispatchOutbox() (int, error) {
+	events, err := s.repository.PendingOutboxEvents(s.batchSize)
+	if err != nil || len(events) == 0 {
+		return 0, err
+	}
+	subscriptions, err := s.repository.ListActiveSubscriptions()
+	if err != nil {
+		return 0, err
+	}
+
+	for _, event := range events {
+		now := s.now()
+		var deliveries []domain.WebhookDelivery
+		if stock, err := event.Stock(); err != nil {
+			log.Printf("⚠ Evento de outbox %d con payload inválido: %v", event.ID, err)
+		} else {
+			action := actionTypeOf(stock)
+			for _, subscription := range subscriptions {
+				if subscription.Accepts(stock, action) {
+					deliveries = append(deliveries, domain.WebhookDelivery{
+						SubscriptionID: subscription.ID,
+						OutboxEventID:  event.ID,
+						Status:         domain.DeliveryPending,
+						NextAttemptAt:  &now,
+					})
+				}
+			}
+		}
+		if err := s.repository.FanOut(event, deliveries, now); err != nil {
+			return 0, err
+		}
+	}
+	return len(events), nil
+}
+
+// DeliverDue envía las entregas cuyo próximo intento ya venció.
+func (s *WebhookService) DeliverDue() (int, error) {
+	deliveries, err := s.repository.DueDeliveries(s.now(), s.batchSize)
+	if err != nil || len(deliveries) == 0 {
+		return 0, err
+	}
+	active, err := s.repository.ListActiveSubscriptions()
+	if err != nil {
+		return 0, err
+	}
+	subscriptions := make(map[uint]domain.WebhookSubscription, len(active))
+	for _, subscription := range active {
+		subscriptions[subscription.ID] = subscription
+	}
+
+	for i := range deliveries {
+		s.attempt(&deliveries[i], subscriptions)
+		if err := s.repository.SaveDelivery(&deliveries[i]); err != nil {
+			return i, err
+		}
+	}
+	return len(deliveries), nil
+}
+
+func (s *WebhookService) attempt(delivery *domain.WebhookDelivery, subscriptions map[uint]domain.WebhookSubscription) {
+	delivery.Attempts++
+	status, err := s.send(delivery, subscriptions)
+	delivery.LastStatusCode = status
+
+	now := s.now()
+	if err == nil {
+		delivery.Status = domain.DeliverySucceeded
+		delivery.LastError = ""
+		delivery.DeliveredAt = &now
+		delivery.NextAttemptAt = nil
+		return
+	}
+
+	delivery.LastError = err.Error()
+	if delivery.Attempts >= s.policy.MaxAttempts {
+		delivery.Status = domain.DeliveryFailed
+		delivery.NextAttemptAt = nil
+		log.Printf("❌ Webhook %d: entrega %d fallida tras %d intentos: %v", delivery.SubscriptionID, delivery.ID, delivery.Attempts, err)
+		return
+	}
+	next := now.Add(s.policy.delay(delivery.Attempts))
+	delivery.NextAttemptAt = &next
+}
+
+func (s *WebhookService) send(delivery *domain.WebhookDelivery, subscriptions map[uint]domain.WebhookSubscription) (int, error) {
+	subscription, ok := subscriptions[delivery.SubscriptionID]
+	if !ok {
+		return 0, fmt.Errorf("suscripción %d inexistente o inactiva", delivery.SubscriptionID)
+	}
+	event, err := s.repository.GetOutboxEvent(delivery.OutboxEventID)
+	if err != nil {
+		return 0, err
+	}
+
+	body, err := json.Marshal(map[string]interface{}{
+		"id":         event.ID,
+		"type":       event.Type,
+		"created_at": event.CreatedAt,
+		"data":       json.RawMessage(event.Payload),
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	timestamp := strconv.FormatInt(s.now().Unix(), 10)
+	headers := map[string]string{
+		"Content-Type":         "application/json",
+		WebhookEventHeader:     event.Type,
+		WebhookDeliveryHeader:  strconv.FormatUint(uint64(delivery.ID), 10),
+		WebhookTimestampHeader: timestamp,
+		WebhookSignatureHeader: SignWebhookPayload(subscription.Secret, timestamp, body),
+	}
+	status, err := s.sender.Send(subscription.URL, headers, body)
+	if err == nil && (status < 200 || status >= 300) {
+		err = fmt.Errorf("webhook respondió %d", status)
+	}
+	return status, err
+}
+
+// Run despacha el outbox y entrega los webhooks cada `interval` hasta que ctx se cancele.
+func (s *WebhookService) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		if _, err := s.DispatchOutbox(); err != nil {
+			log.Printf("⚠ Error despachando el outbox: %v", err)
+		}
+		if _, err := s.DeliverDue(); err != nil {
+			log.Printf("⚠ Error entregando webhooks: %v", err)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// SignWebhookPayload devuelve "sha256=<hex>" del HMAC-SHA256 de "timestamp.body".
+// Los receptores recalculan la firma con su secreto y descartan timestamps viejos.
+func SignWebhookPayload(secret, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,232 @@
+package services
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"recommender/internal/core/domain"
+
+	"gorm.io/gorm"
+)
+
+type mockWebhookRepository struct {
+	subscriptions []domain.WebhookSubscription
+	outbox        []domain.OutboxEvent
+	deliveries    []domain.WebhookDelivery
+}
+
+func (m *mockWebhookRepository) CreateSubscription(subscription *domain.WebhookSubscription) error {
+	subscription.ID = uint(len(m.subscriptions) + 1)
+	m.subscriptions = append(m.subscriptions, *subscription)
+	return nil
+}
+
+func (m *mockWebhookRepository) GetSubscription(userID string, id uint) (*domain.WebhookSubscription, error) {
+	for _, subscription := range m.subscriptions {
+		if subscription.ID == id && subscription.UserID == userID {
+			return &subscription, nil
+		}
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+func (m *mockWebhookRepository) ListSubscriptions(userID string) ([]domain.WebhookSubscription, error) {
+	var subscriptions []domain.WebhookSubscription
+	for _, subscription := range m.subscriptions {
+		if subscription.UserID == userID {
+			subscriptions = append(subscriptions, subscription)
+		}
+	}
+	return subscriptions, nil
+}
+
+func (m *mockWebhookRepository) DeleteSubscription(userID string, id uint) error {
+	return gorm.ErrRecordNotFound
+}
+
+func (m *mockWebhookRepository) ListActiveSubscriptions() ([]domain.WebhookSubscription, error) {
+	var subscriptions []domain.WebhookSubscription
+	for _, subscription := range m.subscriptions {
+		if subscription.Active {
+			subscriptions = append(subscriptions, subscription)
+		}
+	}
+	return subscriptions, nil
+}
+
+func (m *mockWebhookRepository) PendingOutboxEvents(limit int) ([]domain.OutboxEvent, error) {
+	var events []domain.OutboxEvent
+	for _, event := range m.outbox {
+		if event.DispatchedAt == nil {
+			events = append(events, event)
+		}
+	}
+	return events, nil
+}
+
+func (m *mockWebhookRepository) GetOutboxEvent(id uint) (*domain.OutboxEvent, error) {
+	return &m.outbox[id-1], nil
+}
+
+func (m *mockWebhookRepository) FanOut(event domain.OutboxEvent, deliveries []domain.WebhookDelivery, at time.Time) error {
+	for _, delivery := range deliveries {
+		delivery.ID = uint(len(m.deliveries) + 1)
+		m.deliveries = append(m.deliveries, delivery)
+	}
+	m.outbox[event.ID-1].DispatchedAt = &at
+	return nil
+}
+
+func (m *mockWebhookRepository) DueDeliveries(now time.Time, limit int) ([]domain.WebhookDelivery, error) {
+	var due []domain.WebhookDelivery
+	for _, delivery := range m.deliveries {
+		if delivery.Status == domain.DeliveryPending && !delivery.NextAttemptAt.After(now) {
+			due = append(due, delivery)
+		}
+	}
+	return due, nil
+}
+
+func (m *mockWebhookRepository) GetDelivery(id uint) (*domain.WebhookDelivery, error) {
+	if id == 0 || int(id) > len(m.deliveries) {
+		return nil, gorm.ErrRecordNotFound
+	}
+	delivery := m.deliveries[id-1]
+	return &delivery, nil
+}
+
+func (m *mockWebhookRepository) SaveDelivery(delivery *domain.WebhookDelivery) error {
+	m.deliveries[delivery.ID-1] = *delivery
+	return nil
+}
+
+func (m *mockWebhookRepository) ListDeliveries(subscriptionID uint, limit, offset int) ([]domain.WebhookDelivery, error) {
+	return m.deliveries, nil
+}
+
+type fakeWebhookSender struct {
+	statuses []int
+	requests []map[string]string
+	bodies   []string
+}
+
+func (f *fakeWebhookSender) Send(url string, headers map[string]string, body []byte) (int, error) {
+	f.requests = append(f.requests, headers)
+	f.bodies = append(f.bodies, string(body))
+	status := f.statuses[0]
+	if len(f.statuses) > 1 {
+		f.statuses = f.statuses[1:]
+	}
+	return status, nil
+}
+
+func outboxFor(stocks ...domain.Stock) []domain.OutboxEvent {
+	var events []domain.OutboxEvent
+	for i, stock := range stocks {
+		event, _ := domain.NewStockCreatedEvent(stock)
+		event.ID = uint(i + 1)
+		events = append(events, event)
+	}
+	return events
+}
+
+func TestWebhookService_DispatchAndDeliver(t *testing.T) {
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	repo := &mockWebhookRepository{outbox: outboxFor(
+		domain.Stock{ID: 1, Ticker: "AAPL", Action: "downgraded by"},
+		domain.Stock{ID: 2, Ticker: "MSFT", Action: "upgraded by"},
+	)}
+	sender := &fakeWebhookSender{statuses: []int{500, 200}}
+	service := NewWebhookService(repo, sender, WebhookRetryPolicy{MaxAttempts: 3, Backoff: time.Minute, MaxBackoff: time.Hour})
+	service.now = func() time.Time { return now }
+
+	service.CreateSubscription(&domain.WebhookSubscription{UserID: "alice", URL: "https://hooks.example.com/in", Secret: "topsecret", Active: true,
+		ActionTypes: []domain.ActionType{domain.ActionDowngrade}})
+
+	dispatched, err := service.DispatchOutbox()
+	if err != nil || dispatched != 2 {
+		t.Fatalf("expected 2 dispatched events, got %d (%v)", dispatched, err)
+	}
+	if len(repo.deliveries) != 1 || repo.deliveries[0].OutboxEventID != 1 {
+		t.Fatalf("expected one delivery for the downgrade, got %+v", repo.deliveries)
+	}
+
+	service.DeliverDue()
+	delivery := repo.deliveries[0]
+	if delivery.Status != domain.DeliveryPending || delivery.Attempts != 1 || !delivery.NextAttemptAt.Equal(now.Add(time.Minute)) {
+		t.Fatalf("expected a retry in one minute, got %+v", delivery)
+	}
+
+	headers := sender.requests[0]
+	expected := SignWebhookPayload("topsecret", headers[WebhookTimestampHeader], []byte(sender.bodies[0]))
+	if headers[WebhookSignatureHeader] != expected || !strings.HasPrefix(expected, "sha256=") {
+		t.Errorf("unexpected signature %q", headers[WebhookSignatureHeader])
+	}
+	if !strings.Contains(sender.bodies[0], `"ticker":"AAPL"`) {
+		t.Errorf("expected stock payload, got %s", sender.bodies[0])
+	}
+
+	now = now.Add(time.Minute)
+	service.DeliverDue()
+	if repo.deliveries[0].Status != domain.DeliverySucceeded || repo.deliveries[0].DeliveredAt == nil {
+		t.Errorf("expected delivery to succeed on retry, got %+v", repo.deliveries[0])
+	}
+}
+
+func TestWebhookService_GivesUpAndReplays(t *testing.T) {
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	repo := &mockWebhookRepository{outbox: outboxFor(domain.Stock{ID: 1, Ticker: "AAPL"})}
+	sender := &fakeWebhookSender{statuses: []int{503}}
+	service := NewWebhookService(repo, sender, WebhookRetryPolicy{MaxAttempts: 2, Backoff: time.Second, MaxBackoff: time.Second})
+	service.now = func() time.Time { return now }
+	service.CreateSubscription(&domain.WebhookSubscription{UserID: "alice", URL: "http://localhost/hook", Active: true})
+
+	service.DispatchOutbox()
+	service.DeliverDue()
+	now = now.Add(time.Second)
+	service.DeliverDue()
+	if repo.deliveries[0].Status != domain.DeliveryFailed || repo.deliveries[0].Attempts != 2 {
+		t.Fatalf("expected delivery to fail after 2 attempts, got %+v", repo.deliveries[0])
+	}
+
+	if _, err := service.Replay("bob", 1); !errors.Is(err, ErrWebhookDeliveryNotFound) {
+		t.Errorf("expected other users not to replay, got %v", err)
+	}
+	replayed, err := service.Replay("alice", 1)
+	if err != nil || replayed.Status != domain.DeliveryPending || replayed.Attempts != 0 {
+		t.Fatalf("expected delivery to be queued again, got %+v (%v)", replayed, err)
+	}
+}
+
+func TestWebhookService_CreateSubscription(t *testing.T) {
+	repo := &mockWebhookRepository{}
+	service := NewWebhookService(repo, &fakeWebhookSender{}, DefaultWebhookRetryPolicy())
+
+	if err := service.CreateSubscription(&domain.WebhookSubscription{URL: "ftp://example.com"}); !errors.Is(err, ErrInvalidWebhook) {
+		t.Errorf("expected ErrInvalidWebhook, got %v", err)
+	}
+
+	subscription := &domain.WebhookSubscription{UserID: "alice", URL: "https://example.com/hook"}
+	if err := service.CreateSubscription(subscription); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(subscription.Secret) != 64 {
+		t.Errorf("expected a generated secret, got %q", subscription.Secret)
+	}
+	listed, _ := service.ListSubscriptions("alice")
+	if listed[0].Secret != "" {
+		t.Errorf("expected secret to be hidden when listing")
+	}
+}
+
+func TestWebhookRetryPolicy_Delay(t *testing.T) {
+	policy := WebhookRetryPolicy{Backoff: 10 * time.Second, MaxBackoff: time.Minute}
+	expected := []time.Duration{10 * time.Second, 20 * time.Second, 40 * time.Second, time.Minute, time.Minute}
+	for i, want := range expected {
+		if got := policy.delay(i + 1); got != want {
+			t.Errorf("attempt %d: expected %v, got %v", i+1, want, got)
+		}
+	}
+}
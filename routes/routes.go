@@ -8,25 +8,95 @@ import (
 	"github.com/gin-gonic/gin"
 )
 
-func SetupRouter(stockHandler *handlers.StockHandler) *gin.Engine {
+// Handlers agrupa los handlers que expone la API. Los opcionales en nil no registran rutas.
+type Handlers struct {
+	Stock     *handlers.StockHandler
+	Company   *handlers.CompanyHandler
+	Brokerage *handlers.BrokerageHandler
+	Snapshot  *handlers.SnapshotHandler
+	Watchlist *handlers.WatchlistHandler
+	Alert     *handlers.AlertHandler
+	Webhook   *handlers.WebhookHandler
+	Stream    *handlers.StreamHandler
+	Feed      *handlers.RecommendationFeedHandler
+	Upstream  *handlers.UpstreamHandler
+}
+
+func SetupRouter(h Handlers) *gin.Engine {
 	r := gin.Default()
 
 	// Configurar CORS para aceptar cualquier origen
 	r.Use(cors.New(cors.Config{
 		AllowOrigins:     []string{"*"}, // 🔥 Permitir cualquier origen
-		AllowMethods:     []string{"GET", "POST", "OPTIONS"},
-		AllowHeaders:     []string{"Origin", "Content-Type", "Authorization"},
-		ExposeHeaders:    []string{"Content-Length"},
+		AllowMethods:     []string{"GET", "POST", "PUT", "DELETE", "OPTIONS"},
+		AllowHeaders:     []string{"Origin", "Content-Type", "Authorization", "Last-Event-ID", handlers.UserIDHeader},
+		ExposeHeaders:    []string{"Content-Length", "Content-Disposition"},
 		AllowCredentials: false, // No permitir credenciales por seguridad
 	}))
 
 	log.Println("✅ CORS configurado para permitir cualquier origen.")
 
 	// Definir rutas
-	r.GET("/stocks", stockHandler.GetStocks)
-	r.POST("/stocks", stockHandler.PostStock)
-	r.GET("/stocks/recommendations", stockHandler.GetRecommendations)
-	r.GET("/stocks/:ticker", stockHandler.GetStockByTicker)
+	r.GET("/stocks", h.Stock.GetStocks)
+	r.POST("/stocks", h.Stock.PostStock)
+	r.GET("/stocks/recommendations", h.Stock.GetRecommendations)
+	r.GET("/stocks/recommendations/strategies", h.Stock.GetStrategies)
+	if h.Feed != nil {
+		r.GET("/stocks/recommendations/ws", h.Feed.GetRecommendationFeed)
+	}
+	r.GET("/stocks/:ticker", h.Stock.GetStockByTicker)
+	r.GET("/stocks/:ticker/history", h.Stock.GetStockHistory)
+	if h.Stream != nil {
+		r.GET("/stocks/stream", h.Stream.GetStockStream)
+	}
+	r.GET("/ratings/unknown", h.Stock.GetUnknownRatings)
+
+	if h.Company != nil {
+		r.GET("/companies", h.Company.GetCompanies)
+		r.GET("/companies/:symbol", h.Company.GetCompanyBySymbol)
+	}
+
+	if h.Brokerage != nil {
+		r.GET("/brokerages/accuracy", h.Brokerage.GetAccuracy)
+	}
+
+	if h.Snapshot != nil {
+		r.GET("/recommendations/snapshots", h.Snapshot.GetSnapshots)
+		r.GET("/recommendations/diff", h.Snapshot.GetDiff)
+	}
+
+	if h.Watchlist != nil {
+		watchlists := r.Group("/watchlists", handlers.RequireUser())
+		watchlists.GET("", h.Watchlist.GetWatchlists)
+		watchlists.POST("", h.Watchlist.PostWatchlist)
+		watchlists.GET("/:id", h.Watchlist.GetWatchlist)
+		watchlists.PUT("/:id", h.Watchlist.PutWatchlist)
+		watchlists.DELETE("/:id", h.Watchlist.DeleteWatchlist)
+		watchlists.GET("/:id/stocks", h.Watchlist.GetWatchlistStocks)
+		watchlists.GET("/:id/recommendations", h.Watchlist.GetWatchlistRecommendations)
+	}
+
+	if h.Alert != nil {
+		alerts := r.Group("/alerts", handlers.RequireUser())
+		alerts.GET("/rules", h.Alert.GetRules)
+		alerts.POST("/rules", h.Alert.PostRule)
+		alerts.GET("/rules/:id", h.Alert.GetRule)
+		alerts.DELETE("/rules/:id", h.Alert.DeleteRule)
+		alerts.GET("/events", h.Alert.GetEvents)
+	}
+
+	if h.Upstream != nil {
+		r.GET("/upstream/status", h.Upstream.GetUpstreamStatus)
+	}
+
+	if h.Webhook != nil {
+		webhooks := r.Group("/webhooks", handlers.RequireUser())
+		webhooks.GET("", h.Webhook.GetSubscriptions)
+		webhooks.POST("", h.Webhook.PostSubscription)
+		webhooks.DELETE("/:id", h.Webhook.DeleteSubscription)
+		webhooks.GET("/:id/deliveries", h.Webhook.GetDeliveries)
+		webhooks.POST("/deliveries/:id/replay", h.Webhook.PostReplay)
+	}
 
 	return r
 }